@@ -110,3 +110,21 @@ type File struct {
 
 	Data io.Reader `json:"data"`
 }
+
+// Read reads from Data, so a File can be streamed directly.
+func (f *File) Read(p []byte) (int, error) {
+	if f.Data == nil {
+		return 0, io.EOF
+	}
+
+	return f.Data.Read(p)
+}
+
+// Close releases Data when it holds a resource, such as a downloaded temporary file.
+func (f *File) Close() error {
+	if closer, ok := f.Data.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const docsUsage = `usage: wakflo docs [-format markdown|json] [-o file] [dir]
+
+Generate the reference documentation of the integration in dir, describing
+its actions, triggers, inputs, auth requirements and sample outputs.
+
+`
+
+func runDocs(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "markdown", "output format: markdown or json")
+	output := fs.String("o", "", "file to write instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprint(stderr, docsUsage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil || fs.NArg() > 1 {
+		return 2
+	}
+
+	if *format != "markdown" && *format != "json" {
+		fmt.Fprintf(stderr, "docs: unknown format %q\n", *format)
+		return 2
+	}
+
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+
+	if *output == "" {
+		return runHost(dir, []string{"docs", *format}, nil, stdout, stderr)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(stderr, "docs: %s\n", err)
+		return 1
+	}
+
+	code := runHost(dir, []string{"docs", *format}, nil, f, stderr)
+	if err := f.Close(); err != nil && code == 0 {
+		fmt.Fprintf(stderr, "docs: %s\n", err)
+		return 1
+	}
+
+	return code
+}
@@ -0,0 +1,161 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/wakflo/go-sdk/v2/flo"
+)
+
+// hostDir is where the generated host program is written, relative to the
+// integration directory. Directories starting with a dot are skipped by ./...
+const hostDir = ".wakflo/host"
+
+const hostMain = `// Code generated by wakflo. DO NOT EDIT.
+
+package main
+
+import (
+	integration %q
+
+	"github.com/wakflo/go-sdk/v2/host"
+)
+
+func main() {
+	host.Main(integration.Integration)
+}
+`
+
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	dir, ok := parseDirArgs("validate", args, stderr)
+	if !ok {
+		return 2
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "flo.toml"))
+	if err != nil {
+		fmt.Fprintf(stderr, "validate: %s\n", err)
+		return 1
+	}
+
+	if err := flo.Validate(content); err != nil {
+		var verrs flo.ValidationErrors
+		if !errors.As(err, &verrs) {
+			fmt.Fprintf(stderr, "validate: %s\n", err)
+			return 1
+		}
+
+		for _, verr := range verrs {
+			fmt.Fprintf(stderr, "%s:%d:%d: %s\n", filepath.Join(dir, "flo.toml"), verr.Line, verr.Column, verr.Message)
+		}
+
+		return 1
+	}
+
+	return runHost(dir, []string{"validate"}, nil, stdout, stderr)
+}
+
+func runDescribe(args []string, stdout, stderr io.Writer) int {
+	dir, ok := parseDirArgs("describe", args, stderr)
+	if !ok {
+		return 2
+	}
+
+	return runHost(dir, []string{"describe"}, nil, stdout, stderr)
+}
+
+func parseDirArgs(name string, args []string, stderr io.Writer) (string, bool) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: wakflo %s [dir]\n", name)
+	}
+
+	if err := fs.Parse(args); err != nil || fs.NArg() > 1 {
+		return "", false
+	}
+
+	if fs.NArg() == 1 {
+		return fs.Arg(0), true
+	}
+
+	return ".", true
+}
+
+// runHost builds the integration in dir together with a generated host
+// program and runs it with args. The integration package must export an
+// Integration variable.
+func runHost(dir string, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	mod, err := findModule(dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "wakflo: %s\n", err)
+		return 1
+	}
+
+	importPath, err := mod.importPath(dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "wakflo: %s\n", err)
+		return 1
+	}
+
+	mainFile := filepath.Join(dir, hostDir, "main.go")
+	if err := os.MkdirAll(filepath.Dir(mainFile), 0o755); err != nil {
+		fmt.Fprintf(stderr, "wakflo: %s\n", err)
+		return 1
+	}
+	defer func() {
+		os.RemoveAll(filepath.Join(dir, hostDir))
+		os.Remove(filepath.Join(dir, filepath.Dir(hostDir)))
+	}()
+
+	if err := os.WriteFile(mainFile, []byte(fmt.Sprintf(hostMain, importPath)), 0o644); err != nil {
+		fmt.Fprintf(stderr, "wakflo: %s\n", err)
+		return 1
+	}
+
+	bin := filepath.Join(dir, hostDir, "host")
+	build := exec.Command("go", "build", "-o", filepath.Join(hostDir, "host"), "./"+hostDir)
+	build.Dir = dir
+	build.Stdout = stderr
+	build.Stderr = stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintf(stderr, "wakflo: build %s: %s\n", importPath, err)
+		return 1
+	}
+
+	cmd := exec.Command(bin, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+
+		fmt.Fprintf(stderr, "wakflo: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,89 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command wakflo scaffolds, validates and describes Wakflo integrations.
+//
+// Usage:
+//
+//	wakflo init [flags] <dir>          scaffold a new integration
+//	wakflo add action [flags] <id>     add an action stub
+//	wakflo add trigger [flags] <id>    add a trigger stub
+//	wakflo validate [dir]              check flo.toml, metadata and schemas
+//	wakflo describe [dir]              print the IntegrationDefinition as JSON
+//	wakflo docs [flags] [dir]          generate Markdown or JSON reference docs
+//	wakflo run action <dir> <action>   perform an action with local input and credentials
+//	wakflo run trigger <dir> <trigger> start, execute and stop a trigger
+//	wakflo run options <dir> <op> <field> list the options of a dynamic field
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `wakflo is a tool for developing Wakflo integrations.
+
+Usage:
+
+	wakflo <command> [arguments]
+
+Commands:
+
+	init        scaffold a new integration
+	add         add an action or trigger stub to an integration
+	validate    check flo.toml, metadata and schemas of an integration
+	describe    print the integration definition as JSON
+	docs        generate reference documentation for an integration
+	run         run an action, trigger or dynamic options function locally
+
+Run "wakflo <command> -h" for more information about a command.
+`
+
+// command is a wakflo subcommand.
+type command func(args []string, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"init":     runInit,
+	"add":      runAdd,
+	"validate": runValidate,
+	"describe": runDescribe,
+	"docs":     runDocs,
+	"run":      runRun,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "wakflo: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	return cmd(args[1:], stdout, stderr)
+}
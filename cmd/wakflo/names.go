@@ -0,0 +1,111 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// words splits an identifier such as "send-email", "send_email" or "SendEmail"
+// into lower-case words.
+func words(s string) []string {
+	var out []string
+	var cur []rune
+
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+
+	return out
+}
+
+// snakeCase converts s to the snake_case form used for action and trigger IDs.
+func snakeCase(s string) string {
+	return strings.Join(words(s), "_")
+}
+
+// kebabCase converts s to the kebab-case form used for integration names.
+func kebabCase(s string) string {
+	return strings.Join(words(s), "-")
+}
+
+// pascalCase converts s to an exported Go identifier.
+func pascalCase(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+
+	return b.String()
+}
+
+// typeName converts s to an exported Go identifier, prefixing names that
+// start with a digit, as packageName does.
+func typeName(s, prefix string) string {
+	name := pascalCase(s)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = prefix + name
+	}
+
+	return name
+}
+
+// camelCase converts s to an unexported Go identifier.
+func camelCase(s string) string {
+	p := pascalCase(s)
+	if p == "" {
+		return p
+	}
+
+	return strings.ToLower(p[:1]) + p[1:]
+}
+
+// titleCase converts s to space-separated title case for display names.
+func titleCase(s string) string {
+	ws := words(s)
+	for i, w := range ws {
+		ws[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+
+	return strings.Join(ws, " ")
+}
+
+// packageName converts s to a valid Go package name.
+func packageName(s string) string {
+	name := strings.Join(words(s), "")
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "integration" + name
+	}
+
+	return name
+}
@@ -0,0 +1,232 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables read by wakflo run.
+const (
+	// envInput holds the step input as JSON
+	envInput = "WAKFLO_INPUT"
+
+	// envAuth holds the auth context as JSON
+	envAuth = "WAKFLO_AUTH"
+
+	// envAuthPrefix prefixes individual credentials, e.g. WAKFLO_AUTH_ACCESS_TOKEN
+	envAuthPrefix = "WAKFLO_AUTH_"
+
+	// envEnvironment holds the execution environment, e.g. dev or prod
+	envEnvironment = "WAKFLO_ENV"
+)
+
+// authEnvFields maps WAKFLO_AUTH_* suffixes to AuthContext JSON fields. Other
+// suffixes are stored in the auth context's extra parameters.
+var authEnvFields = map[string]string{
+	"ACCESS_TOKEN": "accessToken",
+	"TOKEN_TYPE":   "tokenType",
+	"USERNAME":     "username",
+	"PASSWORD":     "password",
+	"SECRET":       "secret",
+	"KEY":          "key",
+	"SCOPES":       "scopes",
+}
+
+// runRequest mirrors host.RunRequest.
+type runRequest struct {
+	Input       map[string]any `json:"input,omitempty"`
+	Auth        map[string]any `json:"auth,omitempty"`
+	LastRun     *time.Time     `json:"lastRun,omitempty"`
+	Filter      *runFilter     `json:"filter,omitempty"`
+	Environment string         `json:"environment,omitempty"`
+}
+
+type runFilter struct {
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+	FilterTerm string `json:"filterTerm"`
+}
+
+const runUsage = `usage:
+	wakflo run action [flags] <dir> <action>
+	wakflo run trigger [flags] <dir> <trigger>
+	wakflo run options [flags] <dir> <action-or-trigger> <field>
+
+Input is read from -input or $WAKFLO_INPUT. Credentials are read from -auth or
+$WAKFLO_AUTH, then overridden by $WAKFLO_AUTH_ACCESS_TOKEN, $WAKFLO_AUTH_USERNAME,
+$WAKFLO_AUTH_PASSWORD, $WAKFLO_AUTH_KEY, $WAKFLO_AUTH_SECRET, $WAKFLO_AUTH_TOKEN_TYPE
+and $WAKFLO_AUTH_SCOPES (comma-separated). Any other $WAKFLO_AUTH_<NAME> is passed
+as an extra parameter named <name>.
+
+The execution environment is read from -env or $WAKFLO_ENV and defaults to dev.
+Outside prod, action and trigger outputs are checked against their declared
+output schema.
+`
+
+func runRun(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || (args[0] != "action" && args[0] != "trigger" && args[0] != "options") {
+		fmt.Fprint(stderr, runUsage)
+		return 2
+	}
+
+	kind := args[0]
+	fs := flag.NewFlagSet("run "+kind, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	inputFile := fs.String("input", "", "JSON file with the step input (- for stdin)")
+	authFile := fs.String("auth", "", "JSON file with the auth context")
+	lastRun := fs.String("last-run", "", "RFC 3339 time of the previous trigger run")
+	offset := fs.Int("offset", 0, "offset of the first option to return")
+	limit := fs.Int("limit", 0, "maximum number of options to return")
+	search := fs.String("search", "", "filter term for dynamic options")
+	environment := fs.String("env", "", "execution environment (dev, test, debug or prod)")
+	fs.Usage = func() {
+		fmt.Fprint(stderr, runUsage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	want := 2
+	if kind == "options" {
+		want = 3
+	}
+
+	if fs.NArg() != want {
+		fs.Usage()
+		return 2
+	}
+
+	req, err := buildRunRequest(*inputFile, *authFile, os.Environ(), os.Stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "run %s: %s\n", kind, err)
+		return 1
+	}
+
+	if *environment != "" {
+		req.Environment = *environment
+	}
+
+	if *lastRun != "" {
+		t, err := time.Parse(time.RFC3339, *lastRun)
+		if err != nil {
+			fmt.Fprintf(stderr, "run %s: invalid -last-run: %s\n", kind, err)
+			return 2
+		}
+		req.LastRun = &t
+	}
+
+	if kind == "options" {
+		req.Filter = &runFilter{Offset: *offset, Limit: *limit, FilterTerm: *search}
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		fmt.Fprintf(stderr, "run %s: %s\n", kind, err)
+		return 1
+	}
+
+	hostArgs := append([]string{"run-" + kind}, fs.Args()[1:]...)
+	return runHost(fs.Arg(0), hostArgs, bytes.NewReader(body), stdout, stderr)
+}
+
+// buildRunRequest assembles the input, auth context and execution environment
+// from files and the process environment.
+func buildRunRequest(inputFile, authFile string, environ []string, stdin io.Reader) (*runRequest, error) {
+	env := map[string]string{}
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+
+	req := &runRequest{Input: map[string]any{}, Auth: map[string]any{}, Environment: env[envEnvironment]}
+
+	switch {
+	case inputFile == "-":
+		if err := json.NewDecoder(stdin).Decode(&req.Input); err != nil {
+			return nil, fmt.Errorf("decode input from stdin: %w", err)
+		}
+	case inputFile != "":
+		if err := decodeFile(inputFile, &req.Input); err != nil {
+			return nil, err
+		}
+	case env[envInput] != "":
+		if err := json.Unmarshal([]byte(env[envInput]), &req.Input); err != nil {
+			return nil, fmt.Errorf("decode $%s: %w", envInput, err)
+		}
+	}
+
+	switch {
+	case authFile != "":
+		if err := decodeFile(authFile, &req.Auth); err != nil {
+			return nil, err
+		}
+	case env[envAuth] != "":
+		if err := json.Unmarshal([]byte(env[envAuth]), &req.Auth); err != nil {
+			return nil, fmt.Errorf("decode $%s: %w", envAuth, err)
+		}
+	}
+
+	for k, v := range env {
+		name, ok := strings.CutPrefix(k, envAuthPrefix)
+		if !ok || name == "" {
+			continue
+		}
+
+		field, known := authEnvFields[name]
+		switch {
+		case field == "scopes":
+			req.Auth[field] = strings.Split(v, ",")
+		case known:
+			req.Auth[field] = v
+		default:
+			extra, _ := req.Auth["extra"].(map[string]any)
+			if extra == nil {
+				extra = map[string]any{}
+				req.Auth["extra"] = extra
+			}
+			extra[strings.ToLower(name)] = v
+		}
+	}
+
+	if len(req.Auth) == 0 {
+		req.Auth = nil
+	}
+
+	return req, nil
+}
+
+func decodeFile(name string, v any) error {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("decode %s: %w", name, err)
+	}
+
+	return nil
+}
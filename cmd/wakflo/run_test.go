@@ -0,0 +1,66 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRunRequest(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.json")
+	auth := filepath.Join(dir, "auth.json")
+	require.NoError(t, os.WriteFile(input, []byte(`{"name":"Ada"}`), 0o644))
+	require.NoError(t, os.WriteFile(auth, []byte(`{"accessToken":"from-file","username":"ada"}`), 0o644))
+
+	env := []string{
+		"WAKFLO_INPUT={\"name\":\"ignored\"}",
+		"WAKFLO_AUTH_ACCESS_TOKEN=from-env",
+		"WAKFLO_AUTH_SCOPES=read,write",
+		"WAKFLO_AUTH_TENANT_ID=acme",
+		"HOME=/root",
+	}
+
+	req, err := buildRunRequest(input, auth, env, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Ada"}, req.Input)
+	require.Equal(t, map[string]any{
+		"accessToken": "from-env",
+		"username":    "ada",
+		"scopes":      []string{"read", "write"},
+		"extra":       map[string]any{"tenant_id": "acme"},
+	}, req.Auth)
+
+	require.Empty(t, req.Environment)
+
+	req, err = buildRunRequest("", "", []string{`WAKFLO_INPUT={"name":"env"}`, `WAKFLO_AUTH={"key":"k"}`, "WAKFLO_ENV=prod"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "env"}, req.Input)
+	require.Equal(t, map[string]any{"key": "k"}, req.Auth)
+	require.Equal(t, "prod", req.Environment)
+
+	req, err = buildRunRequest("-", "", nil, strings.NewReader(`{"name":"stdin"}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "stdin"}, req.Input)
+	require.Nil(t, req.Auth)
+
+	_, err = buildRunRequest("", "", []string{"WAKFLO_INPUT=not json"}, nil)
+	require.ErrorContains(t, err, "WAKFLO_INPUT")
+}
@@ -0,0 +1,453 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"toml":    tomlString,
+	"comment": commentText,
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// Markers in the generated integration file after which add inserts new
+// constructors.
+const (
+	actionsMarker  = "// wakflo:actions"
+	triggersMarker = "// wakflo:triggers"
+)
+
+// operationData is the template data for an action or trigger.
+type operationData struct {
+	ID          string
+	Type        string
+	Var         string
+	DisplayName string
+	Description string
+}
+
+func newOperationData(id, description string) operationData {
+	typ := typeName(id, "Op")
+	return operationData{
+		ID:          snakeCase(id),
+		Type:        typ,
+		Var:         camelCase(typ),
+		DisplayName: titleCase(id),
+		Description: description,
+	}
+}
+
+// integrationData is the template data for a new integration.
+type integrationData struct {
+	Name        string
+	DisplayName string
+	Package     string
+	Type        string
+	ImportPath  string
+	Author      string
+	Action      operationData
+	Trigger     operationData
+}
+
+// moduleInfo locates the Go module containing dir.
+type moduleInfo struct {
+	Root string
+	Path string
+}
+
+// findModule walks up from dir looking for go.mod.
+func findModule(dir string) (*moduleInfo, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for d := abs; ; d = filepath.Dir(d) {
+		f, err := os.Open(filepath.Join(d, "go.mod"))
+		if err == nil {
+			modPath := readModulePath(f)
+			f.Close()
+			if modPath == "" {
+				return nil, fmt.Errorf("%s: missing module directive", filepath.Join(d, "go.mod"))
+			}
+
+			return &moduleInfo{Root: d, Path: modPath}, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		if filepath.Dir(d) == d {
+			return nil, fmt.Errorf("no go.mod found in %s or any parent directory", abs)
+		}
+	}
+}
+
+func readModulePath(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+
+	return ""
+}
+
+// importPath returns the import path of the package in dir.
+func (m *moduleInfo) importPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(m.Root, abs)
+	if err != nil {
+		return "", err
+	}
+
+	if rel == "." {
+		return m.Path, nil
+	}
+
+	return path.Join(m.Path, filepath.ToSlash(rel)), nil
+}
+
+// tomlString quotes s as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// commentText flattens s onto one line for use in a Go comment.
+func commentText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// render executes the named template. Go sources are gofmt'ed.
+func render(name string, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(strings.TrimSuffix(name, ".tmpl"), ".go") {
+		return buf.Bytes(), nil
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", name, err)
+	}
+
+	return src, nil
+}
+
+// writeNew writes content to name, refusing to overwrite an existing file.
+func writeNew(name string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%s already exists", name)
+		}
+
+		return err
+	}
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// scaffold writes a new integration into dir.
+func scaffold(dir string, data integrationData) ([]string, error) {
+	files := []struct {
+		tmpl string
+		name string
+		data any
+	}{
+		{"flo.toml.tmpl", "flo.toml", data},
+		{"README.md.tmpl", "README.md", data},
+		{"gitignore.tmpl", ".gitignore", data},
+		{"lib.go.tmpl", "lib.go", data},
+		{"lib_test.go.tmpl", "lib_test.go", data},
+		{"action.go.tmpl", filepath.Join("actions", data.Action.ID+".go"), data.Action},
+		{"action_test.go.tmpl", filepath.Join("actions", data.Action.ID+"_test.go"), data.Action},
+		{"trigger.go.tmpl", filepath.Join("triggers", data.Trigger.ID+".go"), data.Trigger},
+		{"trigger_test.go.tmpl", filepath.Join("triggers", data.Trigger.ID+"_test.go"), data.Trigger},
+	}
+
+	// render everything first so a template error leaves no partial scaffold
+	contents := make([][]byte, len(files))
+	for i, f := range files {
+		content, err := render(f.tmpl, f.data)
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = content
+	}
+
+	written := make([]string, 0, len(files))
+	for i, f := range files {
+		name := filepath.Join(dir, f.name)
+		if err := writeNew(name, contents[i]); err != nil {
+			return written, err
+		}
+		written = append(written, name)
+	}
+
+	return written, nil
+}
+
+func runInit(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	name := fs.String("name", "", "integration name (defaults to the directory name)")
+	displayName := fs.String("display-name", "", "human-readable integration name")
+	author := fs.String("author", "Wakflo", "author listed in flo.toml")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: wakflo init [flags] <dir>")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	dir := fs.Arg(0)
+	if *name == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(stderr, "init: %s\n", err)
+			return 1
+		}
+		*name = filepath.Base(abs)
+	}
+
+	if *displayName == "" {
+		*displayName = titleCase(*name)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fmt.Fprintf(stderr, "init: %s\n", err)
+		return 1
+	}
+
+	mod, err := findModule(dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "init: %s\n", err)
+		return 1
+	}
+
+	importPath, err := mod.importPath(dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "init: %s\n", err)
+		return 1
+	}
+
+	data := integrationData{
+		Name:        kebabCase(*name),
+		DisplayName: *displayName,
+		Package:     packageName(*name),
+		Type:        typeName(*name, "Integration"),
+		ImportPath:  importPath,
+		Author:      *author,
+		Action:      newOperationData("say_hello", "Returns a greeting for the given name."),
+		Trigger:     newOperationData("new_item", "Polls for items created since the last run."),
+	}
+
+	written, err := scaffold(dir, data)
+	for _, name := range written {
+		fmt.Fprintf(stdout, "created %s\n", name)
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "init: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+func runAdd(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || (args[0] != "action" && args[0] != "trigger") {
+		fmt.Fprintln(stderr, "usage: wakflo add action|trigger [flags] <id>")
+		return 2
+	}
+
+	kind := args[0]
+	fs := flag.NewFlagSet("add "+kind, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("dir", ".", "integration directory")
+	description := fs.String("description", "", "description of the "+kind)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: wakflo add %s [flags] <id>\n", kind)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if fs.NArg() != 1 || snakeCase(fs.Arg(0)) == "" {
+		fs.Usage()
+		return 2
+	}
+
+	written, err := addOperation(*dir, kind, fs.Arg(0), *description)
+	for _, name := range written {
+		fmt.Fprintf(stdout, "created %s\n", name)
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "add %s: %s\n", kind, err)
+		return 1
+	}
+
+	return 0
+}
+
+// addOperation generates an action or trigger stub and registers it with the
+// integration's Actions or Triggers list.
+func addOperation(dir, kind, id, description string) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(dir, "flo.toml")); err != nil {
+		return nil, fmt.Errorf("%s is not an integration directory: %w", dir, err)
+	}
+
+	op := newOperationData(id, description)
+	if op.Description == "" {
+		op.Description = op.DisplayName + "."
+	}
+
+	subdir, marker, ctor := "actions", actionsMarker, "actions.New"+op.Type+"Action(),"
+	if kind == "trigger" {
+		subdir, marker, ctor = "triggers", triggersMarker, "triggers.New"+op.Type+"Trigger(),"
+	}
+
+	suffixes := []string{".go", "_test.go"}
+	contents := make([][]byte, len(suffixes))
+	for i, suffix := range suffixes {
+		content, err := render(kind+suffix+".tmpl", op)
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = content
+	}
+
+	var written []string
+	for i, suffix := range suffixes {
+		name := filepath.Join(dir, subdir, op.ID+suffix)
+		if err := writeNew(name, contents[i]); err != nil {
+			return written, err
+		}
+		written = append(written, name)
+	}
+
+	if err := register(dir, marker, ctor); err != nil {
+		return written, fmt.Errorf("%w; add %s to the integration manually", err, ctor)
+	}
+
+	return written, nil
+}
+
+// register inserts ctor before marker in the Go file of dir that contains it.
+func register(dir, marker, ctor string) error {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range matches {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		idx := bytes.Index(src, []byte(marker))
+		if idx < 0 {
+			continue
+		}
+
+		var buf bytes.Buffer
+		buf.Write(src[:idx])
+		buf.WriteString(ctor + "\n")
+		buf.Write(src[idx:])
+
+		out, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("format %s: %w", name, err)
+		}
+
+		return os.WriteFile(name, out, 0o644)
+	}
+
+	return fmt.Errorf("no %q marker found in %s", marker, dir)
+}
@@ -0,0 +1,209 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wakflo/go-sdk/v2/flo"
+)
+
+func newModule(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/integrations\n\ngo 1.24\n"), 0o644))
+	return root
+}
+
+func TestInitScaffoldsIntegration(t *testing.T) {
+	root := newModule(t)
+	dir := filepath.Join(root, "acme-crm")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"init", "-author", "Jane Doe", dir}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	for _, name := range []string{
+		"flo.toml", "README.md", ".gitignore", "lib.go", "lib_test.go",
+		"actions/say_hello.go", "actions/say_hello_test.go",
+		"triggers/new_item.go", "triggers/new_item_test.go",
+	} {
+		require.FileExists(t, filepath.Join(dir, name))
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "flo.toml"))
+	require.NoError(t, err)
+
+	file, err := flo.Parse(content)
+	require.NoError(t, err)
+	require.Equal(t, "acme-crm", file.Integration.Name)
+	require.Equal(t, "Acme Crm", file.Integration.DisplayName)
+	require.Equal(t, []string{"Jane Doe"}, file.Integration.Authors)
+
+	lib := parseGo(t, filepath.Join(dir, "lib.go"))
+	require.Contains(t, lib, `package acmecrm`)
+	require.Contains(t, lib, `"example.com/integrations/acme-crm/actions"`)
+	require.Contains(t, lib, `var Integration = sdk.Register(NewAcmeCrm())`)
+
+	parseGo(t, filepath.Join(dir, "actions", "say_hello.go"))
+	parseGo(t, filepath.Join(dir, "triggers", "new_item_test.go"))
+
+	code = run([]string{"init", dir}, &stdout, &stderr)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr.String(), "already exists")
+}
+
+func TestAddRegistersStubs(t *testing.T) {
+	root := newModule(t)
+	dir := filepath.Join(root, "acme")
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run([]string{"init", dir}, &stdout, &stderr), stderr.String())
+
+	require.Equal(t, 0, run([]string{"add", "action", "-dir", dir, "create-contact"}, &stdout, &stderr), stderr.String())
+	require.Equal(t, 0, run([]string{"add", "trigger", "-dir", dir, "ContactUpdated"}, &stdout, &stderr), stderr.String())
+
+	action := parseGo(t, filepath.Join(dir, "actions", "create_contact.go"))
+	require.Contains(t, action, `ID:          "create_contact"`)
+	require.Contains(t, action, `func NewCreateContactAction() sdk.Action`)
+
+	trigger := parseGo(t, filepath.Join(dir, "triggers", "contact_updated.go"))
+	require.Contains(t, trigger, `ID:          "contact_updated"`)
+
+	lib := parseGo(t, filepath.Join(dir, "lib.go"))
+	require.Contains(t, lib, "actions.NewCreateContactAction(),\n\t\t"+actionsMarker)
+	require.Contains(t, lib, "triggers.NewContactUpdatedTrigger(),\n\t\t"+triggersMarker)
+
+	stderr.Reset()
+	require.Equal(t, 1, run([]string{"add", "action", "-dir", dir, "create_contact"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "already exists")
+	require.Equal(t, 1, strings.Count(parseGo(t, filepath.Join(dir, "lib.go")), "NewCreateContactAction"))
+}
+
+func TestScaffoldEscapesStrings(t *testing.T) {
+	root := newModule(t)
+	dir := filepath.Join(root, "acme")
+	const name, author, description = `Acme "CRM" \ Co`, `Jane "JD" Doe\`, `Creates a "contact" in C:\crm`
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run([]string{"init", "-display-name", name, "-author", author, dir}, &stdout, &stderr), stderr.String())
+	require.Equal(t, 0, run([]string{"add", "action", "-dir", dir, "-description", description, "create_contact"}, &stdout, &stderr), stderr.String())
+	require.Equal(t, 0, run([]string{"add", "trigger", "-dir", dir, "-description", description, "contact_created"}, &stdout, &stderr), stderr.String())
+
+	content, err := os.ReadFile(filepath.Join(dir, "flo.toml"))
+	require.NoError(t, err)
+
+	file, err := flo.Parse(content)
+	require.NoError(t, err)
+	require.Equal(t, name, file.Integration.DisplayName)
+	require.Equal(t, name+" integration for Wakflo.", file.Integration.Description)
+	require.Equal(t, []string{author}, file.Integration.Authors)
+
+	require.Contains(t, parseGo(t, filepath.Join(dir, "actions", "create_contact.go")), `Description: "Creates a \"contact\" in C:\\crm",`)
+	require.Contains(t, parseGo(t, filepath.Join(dir, "triggers", "contact_created.go")), `Description: "Creates a \"contact\" in C:\\crm",`)
+}
+
+func TestInitDigitName(t *testing.T) {
+	root := newModule(t)
+	dir := filepath.Join(root, "1password")
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run([]string{"init", dir}, &stdout, &stderr), stderr.String())
+	lib := parseGo(t, filepath.Join(dir, "lib.go"))
+	require.Contains(t, lib, `package integration1password`)
+	require.Contains(t, lib, `type Integration1password struct{}`)
+
+	require.Equal(t, 0, run([]string{"add", "action", "-dir", dir, "2fa_code"}, &stdout, &stderr), stderr.String())
+	require.Contains(t, parseGo(t, filepath.Join(dir, "actions", "2fa_code.go")), `func NewOp2faCodeAction() sdk.Action`)
+}
+
+func TestScaffoldRendersBeforeWriting(t *testing.T) {
+	dir := t.TempDir()
+	data := integrationData{Name: "acme", Package: "acme", Type: "1Acme"}
+
+	_, err := scaffold(dir, data)
+	require.Error(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+// TestScaffoldTypeChecks generates an integration inside this module and vets
+// it, so the templates are checked against the current SDK API.
+func TestScaffoldTypeChecks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	require.NoError(t, os.MkdirAll("testdata", 0o755))
+	dir, err := os.MkdirTemp("testdata", "scaffold-")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"init", "-display-name", "Acme\nfunc init() { panic(1) }", dir}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Equal(t, 0, run([]string{"add", "action", "-dir", dir, "-description", `Says "hi"`, "create_contact"}, &stdout, &stderr), stderr.String())
+	require.Equal(t, 0, run([]string{"add", "trigger", "-dir", dir, "contact_updated"}, &stdout, &stderr), stderr.String())
+
+	require.NotContains(t, parseGo(t, filepath.Join(dir, "lib.go")), "\nfunc init()")
+
+	cmd := exec.Command(goBin, "vet", ".", "./actions", "./triggers")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestAddOutsideIntegration(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 1, run([]string{"add", "action", "-dir", t.TempDir(), "foo"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "not an integration directory")
+}
+
+func TestNames(t *testing.T) {
+	require.Equal(t, "send_email", snakeCase("SendEmail"))
+	require.Equal(t, "send_email", snakeCase("send-email"))
+	require.Equal(t, "SendEmail", pascalCase("send_email"))
+	require.Equal(t, "sendEmail", camelCase("send email"))
+	require.Equal(t, "Send Email", titleCase("send_email"))
+	require.Equal(t, "googlesheets", packageName("google-sheets"))
+	require.Equal(t, "integration3d", packageName("3d"))
+}
+
+func parseGo(t *testing.T, name string) string {
+	t.Helper()
+
+	src, err := os.ReadFile(name)
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), name, src, parser.AllErrors)
+	require.NoError(t, err)
+	return string(src)
+}
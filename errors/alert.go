@@ -0,0 +1,266 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"strings"
+	"time"
+)
+
+// Severity ranks how urgently an alert needs attention.
+type Severity string
+
+const (
+	// SeverityInfo is for failures that need no action, such as cancellations
+	SeverityInfo Severity = "info"
+
+	// SeverityWarning is for failures that are expected to resolve themselves
+	// or that the user caused
+	SeverityWarning Severity = "warning"
+
+	// SeverityError is for failures that need someone to fix something
+	SeverityError Severity = "error"
+
+	// SeverityCritical is for crashes
+	SeverityCritical Severity = "critical"
+)
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	case SeverityError:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether s is as severe as min or more.
+func (s Severity) AtLeast(min Severity) bool {
+	return s.rank() >= min.rank()
+}
+
+// SeverityOf derives the severity of err from its category: cancellations are
+// info, transient and user-caused failures warnings, and the rest errors.
+func SeverityOf(err error) Severity {
+	switch CategoryOf(err) {
+	case CategoryCanceled:
+		return SeverityInfo
+	case CategoryRateLimit, CategoryUpstream, CategoryTimeout, CategoryValidation, CategoryNotFound, CategoryConflict:
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
+// Alert describes a failure of an action or trigger run.
+type Alert struct {
+	// Err is the failure
+	Err error
+
+	// Severity defaults to SeverityOf(Err)
+	Severity Severity
+
+	// Integration is the ID of the integration that failed
+	Integration string
+
+	// Operation is the ID of the action or trigger that failed
+	Operation string
+
+	// StepID is the workflow step that failed
+	StepID string
+
+	// RunID is the workflow run the step belongs to
+	RunID string
+
+	// WorkflowID is the workflow the run belongs to
+	WorkflowID string
+
+	// ProjectID is the project the workflow belongs to
+	ProjectID string
+
+	// Data holds extra context
+	Data map[string]any
+
+	// Time defaults to the time the alert was sent
+	Time time.Time
+
+	// Count is the number of failures the alert stands for; zero means one
+	Count int
+
+	// Dropped is the number of alerts rate limiting discarded before this one
+	Dropped int
+
+	// User identifies who the run belongs to
+	User string
+
+	// Breadcrumbs are the last log entries of the run, oldest first
+	Breadcrumbs []Breadcrumb
+}
+
+// Breadcrumb is a log entry written during a run before it failed.
+type Breadcrumb struct {
+	// Time is when the entry was written
+	Time time.Time `json:"time"`
+
+	// Level is the log level, such as "INFO" or "WARN"
+	Level string `json:"level"`
+
+	// Message is the log message
+	Message string `json:"message"`
+
+	// Fields holds the structured data of the entry
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// Code returns the code of Err, or its category when it has none.
+func (a Alert) Code() string {
+	e := As(a.Err)
+	if e == nil {
+		return ""
+	}
+	if e.Code != "" {
+		return e.Code
+	}
+
+	return string(e.Category)
+}
+
+// Key identifies repeated failures of the same step: alerts with equal keys
+// are grouped together.
+func (a Alert) Key() string {
+	return strings.Join([]string{a.Integration, a.Operation, a.StepID, a.Code()}, "\x00")
+}
+
+// normalized fills in the defaults of a.
+func (a Alert) normalized(now time.Time) Alert {
+	if a.Severity == "" {
+		a.Severity = SeverityOf(a.Err)
+	}
+	if a.Time.IsZero() {
+		a.Time = now
+	}
+	if a.Count < 1 {
+		a.Count = 1
+	}
+
+	return a
+}
+
+func (a Alert) MarshalJSON() ([]byte, error) {
+	a = a.normalized(time.Now())
+
+	var message string
+	if a.Err != nil {
+		message = a.Err.Error()
+	}
+
+	return json.Marshal(struct {
+		Time        time.Time      `json:"time"`
+		Severity    Severity       `json:"severity"`
+		Integration string         `json:"integration,omitempty"`
+		Operation   string         `json:"operation,omitempty"`
+		StepID      string         `json:"stepId,omitempty"`
+		RunID       string         `json:"runId,omitempty"`
+		WorkflowID  string         `json:"workflowId,omitempty"`
+		ProjectID   string         `json:"projectId,omitempty"`
+		Error       string         `json:"error"`
+		Category    Category       `json:"category"`
+		Code        string         `json:"code,omitempty"`
+		Retryable   bool           `json:"retryable"`
+		Count       int            `json:"count"`
+		Dropped     int            `json:"dropped,omitempty"`
+		User        string         `json:"user,omitempty"`
+		Data        map[string]any `json:"data,omitempty"`
+		Breadcrumbs []Breadcrumb   `json:"breadcrumbs,omitempty"`
+	}{
+		Time:        a.Time,
+		Severity:    a.Severity,
+		Integration: a.Integration,
+		Operation:   a.Operation,
+		StepID:      a.StepID,
+		RunID:       a.RunID,
+		WorkflowID:  a.WorkflowID,
+		ProjectID:   a.ProjectID,
+		Error:       message,
+		Category:    CategoryOf(a.Err),
+		Code:        a.Code(),
+		Retryable:   IsRetryable(a.Err),
+		Count:       a.Count,
+		Dropped:     a.Dropped,
+		User:        a.User,
+		Data:        a.Data,
+		Breadcrumbs: a.Breadcrumbs,
+	})
+}
+
+// Alerter delivers alerts to a backend. Implementations must be safe for
+// concurrent use; wrap them with Guard for severity routing, rate limiting and
+// grouping. Backends deliver alerts as given: report runs through the v2
+// alerting package, or wrap an alerter with redact.Redactor.Alerter, so
+// secrets are masked first.
+type Alerter interface {
+	// Alert delivers a, or queues it for delivery.
+	Alert(ctx context.Context, a Alert) error
+
+	// Flush waits up to timeout for queued alerts to be delivered and reports
+	// whether all were. Call it before a worker exits.
+	Flush(timeout time.Duration) bool
+}
+
+// AlerterFunc adapts a function to an Alerter with nothing to flush.
+type AlerterFunc func(ctx context.Context, a Alert) error
+
+func (f AlerterFunc) Alert(ctx context.Context, a Alert) error { return f(ctx, a) }
+
+func (f AlerterFunc) Flush(time.Duration) bool { return true }
+
+// Multi returns an Alerter that delivers every alert to all of alerters.
+// Route alerts by severity by wrapping each with Guard and WithMinSeverity.
+func Multi(alerters ...Alerter) Alerter {
+	return multiAlerter(alerters)
+}
+
+type multiAlerter []Alerter
+
+func (m multiAlerter) Alert(ctx context.Context, a Alert) error {
+	var errs []error
+	for _, alerter := range m {
+		if err := alerter.Alert(ctx, a); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
+
+func (m multiAlerter) Flush(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	ok := true
+	for _, alerter := range m {
+		ok = alerter.Flush(time.Until(deadline)) && ok
+	}
+
+	return ok
+}
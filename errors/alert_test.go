@@ -0,0 +1,169 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAlerter struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *recordingAlerter) Alert(_ context.Context, a Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, a)
+	return nil
+}
+
+func (r *recordingAlerter) Flush(time.Duration) bool { return true }
+
+func TestGuard(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("severity", func(t *testing.T) {
+		rec := &recordingAlerter{}
+		g := Guard(rec, WithMinSeverity(SeverityError))
+
+		require.NoError(t, g.Alert(ctx, Alert{Err: Timeout(stderrors.New("slow"), "slow")}))
+		require.NoError(t, g.Alert(ctx, Alert{Err: stderrors.New("boom")}))
+		require.Len(t, rec.alerts, 1)
+		require.Equal(t, SeverityError, rec.alerts[0].Severity)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		rec := &recordingAlerter{}
+		g := Guard(rec, WithRateLimit(2, time.Minute), WithAlertClock(clock))
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, g.Alert(ctx, Alert{Err: stderrors.New("boom")}))
+		}
+		require.Len(t, rec.alerts, 2)
+
+		now = now.Add(time.Minute)
+		require.NoError(t, g.Alert(ctx, Alert{Err: stderrors.New("boom")}))
+		require.Len(t, rec.alerts, 3)
+		require.Equal(t, 3, rec.alerts[2].Dropped)
+	})
+
+	t.Run("grouping", func(t *testing.T) {
+		rec := &recordingAlerter{}
+		g := Guard(rec, WithGrouping(time.Minute), WithAlertClock(clock))
+
+		step1 := Alert{Err: NotFound("no channel"), Integration: "slack", Operation: "send", StepID: "step_1"}
+		step2 := step1
+		step2.StepID = "step_2"
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, g.Alert(ctx, step1))
+		}
+		require.NoError(t, g.Alert(ctx, step2))
+		require.Len(t, rec.alerts, 2)
+
+		now = now.Add(time.Minute)
+		require.NoError(t, g.Alert(ctx, step1))
+		require.Len(t, rec.alerts, 3)
+		require.Equal(t, 3, rec.alerts[2].Count)
+
+		require.NoError(t, g.Alert(ctx, step1))
+		require.True(t, g.Flush(time.Second))
+		require.Len(t, rec.alerts, 4)
+		require.Equal(t, 1, rec.alerts[3].Count)
+	})
+}
+
+func TestGuardRateLimitKeepsGroupedCounts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rec := &recordingAlerter{}
+	g := Guard(rec, WithGrouping(time.Minute), WithRateLimit(1, time.Hour), WithAlertClock(func() time.Time { return now }))
+
+	alert := Alert{Err: NotFound("no channel"), Operation: "send", StepID: "step_1"}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, g.Alert(ctx, alert))
+	}
+
+	now = now.Add(time.Minute)
+	require.NoError(t, g.Alert(ctx, alert), "rate limited")
+	require.Len(t, rec.alerts, 1)
+
+	require.True(t, g.Flush(time.Second))
+	require.Len(t, rec.alerts, 2)
+	require.Equal(t, 2, rec.alerts[1].Count, "failures grouped before a rate limited alert are still reported")
+}
+
+func TestMulti(t *testing.T) {
+	rec := &recordingAlerter{}
+	failing := AlerterFunc(func(context.Context, Alert) error { return stderrors.New("down") })
+
+	err := Multi(Guard(rec, WithMinSeverity(SeverityError)), failing).Alert(context.Background(), Alert{Err: stderrors.New("boom")})
+	require.EqualError(t, err, "down")
+	require.Len(t, rec.alerts, 1)
+}
+
+func TestLogAlerter(t *testing.T) {
+	var buf bytes.Buffer
+	a := NewLogAlerter(zerolog.New(&buf))
+
+	require.NoError(t, a.Alert(context.Background(), Alert{Err: NotFound("no channel").WithCode("channel_not_found"), Integration: "slack", StepID: "step_1"}))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "warn", entry["level"])
+	require.Equal(t, "channel_not_found", entry["code"])
+	require.Equal(t, "step_1", entry["stepId"])
+}
+
+func TestWebhookAlerter(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer hook" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	hook, err := NewWebhookAlerter(&WebhookAlerterOpts{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer hook"}})
+	require.NoError(t, err)
+	require.NoError(t, hook.Alert(context.Background(), Alert{Err: RateLimit("slow down", time.Minute), Operation: "send"}))
+	require.Equal(t, "rate_limit", got["category"])
+	require.Equal(t, "warning", got["severity"])
+	require.Equal(t, "send", got["operation"])
+	require.Equal(t, true, got["retryable"])
+
+	unauthorized, err := NewWebhookAlerter(&WebhookAlerterOpts{URL: srv.URL})
+	require.NoError(t, err)
+	require.ErrorIs(t, unauthorized.Alert(context.Background(), Alert{Err: stderrors.New("boom")}), ErrAuth)
+
+	_, err = NewWebhookAlerter(&WebhookAlerterOpts{URL: "ftp://example.com"})
+	require.Error(t, err)
+}
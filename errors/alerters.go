@@ -0,0 +1,156 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// NewLogAlerter returns an Alerter that writes each alert as a structured log
+// entry, at warn level for warnings, error level for errors and critical
+// alerts, and info level otherwise.
+func NewLogAlerter(logger zerolog.Logger) Alerter {
+	return &logAlerter{logger: logger}
+}
+
+type logAlerter struct {
+	logger zerolog.Logger
+}
+
+func (l *logAlerter) Alert(ctx context.Context, a Alert) error {
+	a = a.normalized(time.Now())
+
+	level := zerolog.InfoLevel
+	switch a.Severity {
+	case SeverityWarning:
+		level = zerolog.WarnLevel
+	case SeverityError, SeverityCritical:
+		level = zerolog.ErrorLevel
+	}
+
+	event := l.logger.WithLevel(level).
+		Err(a.Err).
+		Str("severity", string(a.Severity)).
+		Str("category", string(CategoryOf(a.Err))).
+		Str("code", a.Code()).
+		Int("count", a.Count)
+
+	for _, field := range [][2]string{
+		{"integration", a.Integration},
+		{"operation", a.Operation},
+		{"stepId", a.StepID},
+		{"runId", a.RunID},
+		{"workflowId", a.WorkflowID},
+		{"projectId", a.ProjectID},
+	} {
+		if field[1] != "" {
+			event = event.Str(field[0], field[1])
+		}
+	}
+	if a.Dropped > 0 {
+		event = event.Int("dropped", a.Dropped)
+	}
+	if len(a.Data) > 0 {
+		event = event.Interface("data", a.Data)
+	}
+
+	event.Time("alertTime", a.Time).Msg("alert")
+
+	return nil
+}
+
+func (l *logAlerter) Flush(time.Duration) bool { return true }
+
+// DefaultWebhookTimeout bounds a webhook delivery when
+// WebhookAlerterOpts.Client is nil.
+const DefaultWebhookTimeout = 10 * time.Second
+
+type WebhookAlerterOpts struct {
+	// URL receives a POST with the JSON form of every alert
+	URL string
+
+	// Headers are added to every request, for example for authentication
+	Headers map[string]string
+
+	// Client sends the requests; defaults to a client with DefaultWebhookTimeout
+	Client *http.Client
+}
+
+// WebhookAlerter posts alerts to an HTTP endpoint.
+type WebhookAlerter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func NewWebhookAlerter(opts *WebhookAlerterOpts) (*WebhookAlerter, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("webhook alerter: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("webhook alerter: unsupported URL scheme %q", u.Scheme)
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+
+	return &WebhookAlerter{url: opts.URL, headers: opts.Headers, client: client}, nil
+}
+
+// Alert posts a and returns a categorized error if the endpoint rejects it.
+func (w *WebhookAlerter) Alert(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("webhook alerter: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook alerter: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return Upstream(err, "webhook alerter: delivery failed")
+	}
+	defer resp.Body.Close()
+
+	if e := FromResponse(resp); e != nil {
+		return e
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
+
+// Flush returns true, since alerts are delivered synchronously.
+func (w *WebhookAlerter) Flush(time.Duration) bool { return true }
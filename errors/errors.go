@@ -46,6 +46,24 @@ func (e DetailedError) Error() string {
 	return errStr
 }
 
+// Is matches the taxonomy sentinel of the category of the error's HTTP code,
+// so errors.Is(err, ErrValidation) holds for a 400 DetailedError.
+func (e DetailedError) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Category == CategoryForStatus(int(e.Code)) && (t.Code == "" || t.Code == string(t.Category))
+}
+
+// Categorized converts the error into the taxonomy, classified by its HTTP code.
+func (e DetailedError) Categorized() *Error {
+	out := FromStatus(int(e.Code), e.Description)
+	out.DocsLink = e.DocsLink
+	if e.Reason != "" {
+		out = out.WithDetail("reason", e.Reason)
+	}
+
+	return out
+}
+
 func NewError(code uint, reason, description, docsLink string) *DetailedError {
 	return &DetailedError{
 		Code:        code,
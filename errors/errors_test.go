@@ -0,0 +1,131 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorIs(t *testing.T) {
+	err := fmt.Errorf("sync contacts: %w", Auth("token expired").WithCode("auth.token_expired"))
+
+	require.ErrorIs(t, err, ErrAuth)
+	require.ErrorIs(t, err, &Error{Category: CategoryAuth, Code: "auth.token_expired"})
+	require.NotErrorIs(t, err, &Error{Category: CategoryAuth, Code: "auth.revoked"})
+	require.NotErrorIs(t, err, ErrPermission)
+	require.Equal(t, "sync contacts: token expired", err.Error())
+
+	cause := stderrors.New("connection reset")
+	wrapped := Upstream(cause, "list contacts")
+	require.ErrorIs(t, wrapped, cause)
+	require.True(t, IsRetryable(wrapped))
+	require.Equal(t, "list contacts: connection reset", wrapped.Error())
+	require.Nil(t, Wrap(nil, CategoryInternal, "x"))
+
+	require.ErrorIs(t, NewError(400, "Bad Request", "bad", ""), ErrValidation)
+	require.Equal(t, CategoryPermission, CategoryOf(NewErrForbidden(cause)))
+
+	var byValue error = *NewError(400, "Bad Request", "bad", "")
+	require.ErrorIs(t, byValue, ErrValidation)
+	require.Equal(t, CategoryValidation, CategoryOf(byValue))
+	require.True(t, IsRetryable(*NewError(503, "Service Unavailable", "down", "")))
+
+	coded := ErrAuth.WithCode("auth.revoked").WithDetail("user", "ada")
+	require.Equal(t, "auth.revoked", coded.Code)
+	require.Empty(t, ErrAuth.Code, "With methods leave sentinels unchanged")
+	require.Nil(t, ErrAuth.Details)
+}
+
+func TestCategoryOf(t *testing.T) {
+	require.Equal(t, CategoryTimeout, CategoryOf(fmt.Errorf("x: %w", context.DeadlineExceeded)))
+	require.Equal(t, CategoryCanceled, CategoryOf(context.Canceled))
+	require.Equal(t, CategoryInternal, CategoryOf(stderrors.New("boom")))
+	require.True(t, IsRetryable(context.DeadlineExceeded))
+	require.False(t, IsRetryable(nil))
+
+	e := As(context.DeadlineExceeded)
+	require.Equal(t, CategoryTimeout, e.Category)
+	require.ErrorIs(t, e, context.DeadlineExceeded)
+	require.Nil(t, As(nil))
+}
+
+func TestErrorJSON(t *testing.T) {
+	e := RateLimit("slow down", 30*time.Second).WithDetail("limit", 100)
+	e.Err = stderrors.New("429 from api")
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"category": "rate_limit",
+		"code": "rate_limit",
+		"message": "slow down",
+		"retryable": true,
+		"retryAfter": 30,
+		"status": 429,
+		"details": {"limit": 100},
+		"cause": "429 from api"
+	}`, string(data))
+
+	var out Error
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.Equal(t, 30*time.Second, out.RetryAfter)
+	require.Equal(t, "slow down: 429 from api", out.Error())
+	d, ok := RetryAfter(&out)
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, d)
+}
+
+func TestFromResponse(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, ok := ParseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)
+	require.True(t, ok)
+	require.Equal(t, time.Minute, d)
+	_, ok = ParseRetryAfter("soon", now)
+	require.False(t, ok)
+
+	for status, want := range map[int]Category{
+		401: CategoryAuth, 403: CategoryPermission, 404: CategoryNotFound, 409: CategoryConflict,
+		422: CategoryValidation, 429: CategoryRateLimit, 504: CategoryTimeout, 503: CategoryUpstream,
+	} {
+		require.Equal(t, want, CategoryForStatus(status), status)
+	}
+
+	resp := &http.Response{
+		Status:     "429 Too Many Requests",
+		StatusCode: 429,
+		Header:     http.Header{"Retry-After": {"12"}},
+		Body:       io.NopCloser(strings.NewReader(`{"error":"quota"}`)),
+		Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "https", Host: "api.example.com", Path: "/v1/rows"}},
+	}
+	e := FromResponse(resp)
+	require.ErrorIs(t, e, ErrRateLimit)
+	require.Equal(t, "http_429", e.Code)
+	require.Equal(t, 12*time.Second, e.RetryAfter)
+	require.Equal(t, `{"error":"quota"}`, e.Details["body"])
+	require.Equal(t, "GET https://api.example.com/v1/rows: 429 Too Many Requests", e.Error())
+
+	require.Nil(t, FromResponse(&http.Response{StatusCode: 204}))
+}
@@ -0,0 +1,189 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AlertOption configures Guard.
+type AlertOption func(*guard)
+
+// WithMinSeverity discards alerts less severe than min.
+func WithMinSeverity(min Severity) AlertOption {
+	return func(g *guard) { g.min = min }
+}
+
+// WithRateLimit delivers at most n alerts per interval and discards the rest.
+// The next delivered alert reports how many were discarded.
+func WithRateLimit(n int, per time.Duration) AlertOption {
+	return func(g *guard) { g.limit, g.per = n, per }
+}
+
+// WithGrouping delivers only the first alert with a given Key per window. The
+// ones after it are counted, and the count is delivered with the first alert
+// of the next window or on Flush.
+func WithGrouping(window time.Duration) AlertOption {
+	return func(g *guard) { g.window = window }
+}
+
+// WithAlertClock sets the clock windows are measured with. Defaults to time.Now.
+func WithAlertClock(now func() time.Time) AlertOption {
+	return func(g *guard) { g.now = now }
+}
+
+// Guard returns next with the policies in opts applied before delivery.
+func Guard(next Alerter, opts ...AlertOption) Alerter {
+	g := &guard{next: next, now: time.Now, groups: make(map[string]*alertGroup)}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+type guard struct {
+	next   Alerter
+	min    Severity
+	limit  int
+	per    time.Duration
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	rateStart time.Time
+	rateCount int
+	dropped   int
+	groups    map[string]*alertGroup
+}
+
+// alertGroup tracks the alerts with one key in the current grouping window.
+type alertGroup struct {
+	start      time.Time
+	last       Alert
+	suppressed int
+}
+
+func (g *guard) Alert(ctx context.Context, a Alert) error {
+	now := g.now()
+	a = a.normalized(now)
+	if !a.Severity.AtLeast(g.min) {
+		return nil
+	}
+
+	g.mu.Lock()
+	if g.suppress(a, now) || !g.allow(now) {
+		g.mu.Unlock()
+		return nil
+	}
+
+	g.open(&a, now)
+	a.Dropped += g.dropped
+	g.dropped = 0
+	g.mu.Unlock()
+
+	return g.next.Alert(ctx, a)
+}
+
+// suppress reports whether a falls in the open grouping window of its key,
+// counting it there if so.
+func (g *guard) suppress(a Alert, now time.Time) bool {
+	if g.window <= 0 {
+		return false
+	}
+
+	grp := g.groups[a.Key()]
+	if grp == nil || now.Sub(grp.start) >= g.window {
+		return false
+	}
+
+	grp.last = a
+	grp.suppressed += a.Count
+
+	return true
+}
+
+// open starts a new grouping window for a, which is about to be delivered,
+// and adds the failures counted in the previous window to it. It runs only
+// after the rate limit admitted a, so a discarded alert never loses them.
+func (g *guard) open(a *Alert, now time.Time) {
+	if g.window <= 0 {
+		return
+	}
+
+	key := a.Key()
+	if prev := g.groups[key]; prev != nil {
+		a.Count += prev.suppressed
+	}
+
+	for k, grp := range g.groups {
+		if grp.suppressed == 0 && now.Sub(grp.start) >= g.window {
+			delete(g.groups, k)
+		}
+	}
+	g.groups[key] = &alertGroup{start: now}
+}
+
+// allow reports whether the rate limit admits another alert, counting a
+// discarded one otherwise.
+func (g *guard) allow(now time.Time) bool {
+	if g.limit <= 0 {
+		return true
+	}
+
+	if now.Sub(g.rateStart) >= g.per {
+		g.rateStart, g.rateCount = now, 0
+	}
+	if g.rateCount >= g.limit {
+		g.dropped++
+		return false
+	}
+
+	g.rateCount++
+
+	return true
+}
+
+// Flush delivers the counts of grouped alerts, bypassing the rate limit, then
+// flushes the wrapped Alerter.
+func (g *guard) Flush(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	g.mu.Lock()
+	var pending []Alert
+	for key, grp := range g.groups {
+		if grp.suppressed > 0 {
+			a := grp.last
+			a.Count = grp.suppressed
+			pending = append(pending, a)
+		}
+		delete(g.groups, key)
+	}
+	g.mu.Unlock()
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	ok := true
+	for _, a := range pending {
+		if err := g.next.Alert(ctx, a); err != nil {
+			ok = false
+		}
+	}
+
+	return g.next.Flush(time.Until(deadline)) && ok
+}
@@ -0,0 +1,120 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBodyDetail is how much of a response body FromResponse keeps.
+const maxBodyDetail = 1024
+
+// CategoryForStatus maps an HTTP status code onto the taxonomy. Other client
+// errors are validation errors and other server errors are upstream errors.
+func CategoryForStatus(status int) Category {
+	switch status {
+	case http.StatusUnauthorized, http.StatusProxyAuthRequired:
+		return CategoryAuth
+	case http.StatusForbidden:
+		return CategoryPermission
+	case http.StatusNotFound, http.StatusGone:
+		return CategoryNotFound
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return CategoryConflict
+	case http.StatusTooManyRequests:
+		return CategoryRateLimit
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return CategoryTimeout
+	case 499:
+		return CategoryCanceled
+	}
+
+	switch {
+	case status >= 400 && status < 500:
+		return CategoryValidation
+	case status == http.StatusNotImplemented:
+		return CategoryInternal
+	case status >= 500:
+		return CategoryUpstream
+	default:
+		return CategoryInternal
+	}
+}
+
+// FromStatus creates an Error for an HTTP status code. The code is
+// "http_<status>", and a 503 is retryable like other upstream failures.
+func FromStatus(status int, message string) *Error {
+	e := New(CategoryForStatus(status), message)
+	e.Code = "http_" + strconv.Itoa(status)
+	e.Status = status
+
+	return e
+}
+
+// FromResponse creates an Error for a failed HTTP response, or returns nil
+// for a status below 400. The Retry-After header becomes the retry hint, and
+// up to 1 KiB of the body is kept in the "body" detail; the body is not closed.
+func FromResponse(resp *http.Response) *Error {
+	if resp == nil || resp.StatusCode < 400 {
+		return nil
+	}
+
+	msg := resp.Status
+	if resp.Request != nil && resp.Request.URL != nil {
+		msg = fmt.Sprintf("%s %s: %s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status)
+	}
+
+	e := FromStatus(resp.StatusCode, msg)
+	if d, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		e = e.WithRetryAfter(d)
+	}
+
+	if resp.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodyDetail))
+		if text := strings.TrimSpace(string(body)); text != "" {
+			e = e.WithDetail("body", text)
+		}
+	}
+
+	return e
+}
+
+// ParseRetryAfter parses a Retry-After header holding either seconds or an
+// HTTP date, relative to now.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(at.Sub(now), 0), true
+}
@@ -12,17 +12,26 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package sentry reports action and trigger failures to Sentry.
 package sentry
 
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/getsentry/sentry-go"
+	sdkerrors "github.com/wakflo/go-sdk/errors"
 )
 
+// DefaultMaxBreadcrumbs is the number of an alert's breadcrumbs attached to an
+// event when SentryAlerterOpts.MaxBreadcrumbs is zero.
+const DefaultMaxBreadcrumbs = 20
+
 type SentryAlerter struct {
-	client *sentry.Client
+	client         *sentry.Client
+	maxBreadcrumbs int
 }
 
 func noIntegrations(ints []sentry.Integration) []sentry.Integration {
@@ -32,6 +41,19 @@ func noIntegrations(ints []sentry.Integration) []sentry.Integration {
 type SentryAlerterOpts struct {
 	DSN         string
 	Environment string
+
+	// Release is reported with every event, typically the integration version
+	Release string
+
+	// SampleRate is the fraction of alerts sent, between 0 and 1; zero sends all
+	SampleRate float64
+
+	// MaxBreadcrumbs caps the breadcrumbs attached to an event, keeping the
+	// latest; zero uses DefaultMaxBreadcrumbs and a negative value attaches none
+	MaxBreadcrumbs int
+
+	// Transport replaces the HTTP transport, mainly for tests
+	Transport sentry.Transport
 }
 
 func NewSentryAlerter(opts *SentryAlerterOpts) (*SentryAlerter, error) {
@@ -40,27 +62,109 @@ func NewSentryAlerter(opts *SentryAlerterOpts) (*SentryAlerter, error) {
 		AttachStacktrace: true,
 		Integrations:     noIntegrations,
 		Environment:      opts.Environment,
+		Release:          opts.Release,
+		SampleRate:       opts.SampleRate,
+		Transport:        opts.Transport,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	maxBreadcrumbs := opts.MaxBreadcrumbs
+	if maxBreadcrumbs == 0 {
+		maxBreadcrumbs = DefaultMaxBreadcrumbs
+	}
+
 	return &SentryAlerter{
-		client: sentryClient,
+		client:         sentryClient,
+		maxBreadcrumbs: maxBreadcrumbs,
 	}, nil
 }
 
+// SendAlert reports err with data as tags. Neither is redacted; use Alert
+// through the v2 alerting package to mask secrets.
 func (s *SentryAlerter) SendAlert(ctx context.Context, err error, data map[string]interface{}) {
-	if data == nil {
-		data = make(map[string]interface{})
+	s.capture(err, sentry.NewScope(), data)
+}
+
+var _ sdkerrors.Alerter = (*SentryAlerter)(nil)
+
+// Alert reports a with its run as tags, implementing errors.Alerter. The alert
+// user and breadcrumbs are attached to the event. Report runs through the v2
+// alerting package, which redacts alerts and collects breadcrumbs, and wrap
+// the alerter with errors.Guard for severity routing, rate limiting and
+// grouping.
+func (s *SentryAlerter) Alert(ctx context.Context, a sdkerrors.Alert) error {
+	severity := a.Severity
+	if severity == "" {
+		severity = sdkerrors.SeverityOf(a.Err)
 	}
 
 	scope := sentry.NewScope()
+	scope.SetLevel(alertLevel(severity))
+	for key, value := range map[string]string{
+		"integration": a.Integration,
+		"operation":   a.Operation,
+		"step_id":     a.StepID,
+		"run_id":      a.RunID,
+		"workflow_id": a.WorkflowID,
+		"project_id":  a.ProjectID,
+	} {
+		if value != "" {
+			scope.SetTag(key, value)
+		}
+	}
+	if a.Count > 1 {
+		scope.SetExtra("count", a.Count)
+	}
+	if a.Dropped > 0 {
+		scope.SetExtra("dropped", a.Dropped)
+	}
+	if a.User != "" {
+		scope.SetUser(sentry.User{ID: a.User})
+	}
+	s.addBreadcrumbs(scope, a.Breadcrumbs)
+
+	s.capture(a.Err, scope, a.Data, a.Integration, a.Operation)
+
+	return nil
+}
+
+func alertLevel(severity sdkerrors.Severity) sentry.Level {
+	switch severity {
+	case sdkerrors.SeverityInfo:
+		return sentry.LevelInfo
+	case sdkerrors.SeverityWarning:
+		return sentry.LevelWarning
+	case sdkerrors.SeverityCritical:
+		return sentry.LevelFatal
+	default:
+		return sentry.LevelError
+	}
+}
+
+// Flush waits up to timeout for queued events to be sent and reports whether
+// the queue drained. Call it before a worker exits.
+func (s *SentryAlerter) Flush(timeout time.Duration) bool {
+	return s.client.Flush(timeout)
+}
+
+// capture sends err and data with scope. Events are fingerprinted by error
+// code, so repeated failures with varying messages group into one issue.
+func (s *SentryAlerter) capture(err error, scope *sentry.Scope, data map[string]interface{}, fingerprint ...string) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
 
 	for key, val := range data {
 		scope.SetTag(key, fmt.Sprintf("%v", val))
 	}
 
+	if code := ErrorCode(err); code != "" {
+		scope.SetTag("error_code", code)
+		scope.SetFingerprint(append(fingerprint, code))
+	}
+
 	s.client.CaptureException(
 		err,
 		&sentry.EventHint{
@@ -69,3 +173,44 @@ func (s *SentryAlerter) SendAlert(ctx context.Context, err error, data map[strin
 		scope,
 	)
 }
+
+// addBreadcrumbs attaches the last breadcrumbs to scope.
+func (s *SentryAlerter) addBreadcrumbs(scope *sentry.Scope, breadcrumbs []sdkerrors.Breadcrumb) {
+	if s.maxBreadcrumbs < 0 {
+		return
+	}
+
+	if len(breadcrumbs) > s.maxBreadcrumbs {
+		breadcrumbs = breadcrumbs[len(breadcrumbs)-s.maxBreadcrumbs:]
+	}
+
+	for _, b := range breadcrumbs {
+		scope.AddBreadcrumb(&sentry.Breadcrumb{
+			Category:  "log",
+			Level:     breadcrumbLevel(b.Level),
+			Message:   b.Message,
+			Data:      b.Fields,
+			Timestamp: b.Time,
+		}, s.maxBreadcrumbs)
+	}
+}
+
+func breadcrumbLevel(level string) sentry.Level {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		return sentry.LevelDebug
+	case "WARN", "WARNING":
+		return sentry.LevelWarning
+	case "ERROR":
+		return sentry.LevelError
+	default:
+		return sentry.LevelInfo
+	}
+}
+
+// ErrorCode returns the code events for err are grouped by, as errors.Alert
+// reports it: the code of its categorized form, or the category when no code
+// is set. It is empty for a nil err.
+func ErrorCode(err error) string {
+	return sdkerrors.Alert{Err: err}.Code()
+}
@@ -0,0 +1,95 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/require"
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+)
+
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Flush(time.Duration) bool { return true }
+
+func (t *recordingTransport) Configure(sentry.ClientOptions) {}
+
+func (t *recordingTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *recordingTransport) Close() {}
+
+func TestAlert(t *testing.T) {
+	transport := &recordingTransport{}
+	alerter, err := NewSentryAlerter(&SentryAlerterOpts{Transport: transport, MaxBreadcrumbs: 3})
+	require.NoError(t, err)
+
+	var breadcrumbs []sdkerrors.Breadcrumb
+	for i := 0; i < 5; i++ {
+		breadcrumbs = append(breadcrumbs, sdkerrors.Breadcrumb{Level: "INFO", Message: fmt.Sprintf("attempt %d", i)})
+	}
+	breadcrumbs = append(breadcrumbs, sdkerrors.Breadcrumb{Level: "WARN", Message: "calling api"})
+
+	require.NoError(t, alerter.Alert(context.Background(), sdkerrors.Alert{
+		Err:         sdkerrors.RateLimit("upstream said no", time.Minute).WithCode("slack_rate_limited"),
+		Integration: "slack",
+		Operation:   "send",
+		StepID:      "step_1",
+		User:        "user-1",
+		Breadcrumbs: breadcrumbs,
+	}))
+	require.True(t, alerter.Flush(time.Second))
+
+	require.Len(t, transport.events, 1)
+	event := transport.events[0]
+	require.Equal(t, []string{"slack", "send", "slack_rate_limited"}, event.Fingerprint)
+	require.Equal(t, "slack", event.Tags["integration"])
+	require.Equal(t, "send", event.Tags["operation"])
+	require.Equal(t, "step_1", event.Tags["step_id"])
+	require.Equal(t, sentry.LevelWarning, event.Level)
+	require.Equal(t, "user-1", event.User.ID)
+
+	require.Len(t, event.Breadcrumbs, 3)
+	require.Equal(t, "attempt 3", event.Breadcrumbs[0].Message)
+	require.Equal(t, sentry.LevelWarning, event.Breadcrumbs[2].Level)
+}
+
+type detailed struct{ code string }
+
+func (d detailed) Error() string { return d.code }
+
+func (d detailed) Categorized() *sdkerrors.Error {
+	return sdkerrors.New(sdkerrors.CategoryValidation, d.code).WithCode(d.code)
+}
+
+func TestErrorCode(t *testing.T) {
+	require.Equal(t, "timeout", ErrorCode(sdkerrors.Timeout(errors.New("slow"), "slow")))
+	require.Equal(t, "bad_input", ErrorCode(fmt.Errorf("perform: %w", detailed{"bad_input"})))
+	require.Equal(t, "internal", ErrorCode(errors.New("boom")))
+	require.Empty(t, ErrorCode(nil))
+}
@@ -0,0 +1,383 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"maps"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Category classifies an error by what went wrong, independently of the
+// integration that reported it.
+type Category string
+
+const (
+	// CategoryAuth means credentials are missing, invalid or expired
+	CategoryAuth Category = "auth"
+
+	// CategoryPermission means the credentials lack access to the resource
+	CategoryPermission Category = "permission"
+
+	// CategoryRateLimit means a quota or rate limit was hit
+	CategoryRateLimit Category = "rate_limit"
+
+	// CategoryNotFound means the requested resource does not exist
+	CategoryNotFound Category = "not_found"
+
+	// CategoryValidation means the input was rejected
+	CategoryValidation Category = "validation"
+
+	// CategoryConflict means the request conflicts with the resource's state
+	CategoryConflict Category = "conflict"
+
+	// CategoryUpstream means a remote service failed
+	CategoryUpstream Category = "upstream"
+
+	// CategoryTimeout means an operation did not finish in time
+	CategoryTimeout Category = "timeout"
+
+	// CategoryCanceled means the operation was canceled
+	CategoryCanceled Category = "canceled"
+
+	// CategoryInternal means a bug or an unclassified failure
+	CategoryInternal Category = "internal"
+)
+
+// Sentinels matching any Error of their category with errors.Is.
+var (
+	ErrAuth       = &Error{Category: CategoryAuth}
+	ErrPermission = &Error{Category: CategoryPermission}
+	ErrRateLimit  = &Error{Category: CategoryRateLimit}
+	ErrNotFound   = &Error{Category: CategoryNotFound}
+	ErrValidation = &Error{Category: CategoryValidation}
+	ErrConflict   = &Error{Category: CategoryConflict}
+	ErrUpstream   = &Error{Category: CategoryUpstream}
+	ErrTimeout    = &Error{Category: CategoryTimeout}
+	ErrCanceled   = &Error{Category: CategoryCanceled}
+	ErrInternal   = &Error{Category: CategoryInternal}
+)
+
+// Retryable reports whether errors of the category are usually transient.
+func (c Category) Retryable() bool {
+	switch c {
+	case CategoryRateLimit, CategoryUpstream, CategoryTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// HTTPStatus returns the HTTP status code that best represents the category.
+func (c Category) HTTPStatus() int {
+	switch c {
+	case CategoryAuth:
+		return http.StatusUnauthorized
+	case CategoryPermission:
+		return http.StatusForbidden
+	case CategoryRateLimit:
+		return http.StatusTooManyRequests
+	case CategoryNotFound:
+		return http.StatusNotFound
+	case CategoryValidation:
+		return http.StatusBadRequest
+	case CategoryConflict:
+		return http.StatusConflict
+	case CategoryUpstream:
+		return http.StatusBadGateway
+	case CategoryTimeout:
+		return http.StatusGatewayTimeout
+	case CategoryCanceled:
+		return 499
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error is a categorized error. The With methods return a modified copy, so an
+// Error can be built in one expression without changing the package sentinels.
+type Error struct {
+	// Category classifies the error
+	Category Category `json:"category"`
+
+	// Code is a stable machine-readable code, such as "auth.token_expired"; it defaults to the category
+	Code string `json:"code"`
+
+	// Message is a human-readable description
+	Message string `json:"message"`
+
+	// Retryable tells whether the operation may succeed if tried again
+	Retryable bool `json:"retryable"`
+
+	// RetryAfter is how long to wait before retrying, zero if unknown
+	RetryAfter time.Duration `json:"-"`
+
+	// Status is the HTTP status code the error came from or maps to
+	Status int `json:"status,omitempty"`
+
+	// Details holds additional context
+	Details map[string]any `json:"details,omitempty"`
+
+	// DocsLink points to documentation about the error
+	DocsLink string `json:"docsLink,omitempty"`
+
+	// Err is the underlying cause
+	Err error `json:"-"`
+}
+
+// New creates an Error of the given category. Retryable defaults to the
+// category's and Code to the category name.
+func New(category Category, message string) *Error {
+	return &Error{
+		Category:  category,
+		Code:      string(category),
+		Message:   message,
+		Retryable: category.Retryable(),
+		Status:    category.HTTPStatus(),
+	}
+}
+
+// Wrap creates an Error of the given category caused by err. A nil err yields nil.
+func Wrap(err error, category Category, message string) *Error {
+	if err == nil {
+		return nil
+	}
+
+	e := New(category, message)
+	e.Err = err
+
+	return e
+}
+
+// Auth creates an auth error.
+func Auth(message string) *Error { return New(CategoryAuth, message) }
+
+// Permission creates a permission error.
+func Permission(message string) *Error { return New(CategoryPermission, message) }
+
+// RateLimit creates a rate limit error with a hint of when to retry.
+func RateLimit(message string, retryAfter time.Duration) *Error {
+	return New(CategoryRateLimit, message).WithRetryAfter(retryAfter)
+}
+
+// NotFound creates a not-found error.
+func NotFound(message string) *Error { return New(CategoryNotFound, message) }
+
+// Validation creates a validation error.
+func Validation(message string) *Error { return New(CategoryValidation, message) }
+
+// Conflict creates a conflict error.
+func Conflict(message string) *Error { return New(CategoryConflict, message) }
+
+// Upstream wraps the failure of a remote service.
+func Upstream(err error, message string) *Error { return Wrap(err, CategoryUpstream, message) }
+
+// Timeout wraps an operation that did not finish in time.
+func Timeout(err error, message string) *Error { return Wrap(err, CategoryTimeout, message) }
+
+// Internal wraps an unexpected failure.
+func Internal(err error, message string) *Error { return Wrap(err, CategoryInternal, message) }
+
+// WithCode sets the machine-readable code.
+func (e *Error) WithCode(code string) *Error {
+	e = e.clone()
+	e.Code = code
+	return e
+}
+
+// WithRetryable overrides whether the error is retryable.
+func (e *Error) WithRetryable(retryable bool) *Error {
+	e = e.clone()
+	e.Retryable = retryable
+	return e
+}
+
+// WithRetryAfter sets the retry delay hint and marks the error retryable.
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	e = e.clone()
+	e.RetryAfter = d
+	if d > 0 {
+		e.Retryable = true
+	}
+
+	return e
+}
+
+// WithDetail adds a detail.
+func (e *Error) WithDetail(key string, value any) *Error {
+	e = e.clone()
+	if e.Details == nil {
+		e.Details = map[string]any{}
+	}
+	e.Details[key] = value
+
+	return e
+}
+
+// WithDocsLink sets the documentation link.
+func (e *Error) WithDocsLink(link string) *Error {
+	e = e.clone()
+	e.DocsLink = link
+	return e
+}
+
+// clone copies e, including its details.
+func (e *Error) clone() *Error {
+	c := *e
+	c.Details = maps.Clone(e.Details)
+
+	return &c
+}
+
+// Categorized returns e, so an Error satisfies Categorizer.
+func (e *Error) Categorized() *Error { return e }
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = string(e.Category) + " error"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns the cause.
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is matches an Error target of the same category whose code is empty, the
+// category name, or equal to e's code. The package sentinels therefore match
+// every error of their category.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || t.Category != e.Category {
+		return false
+	}
+
+	return t.Code == "" || t.Code == string(t.Category) || t.Code == e.Code
+}
+
+// MarshalJSON encodes RetryAfter in seconds and the cause as its message.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	type plain Error
+	out := struct {
+		*plain
+		RetryAfter float64 `json:"retryAfter,omitempty"`
+		Cause      string  `json:"cause,omitempty"`
+	}{plain: (*plain)(e), RetryAfter: e.RetryAfter.Seconds()}
+	if e.Err != nil {
+		out.Cause = e.Err.Error()
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes an Error written by MarshalJSON. The cause is
+// restored as a plain error.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	type plain Error
+	in := struct {
+		*plain
+		RetryAfter float64 `json:"retryAfter,omitempty"`
+		Cause      string  `json:"cause,omitempty"`
+	}{plain: (*plain)(e)}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	e.RetryAfter = time.Duration(in.RetryAfter * float64(time.Second))
+	if in.Cause != "" {
+		e.Err = stderrors.New(in.Cause)
+	}
+
+	return nil
+}
+
+// Categorizer is implemented by error types that can describe themselves in
+// the taxonomy, such as DetailedError and errors defined in packages this one
+// cannot import.
+type Categorizer interface {
+	Categorized() *Error
+}
+
+// categorized returns the taxonomy form of the first Categorizer in err's chain.
+func categorized(err error) *Error {
+	var c Categorizer
+	if stderrors.As(err, &c) {
+		return c.Categorized()
+	}
+
+	return nil
+}
+
+// As returns the Error for the first Categorizer in err's chain. Errors that
+// are not categorized are classified with CategoryOf and wrapped, so the
+// result is nil only for a nil err.
+func As(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	if e := categorized(err); e != nil {
+		return e
+	}
+
+	return Wrap(err, CategoryOf(err), "")
+}
+
+// CategoryOf classifies err. Categorizers report their category, context and
+// network timeouts are timeouts, and anything else is internal.
+func CategoryOf(err error) Category {
+	if e := categorized(err); e != nil {
+		return e.Category
+	}
+
+	var netErr net.Error
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return CategoryTimeout
+	case stderrors.Is(err, context.Canceled):
+		return CategoryCanceled
+	case stderrors.As(err, &netErr) && netErr.Timeout():
+		return CategoryTimeout
+	default:
+		return CategoryInternal
+	}
+}
+
+// IsRetryable reports whether the operation that failed with err may succeed
+// if tried again.
+func IsRetryable(err error) bool {
+	if e := categorized(err); e != nil {
+		return e.Retryable
+	}
+
+	return err != nil && CategoryOf(err).Retryable()
+}
+
+// RetryAfter returns the retry delay hint carried by err, if any.
+func RetryAfter(err error) (time.Duration, bool) {
+	if e := categorized(err); e != nil && e.RetryAfter > 0 {
+		return e.RetryAfter, true
+	}
+
+	return 0, false
+}
@@ -15,7 +15,13 @@
 package sdk
 
 import (
+	stderrors "errors"
+	"maps"
+	"time"
+
 	"github.com/juicycleff/smartform/v1"
+	sdkcore "github.com/wakflo/go-sdk/core"
+	sdkerrors "github.com/wakflo/go-sdk/errors"
 	"github.com/wakflo/go-sdk/v2/context"
 	"github.com/wakflo/go-sdk/v2/core"
 )
@@ -46,6 +52,9 @@ type ActionMetadata struct {
 	// SampleOutput contains an example of the action's output
 	SampleOutput core.JSON `json:"sampleOutput,omitempty"`
 
+	// OutputSchema describes the action's output; when nil it is inferred from SampleOutput
+	OutputSchema *sdkcore.AutoFormSchema `json:"outputSchema,omitempty"`
+
 	// Tags are searchable labels for the action
 	Tags []string `json:"tags,omitempty"`
 
@@ -85,6 +94,9 @@ type ActionDefinition struct {
 	// SampleOutput contains an example of the action's output
 	SampleOutput core.JSON `json:"sampleOutput,omitempty"`
 
+	// OutputSchema describes the action's output, declared or inferred from SampleOutput
+	OutputSchema *sdkcore.AutoFormSchema `json:"outputSchema,omitempty"`
+
 	Properties *smartform.FormSchema `json:"properties"`
 
 	// Tags are searchable labels for the action
@@ -110,6 +122,86 @@ type ActionError struct {
 
 	// Details contains additional context for the error
 	Details map[string]interface{} `json:"details,omitempty"`
+
+	// Category classifies the error; empty is treated as internal
+	Category sdkerrors.Category `json:"category,omitempty"`
+}
+
+func (e *ActionError) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+
+	return e.Message
+}
+
+// Is matches the errors taxonomy sentinel of the error's category, and an
+// errors.Error of that category with the same code.
+func (e *ActionError) Is(target error) bool {
+	t, ok := target.(*sdkerrors.Error)
+	if !ok {
+		return false
+	}
+
+	category := e.category()
+	return t.Category == category && (t.Code == "" || t.Code == string(category) || t.Code == e.Code)
+}
+
+// Categorized converts the error into the errors taxonomy, so errors.As,
+// errors.CategoryOf and errors.IsRetryable classify it. The
+// "retryAfterSeconds" detail becomes the retry hint.
+func (e *ActionError) Categorized() *sdkerrors.Error {
+	category := e.category()
+	out := sdkerrors.New(category, e.Message).WithRetryable(e.Retryable)
+	if e.Code != "" {
+		out.Code = e.Code
+	}
+	out.Details = maps.Clone(e.Details)
+
+	if secs, ok := e.Details["retryAfterSeconds"].(float64); ok && secs > 0 {
+		out.RetryAfter = time.Duration(secs * float64(time.Second))
+	}
+
+	return out
+}
+
+func (e *ActionError) category() sdkerrors.Category {
+	if e.Category == "" {
+		return sdkerrors.CategoryInternal
+	}
+
+	return e.Category
+}
+
+// NewActionError converts err into an ActionError for reporting a failed step.
+// The code, retryability and details come from the errors taxonomy; a retry
+// hint is kept as the "retryAfterSeconds" detail. A nil err yields nil.
+func NewActionError(err error) *ActionError {
+	if err == nil {
+		return nil
+	}
+
+	var ae *ActionError
+	if stderrors.As(err, &ae) {
+		return ae
+	}
+
+	te := sdkerrors.As(err)
+	out := &ActionError{
+		Code:      te.Code,
+		Message:   err.Error(),
+		Retryable: te.Retryable,
+		Details:   maps.Clone(te.Details),
+		Category:  te.Category,
+	}
+	if te.RetryAfter > 0 {
+		if out.Details == nil {
+			out.Details = map[string]interface{}{}
+		}
+		out.Details["retryAfterSeconds"] = te.RetryAfter.Seconds()
+	}
+
+	return out
 }
 
 // Action defines the interface for workflow actions.
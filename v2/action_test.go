@@ -0,0 +1,46 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+)
+
+func TestActionError(t *testing.T) {
+	ae := NewActionError(fmt.Errorf("perform: %w", sdkerrors.RateLimit("slow down", time.Minute)))
+	require.Equal(t, "rate_limit", ae.Code)
+	require.True(t, ae.Retryable)
+	require.Equal(t, 60.0, ae.Details["retryAfterSeconds"])
+	require.ErrorIs(t, ae, sdkerrors.ErrRateLimit)
+
+	var err error = &ActionError{Code: "missing_sheet", Message: "sheet not found", Category: sdkerrors.CategoryNotFound}
+	require.ErrorIs(t, err, sdkerrors.ErrNotFound)
+	require.Same(t, err, NewActionError(fmt.Errorf("wrapped: %w", err)))
+	require.Nil(t, NewActionError(nil))
+
+	err = fmt.Errorf("perform: %w", &ActionError{Code: "quota", Category: sdkerrors.CategoryRateLimit, Retryable: true,
+		Details: map[string]interface{}{"retryAfterSeconds": 30.0}})
+	require.Equal(t, sdkerrors.CategoryRateLimit, sdkerrors.CategoryOf(err))
+	require.True(t, sdkerrors.IsRetryable(err))
+	require.Equal(t, "quota", sdkerrors.As(err).Code)
+	after, ok := sdkerrors.RetryAfter(err)
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, after)
+}
@@ -0,0 +1,209 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package alerting reports failed action and trigger runs to an errors.Alerter.
+//
+// Every alert is redacted with the run's auth secrets, the input fields its
+// schema marks secret and the default token patterns before any backend sees
+// it.
+package alerting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juicycleff/smartform/v1"
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/redact"
+)
+
+// Option configures an alerting hook.
+type Option func(*config)
+
+type config struct {
+	integration    string
+	maxBreadcrumbs int
+	user           func(ctx sdkcontext.BaseContext) string
+}
+
+// WithIntegration sets the integration ID alerts are reported under.
+func WithIntegration(integrationID string) Option {
+	return func(c *config) { c.integration = integrationID }
+}
+
+// WithBreadcrumbs attaches up to n of the run's latest log entries to each
+// alert of an action or trigger Execute.
+func WithBreadcrumbs(n int) Option {
+	return func(c *config) { c.maxBreadcrumbs = n }
+}
+
+// WithUser sets the user reported with each alert of an action or trigger
+// Execute, since the execution context does not carry one.
+func WithUser(fn func(ctx sdkcontext.BaseContext) string) Option {
+	return func(c *config) { c.user = fn }
+}
+
+func newConfig(opts []Option) config {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WrapAction returns action with every failed Perform reported to alerter.
+// Panics are reported as critical and then re-raised. A failure to deliver an
+// alert is logged and does not change the result.
+func WrapAction(action sdk.Action, alerter sdkerrors.Alerter, opts ...Option) sdk.Action {
+	return &alertingAction{Action: action, alerter: alerter, cfg: newConfig(opts)}
+}
+
+type alertingAction struct {
+	sdk.Action
+	alerter sdkerrors.Alerter
+	cfg     config
+}
+
+// Unwrap returns the instrumented action.
+func (a *alertingAction) Unwrap() sdk.Action { return a.Action }
+
+func (a *alertingAction) Perform(ctx sdkcontext.PerformContext) (output core.JSON, err error) {
+	defer func() {
+		recovered := recover()
+		if err != nil || recovered != nil {
+			alert := a.cfg.alert(ctx, a.Metadata().ID)
+			alert.StepID = ctx.StepID()
+			alert.RunID = ctx.RunID().String()
+			send(ctx.Context(), ctx.Logger(), runRedactor(ctx, ctx.Schema()), a.alerter, alert, err, recovered)
+		}
+	}()
+
+	return a.Action.Perform(ctx)
+}
+
+// WrapTrigger is WrapAction for a trigger's Start, Execute and Stop.
+func WrapTrigger(trigger sdk.Trigger, alerter sdkerrors.Alerter, opts ...Option) sdk.Trigger {
+	return &alertingTrigger{Trigger: trigger, alerter: alerter, cfg: newConfig(opts)}
+}
+
+type alertingTrigger struct {
+	sdk.Trigger
+	alerter sdkerrors.Alerter
+	cfg     config
+}
+
+// Unwrap returns the instrumented trigger.
+func (t *alertingTrigger) Unwrap() sdk.Trigger { return t.Trigger }
+
+func (t *alertingTrigger) Start(ctx sdkcontext.LifecycleContext) (err error) {
+	defer func() { t.lifecycle(ctx, "start", err, recover()) }()
+	return t.Trigger.Start(ctx)
+}
+
+func (t *alertingTrigger) Stop(ctx sdkcontext.LifecycleContext) (err error) {
+	defer func() { t.lifecycle(ctx, "stop", err, recover()) }()
+	return t.Trigger.Stop(ctx)
+}
+
+func (t *alertingTrigger) Execute(ctx sdkcontext.ExecuteContext) (output core.JSON, err error) {
+	defer func() {
+		recovered := recover()
+		if err != nil || recovered != nil {
+			alert := t.cfg.alert(ctx, t.Metadata().ID)
+			alert.RunID = ctx.RunID().String()
+			send(ctx.Context(), ctx.Logger(), runRedactor(ctx, ctx.Schema()), t.alerter, alert, err, recovered)
+		}
+	}()
+
+	return t.Trigger.Execute(ctx)
+}
+
+// lifecycle reports a failed Start or Stop call, tagged with the phase, and
+// re-raises a recovered panic.
+func (t *alertingTrigger) lifecycle(ctx sdkcontext.LifecycleContext, phase string, err error, recovered any) {
+	if err == nil && recovered == nil {
+		return
+	}
+
+	r := redact.New()
+	r.AddSchema(t.Props(), ctx.Input())
+
+	alert := sdkerrors.Alert{
+		Integration: t.cfg.integration,
+		Operation:   t.Metadata().ID,
+		Data:        map[string]any{"phase": phase},
+	}
+	send(ctx.Context(), ctx.Logger(), r, t.alerter, alert, err, recovered)
+}
+
+func (c config) alert(ctx sdkcontext.BaseContext, operation string) sdkerrors.Alert {
+	alert := sdkerrors.Alert{Integration: c.integration, Operation: operation}
+	if id := ctx.WorkflowID(); !id.IsNil() {
+		alert.WorkflowID = id.String()
+	}
+	if id := ctx.ProjectID(); !id.IsNil() {
+		alert.ProjectID = id.String()
+	}
+	if c.user != nil {
+		alert.User = c.user(ctx)
+	}
+
+	if c.maxBreadcrumbs > 0 && ctx.Logger() != nil {
+		logs := ctx.Logger().GetLogs()
+		if len(logs) > c.maxBreadcrumbs {
+			logs = logs[len(logs)-c.maxBreadcrumbs:]
+		}
+
+		for _, entry := range logs {
+			alert.Breadcrumbs = append(alert.Breadcrumbs, sdkerrors.Breadcrumb{
+				Time:    entry.Timestamp,
+				Level:   string(entry.Level),
+				Message: entry.Message,
+				Fields:  entry.Fields,
+			})
+		}
+	}
+
+	return alert
+}
+
+// runRedactor masks the auth secrets of a run and the input fields its schema
+// marks secret.
+func runRedactor(ctx sdkcontext.BaseContext, schema *smartform.FormSchema) *redact.Redactor {
+	r := redact.New(redact.WithAuth(ctx.Auth()))
+	r.AddSchema(schema, ctx.Input())
+	return r
+}
+
+// send redacts and reports err, or the panic value recovered, and re-raises
+// the panic. err or recovered must be set.
+func send(ctx context.Context, logger core.Logger, r *redact.Redactor, alerter sdkerrors.Alerter, alert sdkerrors.Alert, err error, recovered any) {
+	if recovered != nil {
+		err = fmt.Errorf("panic: %v", recovered)
+		alert.Severity = sdkerrors.SeverityCritical
+	}
+
+	alert.Err = err
+	if alertErr := alerter.Alert(ctx, r.Alert(alert)); alertErr != nil && logger != nil {
+		logger.Warn("alert delivery failed", "error", r.String(alertErr.Error()))
+	}
+
+	if recovered != nil {
+		panic(recovered)
+	}
+}
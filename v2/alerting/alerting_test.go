@@ -0,0 +1,136 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/sdktest"
+)
+
+type sendAction struct{ panics bool }
+
+func (a *sendAction) Metadata() sdk.ActionMetadata {
+	return sdk.ActionMetadata{ID: "send", DisplayName: "Send", Type: core.ActionTypeAction}
+}
+
+func (a *sendAction) Properties() *smartform.FormSchema { return &smartform.FormSchema{ID: "send"} }
+
+func (a *sendAction) Auth() *core.AuthMetadata { return nil }
+
+func (a *sendAction) Perform(ctx sdkcontext.PerformContext) (core.JSON, error) {
+	if a.panics {
+		panic("boom")
+	}
+
+	return nil, fmt.Errorf("post with %s: %w", ctx.Auth().AccessToken, sdkerrors.NotFound("no channel").WithCode("channel_not_found"))
+}
+
+func TestWrapAction(t *testing.T) {
+	var buf bytes.Buffer
+	action := WrapAction(&sendAction{}, sdkerrors.NewLogAlerter(zerolog.New(&buf)), WithIntegration("slack"))
+
+	_, err := action.Perform(sdktest.NewPerformContext(
+		sdktest.WithStepID("step_1"),
+		sdktest.WithAuth(&sdkcontext.AuthContext{AccessToken: "xoxb-123456"}),
+	))
+	require.ErrorIs(t, err, sdkerrors.ErrNotFound)
+
+	out := buf.String()
+	require.NotContains(t, out, "xoxb-123456")
+	require.Contains(t, out, `"code":"channel_not_found"`)
+	require.Contains(t, out, `"level":"warn"`)
+	require.Contains(t, out, `"stepId":"step_1"`)
+}
+
+func TestWrapActionPanic(t *testing.T) {
+	var got []sdkerrors.Alert
+	alerter := sdkerrors.AlerterFunc(func(_ context.Context, a sdkerrors.Alert) error {
+		got = append(got, a)
+		return nil
+	})
+
+	action := WrapAction(&sendAction{panics: true}, alerter)
+	require.PanicsWithValue(t, "boom", func() { _, _ = action.Perform(sdktest.NewPerformContext()) })
+	require.Len(t, got, 1)
+	require.Equal(t, sdkerrors.SeverityCritical, got[0].Severity)
+}
+
+func TestWrapActionBreadcrumbs(t *testing.T) {
+	var got []sdkerrors.Alert
+	alerter := sdkerrors.AlerterFunc(func(_ context.Context, a sdkerrors.Alert) error {
+		got = append(got, a)
+		return nil
+	})
+
+	action := WrapAction(&sendAction{}, alerter, WithBreadcrumbs(2),
+		WithUser(func(sdkcontext.BaseContext) string { return "user-1" }))
+
+	ctx := sdktest.NewPerformContext(sdktest.WithAuth(&sdkcontext.AuthContext{AccessToken: "xoxb-123456"}))
+	ctx.Logger().Info("resolving channel")
+	ctx.Logger().Debug("loaded config")
+	ctx.Logger().Warn("posting with xoxb-123456", "token", "xoxb-123456")
+	_, _ = action.Perform(ctx)
+
+	require.Len(t, got, 1)
+	require.Equal(t, "user-1", got[0].User)
+	require.Len(t, got[0].Breadcrumbs, 2)
+	require.Equal(t, "loaded config", got[0].Breadcrumbs[0].Message)
+	require.Equal(t, string(core.LevelWarning), got[0].Breadcrumbs[1].Level)
+	require.NotContains(t, got[0].Breadcrumbs[1].Message, "xoxb-123456")
+	require.NotContains(t, fmt.Sprint(got[0].Breadcrumbs[1].Fields), "xoxb-123456")
+}
+
+type startTrigger struct{}
+
+func (t *startTrigger) Metadata() sdk.TriggerMetadata {
+	return sdk.TriggerMetadata{ID: "poll", DisplayName: "Poll", Type: core.TriggerTypePolling}
+}
+
+func (t *startTrigger) Props() *smartform.FormSchema { return &smartform.FormSchema{ID: "poll"} }
+
+func (t *startTrigger) Auth() *core.AuthMetadata { return nil }
+
+func (t *startTrigger) Start(sdkcontext.LifecycleContext) error { return errors.New("no webhook") }
+
+func (t *startTrigger) Stop(sdkcontext.LifecycleContext) error { return nil }
+
+func (t *startTrigger) Execute(sdkcontext.ExecuteContext) (core.JSON, error) { return nil, nil }
+
+func TestWrapTriggerLifecycle(t *testing.T) {
+	var got []sdkerrors.Alert
+	alerter := sdkerrors.AlerterFunc(func(_ context.Context, a sdkerrors.Alert) error {
+		got = append(got, a)
+		return nil
+	})
+
+	res := sdktest.RunTrigger(WrapTrigger(&startTrigger{}, alerter, WithIntegration("acme")), nil)
+	require.Equal(t, "start", res.Phase)
+	require.Len(t, got, 1)
+	require.Equal(t, "poll", got[0].Operation)
+	require.Equal(t, "start", got[0].Data["phase"])
+	require.EqualError(t, got[0].Err, "no webhook")
+}
@@ -0,0 +1,260 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle implements the signed archive format used to ship an
+// integration to the platform.
+//
+// A bundle is a gzip-compressed tar archive with the following layout:
+//
+//	manifest.json                      SHA-256 digest of every other file
+//	manifest.sig                       ed25519 signature of manifest.json
+//	flo.toml                           integration metadata
+//	README.md                          integration documentation
+//	definitions.json                   JSON dump of action/trigger definitions
+//	bin/<platform>/<os>-<arch>/<name>  one binary per native build target
+//	bin/<platform>/<name>              binaries not tied to an OS, e.g. wasm
+//
+// The signature covers the exact bytes of manifest.json, and the manifest
+// covers every other file, so a bundle that verifies cannot have been
+// modified after it was packed.
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// FormatVersion is the bundle layout version written by Pack.
+const FormatVersion = 1
+
+// Well-known file names inside a bundle.
+const (
+	ManifestFile    = "manifest.json"
+	SignatureFile   = "manifest.sig"
+	FloFile         = "flo.toml"
+	ReadmeFile      = "README.md"
+	DefinitionsFile = "definitions.json"
+	binDir          = "bin"
+)
+
+var (
+	// ErrUnsigned is returned when a bundle carries no signature and unsigned bundles are not allowed.
+	ErrUnsigned = errors.New("bundle is not signed")
+
+	// ErrBadSignature is returned when the manifest signature does not match any trusted key.
+	ErrBadSignature = errors.New("bundle signature is invalid")
+
+	// ErrTampered is returned when a file does not match the digest recorded in the manifest.
+	ErrTampered = errors.New("bundle content does not match manifest")
+
+	// ErrMalformed is returned when the archive does not follow the bundle layout.
+	ErrMalformed = errors.New("malformed bundle")
+)
+
+// Binary is a compiled integration for a single build target.
+type Binary struct {
+	// Platform is the integration platform, e.g. "native" or "wasm"
+	Platform string `json:"platform"`
+
+	// OS is the target operating system (GOOS), empty for wasm builds
+	OS string `json:"os,omitempty"`
+
+	// Arch is the target architecture (GOARCH), empty for wasm builds
+	Arch string `json:"arch,omitempty"`
+
+	// Name is the file name of the binary inside its target directory
+	Name string `json:"name"`
+
+	// Content holds the binary itself
+	Content []byte `json:"-"`
+}
+
+// Target returns the "<os>-<arch>" identifier of the binary, or the platform
+// name for targets that are not tied to an operating system.
+func (b Binary) Target() string {
+	if b.OS == "" && b.Arch == "" {
+		return b.Platform
+	}
+
+	return b.OS + "-" + b.Arch
+}
+
+// Path returns the location of the binary inside the archive.
+func (b Binary) Path() string {
+	if b.OS == "" && b.Arch == "" {
+		return path.Join(binDir, b.Platform, b.Name)
+	}
+
+	return path.Join(binDir, b.Platform, b.Target(), b.Name)
+}
+
+// Bundle is the in-memory representation of a packaged integration.
+type Bundle struct {
+	// FloFile is the raw content of flo.toml
+	FloFile []byte
+
+	// Readme is the raw content of README.md
+	Readme []byte
+
+	// Definitions is the JSON dump of the integration's action and trigger definitions
+	Definitions []byte
+
+	// Binaries contains one entry per build target
+	Binaries []Binary
+
+	// Manifest is populated by Unpack and Pack
+	Manifest *Manifest
+}
+
+// Binary returns the binary built for the given platform, os and arch.
+func (b *Bundle) Binary(platform, goos, goarch string) (*Binary, bool) {
+	for i := range b.Binaries {
+		bin := &b.Binaries[i]
+		if bin.Platform == platform && bin.OS == goos && bin.Arch == goarch {
+			return bin, true
+		}
+	}
+
+	return nil, false
+}
+
+// FileEntry records the digest of a single file in the bundle.
+type FileEntry struct {
+	// Path is the location of the file inside the archive
+	Path string `json:"path"`
+
+	// SHA256 is the hex-encoded SHA-256 digest of the file
+	SHA256 string `json:"sha256"`
+
+	// Size is the file size in bytes
+	Size int64 `json:"size"`
+}
+
+// Manifest describes the content of a bundle.
+type Manifest struct {
+	// FormatVersion is the bundle layout version
+	FormatVersion int `json:"formatVersion"`
+
+	// Name is the integration name taken from flo.toml
+	Name string `json:"name"`
+
+	// Version is the integration version taken from flo.toml
+	Version string `json:"version"`
+
+	// CreatedAt is when the bundle was packed
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Files lists every file in the bundle except the manifest and its signature
+	Files []FileEntry `json:"files"`
+
+	// Binaries describes the build targets shipped in the bundle
+	Binaries []Binary `json:"binaries,omitempty"`
+
+	// PublicKey is the hex-encoded ed25519 key the bundle was signed with.
+	// It is informational only; trust is always decided by VerifyOptions.
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// File returns the manifest entry for the given archive path.
+func (m *Manifest) File(name string) (*FileEntry, bool) {
+	for i := range m.Files {
+		if m.Files[i].Path == name {
+			return &m.Files[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// VerifyOptions configures how Unpack and Verify decide whether to trust a bundle.
+type VerifyOptions struct {
+	// TrustedKeys are the public keys a bundle may be signed with
+	TrustedKeys []ed25519.PublicKey
+
+	// AllowUnsigned accepts bundles without a signature. Digests are still checked.
+	AllowUnsigned bool
+
+	// MaxSize limits the total uncompressed size of the bundle. Zero uses DefaultMaxSize.
+	MaxSize int64
+}
+
+// DefaultMaxSize is the uncompressed size limit used when VerifyOptions.MaxSize is zero.
+const DefaultMaxSize int64 = 512 << 20
+
+func (o VerifyOptions) maxSize() int64 {
+	if o.MaxSize <= 0 {
+		return DefaultMaxSize
+	}
+
+	return o.MaxSize
+}
+
+// Sum returns the hex-encoded SHA-256 digest of everything read from r.
+// It is the value stored in ConnectorVersionMetadata.FileHash for a packed bundle.
+func Sum(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+type floIdentity struct {
+	Integration struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+	} `toml:"integration"`
+}
+
+func readIdentity(flo []byte) (string, string, error) {
+	var id floIdentity
+	if err := toml.Unmarshal(flo, &id); err != nil {
+		return "", "", fmt.Errorf("failed to read flo.toml: %w", err)
+	}
+
+	if id.Integration.Name == "" || id.Integration.Version == "" {
+		return "", "", fmt.Errorf("%w: flo.toml must declare integration name and version", ErrMalformed)
+	}
+
+	return id.Integration.Name, id.Integration.Version, nil
+}
+
+// cleanPath rejects archive paths that could escape the extraction directory.
+func cleanPath(name string) (string, error) {
+	if name == "" || strings.HasPrefix(name, "/") || strings.Contains(name, "\\") {
+		return "", fmt.Errorf("%w: invalid path %q", ErrMalformed, name)
+	}
+
+	cleaned := path.Clean(name)
+	if cleaned != name || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%w: invalid path %q", ErrMalformed, name)
+	}
+
+	return cleaned, nil
+}
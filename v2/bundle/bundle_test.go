@@ -0,0 +1,195 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testFlo = `[integration]
+name = "gmail"
+version = "1.2.0"
+`
+
+func testBundle() *Bundle {
+	return &Bundle{
+		FloFile:     []byte(testFlo),
+		Readme:      []byte("# Gmail"),
+		Definitions: []byte(`{"actions":{},"triggers":{}}`),
+		Binaries: []Binary{
+			{Platform: "native", OS: "linux", Arch: "amd64", Name: "gmail", Content: []byte("ELF")},
+			{Platform: "wasm", Name: "gmail.wasm", Content: []byte("\x00asm")},
+		},
+	}
+}
+
+func packTestBundle(t *testing.T, key ed25519.PrivateKey) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	_, err := Pack(&buf, testBundle(), key)
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+// rewrite re-packs an archive, letting fn modify the content of each entry.
+func rewrite(t *testing.T, archive []byte, fn func(name string, content []byte) []byte) []byte {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	tr := tar.NewReader(gr)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+
+		content = fn(hdr.Name, content)
+		if content == nil {
+			continue
+		}
+
+		hdr.Size = int64(len(content))
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err = tw.Write(content)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	return out.Bytes()
+}
+
+func TestPackUnpackRoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	archive := packTestBundle(t, priv)
+
+	b, err := Unpack(bytes.NewReader(archive), VerifyOptions{TrustedKeys: []ed25519.PublicKey{pub}})
+	require.NoError(t, err)
+
+	require.Equal(t, "gmail", b.Manifest.Name)
+	require.Equal(t, "1.2.0", b.Manifest.Version)
+	require.Equal(t, []byte("# Gmail"), b.Readme)
+	require.Len(t, b.Binaries, 2)
+
+	bin, ok := b.Binary("native", "linux", "amd64")
+	require.True(t, ok)
+	require.Equal(t, []byte("ELF"), bin.Content)
+
+	wasm, ok := b.Binary("wasm", "", "")
+	require.True(t, ok)
+	require.Equal(t, "bin/wasm/gmail.wasm", wasm.Path())
+}
+
+func TestUnpackRejectsUnsigned(t *testing.T) {
+	archive := packTestBundle(t, nil)
+
+	_, err := Unpack(bytes.NewReader(archive), VerifyOptions{})
+	require.ErrorIs(t, err, ErrUnsigned)
+
+	_, err = Unpack(bytes.NewReader(archive), VerifyOptions{AllowUnsigned: true})
+	require.NoError(t, err)
+}
+
+func TestUnpackRejectsUntrustedKey(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	other, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	_, err = Verify(bytes.NewReader(packTestBundle(t, priv)), VerifyOptions{TrustedKeys: []ed25519.PublicKey{other}})
+	require.ErrorIs(t, err, ErrBadSignature)
+}
+
+func TestUnpackRejectsTamperedContent(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	opts := VerifyOptions{TrustedKeys: []ed25519.PublicKey{pub}}
+	archive := packTestBundle(t, priv)
+
+	tests := []struct {
+		name string
+		fn   func(name string, content []byte) []byte
+		want error
+	}{
+		{
+			name: "modified binary",
+			fn: func(name string, content []byte) []byte {
+				if name == "bin/native/linux-amd64/gmail" {
+					return []byte("EVIL")
+				}
+				return content
+			},
+			want: ErrTampered,
+		},
+		{
+			name: "modified manifest",
+			fn: func(name string, content []byte) []byte {
+				if name == ManifestFile {
+					return bytes.Replace(content, []byte("1.2.0"), []byte("9.9.9"), 1)
+				}
+				return content
+			},
+			want: ErrBadSignature,
+		},
+		{
+			name: "removed signature",
+			fn: func(name string, content []byte) []byte {
+				if name == SignatureFile {
+					return nil
+				}
+				return content
+			},
+			want: ErrUnsigned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Unpack(bytes.NewReader(rewrite(t, archive, tt.fn)), opts)
+			require.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestPackRequiresIdentity(t *testing.T) {
+	b := testBundle()
+	b.FloFile = []byte("[integration]\nname = \"gmail\"\n")
+
+	_, err := Pack(io.Discard, b, nil)
+	require.ErrorIs(t, err, ErrMalformed)
+}
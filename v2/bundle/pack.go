@@ -0,0 +1,162 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+type packedFile struct {
+	path    string
+	mode    int64
+	content []byte
+}
+
+// Pack writes b to w as a bundle signed with key and returns the manifest it
+// wrote. A nil key produces an unsigned bundle, which Unpack only accepts when
+// VerifyOptions.AllowUnsigned is set.
+func Pack(w io.Writer, b *Bundle, key ed25519.PrivateKey) (*Manifest, error) {
+	if b == nil {
+		return nil, errors.New("bundle is nil")
+	}
+
+	if key != nil && len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid ed25519 private key size %d", len(key))
+	}
+
+	name, version, err := readIdentity(b.FloFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b.Definitions) > 0 && !json.Valid(b.Definitions) {
+		return nil, fmt.Errorf("%w: definitions are not valid JSON", ErrMalformed)
+	}
+
+	files, err := collectFiles(b)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		Name:          name,
+		Version:       version,
+		CreatedAt:     time.Now().UTC().Truncate(time.Second),
+		Files:         make([]FileEntry, 0, len(files)),
+		Binaries:      b.Binaries,
+	}
+
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, FileEntry{
+			Path:   f.path,
+			SHA256: digest(f.content),
+			Size:   int64(len(f.content)),
+		})
+	}
+
+	if key != nil {
+		manifest.PublicKey = hex.EncodeToString(key.Public().(ed25519.PublicKey))
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	head := []packedFile{{path: ManifestFile, mode: 0o644, content: manifestBytes}}
+	if key != nil {
+		head = append(head, packedFile{path: SignatureFile, mode: 0o644, content: ed25519.Sign(key, manifestBytes)})
+	}
+
+	if err := writeArchive(w, append(head, files...), manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	b.Manifest = manifest
+
+	return manifest, nil
+}
+
+func collectFiles(b *Bundle) ([]packedFile, error) {
+	if len(b.Readme) == 0 {
+		return nil, fmt.Errorf("%w: README is required", ErrMalformed)
+	}
+
+	files := []packedFile{
+		{path: FloFile, mode: 0o644, content: b.FloFile},
+		{path: ReadmeFile, mode: 0o644, content: b.Readme},
+		{path: DefinitionsFile, mode: 0o644, content: b.Definitions},
+	}
+
+	seen := map[string]bool{}
+	for _, bin := range b.Binaries {
+		if bin.Platform == "" || bin.Name == "" {
+			return nil, fmt.Errorf("%w: binary platform and name are required", ErrMalformed)
+		}
+
+		p, err := cleanPath(bin.Path())
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[p] {
+			return nil, fmt.Errorf("%w: duplicate binary %s", ErrMalformed, p)
+		}
+		seen[p] = true
+
+		files = append(files, packedFile{path: p, mode: 0o755, content: bin.Content})
+	}
+
+	return files, nil
+}
+
+func writeArchive(w io.Writer, files []packedFile, modTime time.Time) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:     f.path,
+			Mode:     f.mode,
+			Size:     int64(len(f.content)),
+			ModTime:  modTime,
+			Typeflag: tar.TypeReg,
+			Format:   tar.FormatPAX,
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+
+		if _, err := tw.Write(f.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.path, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
@@ -0,0 +1,217 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Unpack reads a bundle from r, verifies it according to opts and returns its
+// content. Unsigned, untrusted or tampered bundles are rejected.
+func Unpack(r io.Reader, opts VerifyOptions) (*Bundle, error) {
+	files, err := readArchive(r, opts.maxSize())
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := verifyFiles(files, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		FloFile:     files[FloFile],
+		Readme:      files[ReadmeFile],
+		Definitions: files[DefinitionsFile],
+		Binaries:    make([]Binary, 0, len(manifest.Binaries)),
+		Manifest:    manifest,
+	}
+
+	for _, bin := range manifest.Binaries {
+		content, ok := files[bin.Path()]
+		if !ok {
+			return nil, fmt.Errorf("%w: binary %s is missing", ErrTampered, bin.Path())
+		}
+
+		bin.Content = content
+		b.Binaries = append(b.Binaries, bin)
+	}
+
+	return b, nil
+}
+
+// Verify checks the signature and digests of the bundle read from r without
+// keeping its content, and returns the verified manifest.
+func Verify(r io.Reader, opts VerifyOptions) (*Manifest, error) {
+	b, err := Unpack(r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Manifest, nil
+}
+
+// Open unpacks and verifies the bundle stored at path.
+func Open(path string, opts VerifyOptions) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Unpack(f, opts)
+}
+
+func readArchive(r io.Reader, maxSize int64) (map[string][]byte, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	files := map[string][]byte{}
+	remaining := maxSize
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%w: unsupported entry type for %s", ErrMalformed, hdr.Name)
+		}
+
+		name, err := cleanPath(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := files[name]; dup {
+			return nil, fmt.Errorf("%w: duplicate entry %s", ErrMalformed, name)
+		}
+
+		if hdr.Size > remaining {
+			return nil, fmt.Errorf("%w: bundle exceeds %d bytes", ErrMalformed, maxSize)
+		}
+
+		content, err := io.ReadAll(io.LimitReader(tr, hdr.Size))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+
+		remaining -= int64(len(content))
+		files[name] = content
+	}
+
+	return files, nil
+}
+
+func verifyFiles(files map[string][]byte, opts VerifyOptions) (*Manifest, error) {
+	manifestBytes, ok := files[ManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is missing", ErrMalformed, ManifestFile)
+	}
+
+	if err := verifySignature(manifestBytes, files[SignatureFile], opts); err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest: %w", ErrMalformed, err)
+	}
+
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrMalformed, manifest.FormatVersion)
+	}
+
+	listed := make(map[string]bool, len(manifest.Files))
+	for _, entry := range manifest.Files {
+		content, ok := files[entry.Path]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is missing", ErrTampered, entry.Path)
+		}
+
+		if int64(len(content)) != entry.Size || digest(content) != entry.SHA256 {
+			return nil, fmt.Errorf("%w: digest mismatch for %s", ErrTampered, entry.Path)
+		}
+
+		listed[entry.Path] = true
+	}
+
+	for name := range files {
+		if name != ManifestFile && name != SignatureFile && !listed[name] {
+			return nil, fmt.Errorf("%w: unexpected file %s", ErrTampered, name)
+		}
+	}
+
+	for _, required := range []string{FloFile, ReadmeFile, DefinitionsFile} {
+		if !listed[required] {
+			return nil, fmt.Errorf("%w: %s is missing", ErrMalformed, required)
+		}
+	}
+
+	name, version, err := readIdentity(files[FloFile])
+	if err != nil {
+		return nil, err
+	}
+
+	if name != manifest.Name || version != manifest.Version {
+		return nil, fmt.Errorf("%w: manifest identity %s@%s does not match flo.toml %s@%s",
+			ErrTampered, manifest.Name, manifest.Version, name, version)
+	}
+
+	return &manifest, nil
+}
+
+func verifySignature(manifest, signature []byte, opts VerifyOptions) error {
+	if signature == nil {
+		if opts.AllowUnsigned {
+			return nil
+		}
+
+		return ErrUnsigned
+	}
+
+	if len(opts.TrustedKeys) == 0 {
+		if opts.AllowUnsigned {
+			return nil
+		}
+
+		return fmt.Errorf("%w: no trusted keys configured", ErrBadSignature)
+	}
+
+	for _, key := range opts.TrustedKeys {
+		if len(key) == ed25519.PublicKeySize && ed25519.Verify(key, manifest, signature) {
+			return nil
+		}
+	}
+
+	return ErrBadSignature
+}
@@ -0,0 +1,66 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rs/xid"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/redact"
+)
+
+// Secrets returns the credential values held by an auth context, for use with WithSecrets.
+func Secrets(auth *sdkcontext.AuthContext) []string {
+	return redact.AuthSecrets(auth)
+}
+
+// WithAuthContext redacts every credential held by auth.
+func WithAuthContext(auth *sdkcontext.AuthContext) Option {
+	return WithSecrets(Secrets(auth)...)
+}
+
+// WrapAuth returns an sdk.Auth whose authenticated clients send requests
+// through the recorder and whose auth contexts' credentials are redacted from
+// the cassette.
+func WrapAuth(auth sdk.Auth, r *Recorder) sdk.Auth {
+	return &recordedAuth{Auth: auth, recorder: r}
+}
+
+type recordedAuth struct {
+	sdk.Auth
+
+	recorder *Recorder
+}
+
+func (a *recordedAuth) GetAuthContext(ctx context.Context, connectionID xid.ID) (*sdkcontext.AuthContext, error) {
+	auth, err := a.Auth.GetAuthContext(ctx, connectionID)
+	if err == nil {
+		a.recorder.redactor.AddSecrets(Secrets(auth)...)
+	}
+
+	return auth, err
+}
+
+func (a *recordedAuth) CreateAuthenticatedClient(ctx context.Context, connectionID xid.ID) (*http.Client, error) {
+	client, err := a.Auth.CreateAuthenticatedClient(ctx, connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.recorder.WrapClient(client), nil
+}
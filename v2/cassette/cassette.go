@@ -0,0 +1,206 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cassette records HTTP interactions to files and replays them, so
+// integration tests that talk to SaaS APIs can run without network access.
+//
+// A Recorder is an http.RoundTripper. In record mode it forwards requests to
+// the real transport and stores each request/response pair, with credentials
+// redacted, in a cassette file. In replay mode it serves responses from the
+// cassette and never touches the network.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatVersion is the version of the cassette file format written by Save.
+const FormatVersion = 1
+
+// Cassette is the on-disk collection of recorded interactions.
+type Cassette struct {
+	// Version is the cassette file format version
+	Version int `json:"version"`
+
+	// Interactions are the recorded request/response pairs in recording order
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	// Request is the redacted request
+	Request Request `json:"request"`
+
+	// Response is the redacted response
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	// Method is the HTTP method
+	Method string `json:"method"`
+
+	// URL is the full request URL
+	URL string `json:"url"`
+
+	// Headers are the request headers
+	Headers http.Header `json:"headers,omitempty"`
+
+	// Body is the request body
+	Body Body `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	// StatusCode is the HTTP status code
+	StatusCode int `json:"statusCode"`
+
+	// Headers are the response headers
+	Headers http.Header `json:"headers,omitempty"`
+
+	// Body is the response body
+	Body Body `json:"body,omitempty"`
+}
+
+// Body is an HTTP body. It is stored as text when it is valid UTF-8 and as
+// base64 otherwise.
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return fmt.Errorf("cassette: body must be a string or {\"base64\": ...}: %w", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return fmt.Errorf("cassette: invalid base64 body: %w", err)
+	}
+
+	*b = raw
+	return nil
+}
+
+// Load reads a cassette file.
+func Load(path string) (*Cassette, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(content, &c); err != nil {
+		return nil, fmt.Errorf("cassette: decode %s: %w", path, err)
+	}
+
+	if c.Version != FormatVersion {
+		return nil, fmt.Errorf("cassette: %s has unsupported version %d", path, c.Version)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating parent directories as needed.
+// The output is stable so re-recording unchanged traffic produces no diff.
+func (c *Cassette) Save(path string) error {
+	c.Version = FormatVersion
+	if c.Interactions == nil {
+		c.Interactions = []*Interaction{}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// toHTTP builds an *http.Response for req from the recording.
+func (r *Response) toHTTP(req *http.Request) *http.Response {
+	header := r.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// readBody drains and restores an HTTP body.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	content, err := io.ReadAll(*body)
+	closeErr := (*body).Close()
+	*body = io.NopCloser(bytes.NewReader(content))
+
+	return content, errors.Join(err, closeErr)
+}
+
+// isBinaryContent reports whether a content type is unlikely to carry credentials.
+func isBinaryContent(contentType string) bool {
+	ct := strings.ToLower(contentType)
+	for _, prefix := range []string{"image/", "audio/", "video/", "application/octet-stream", "application/pdf", "application/zip"} {
+		if strings.HasPrefix(ct, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,160 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+)
+
+// authTransport adds a bearer token the way oauth2.Transport does.
+type authTransport struct {
+	token string
+	calls atomic.Int32
+	next  http.RoundTripper
+}
+
+func (a *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	a.calls.Add(1)
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return a.next.RoundTrip(req)
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer live-token-123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc123")
+		_, _ = io.WriteString(w, `{"path":"`+r.URL.Path+`","echo":`+string(body)+`,"refresh_token":"rt-999","owner":"api-key-456"}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func get(t *testing.T, client *http.Client, url, body string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := client.Do(req)
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+
+	content, err := io.ReadAll(rsp.Body)
+	require.NoError(t, err)
+	return rsp.StatusCode, string(content)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := newServer(t)
+	path := filepath.Join(t.TempDir(), "contacts.json")
+	auth := &sdkcontext.AuthContext{AccessToken: "live-token-123", Key: "api-key-456"}
+
+	rec, err := New(path, WithMode(ModeRecord), WithAuthContext(auth))
+	require.NoError(t, err)
+
+	live := &authTransport{token: auth.AccessToken, next: http.DefaultTransport}
+	client := rec.WrapClient(&http.Client{Transport: live})
+
+	status, body := get(t, client, srv.URL+"/contacts?api_key=api-key-456&page=1", `{"name":"Ada","password":"hunter22"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, body, "rt-999")
+	_, _ = get(t, client, srv.URL+"/contacts?page=2", `{}`)
+	require.NoError(t, rec.Stop())
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, secret := range []string{"live-token-123", "api-key-456", "hunter22", "rt-999", "abc123"} {
+		require.NotContains(t, string(raw), secret)
+	}
+	require.Contains(t, string(raw), Redacted)
+
+	srv.Close()
+
+	replay, err := New(path, WithAuthContext(&sdkcontext.AuthContext{AccessToken: "other-token", Key: "other-key"}))
+	require.NoError(t, err)
+
+	offline := &authTransport{token: "other-token", next: http.DefaultTransport}
+	client = replay.WrapClient(&http.Client{Transport: offline})
+
+	status, body = get(t, client, srv.URL+"/contacts?page=1&api_key=other-key", `{"password":"different","name":"Ada"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, body, `"path":"/contacts"`)
+	require.Zero(t, offline.calls.Load())
+
+	_, err = client.Get(srv.URL + "/contacts?page=1")
+	require.ErrorIs(t, err, ErrNoMatch)
+
+	require.Len(t, replay.Unused(), 1)
+}
+
+func TestMatchersAndRepeats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.json")
+	c := &Cassette{Interactions: []*Interaction{
+		{
+			Request:  Request{Method: http.MethodGet, URL: "https://api.example.com/v1/items?x=1"},
+			Response: Response{StatusCode: 200, Body: Body("first")},
+		},
+		{
+			Request:  Request{Method: http.MethodGet, URL: "https://api.example.com/v1/items?x=2"},
+			Response: Response{StatusCode: 200, Body: Body{0xff, 0xfe}},
+		},
+	}}
+	require.NoError(t, c.Save(path))
+
+	rec, err := New(path, WithMatcher(MatchAll(MatchMethod, MatchPath)), WithRepeats())
+	require.NoError(t, err)
+
+	client := rec.Client()
+	for _, want := range []string{"first", "\xff\xfe", "first"} {
+		rsp, err := client.Get("http://localhost/v1/items")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(rsp.Body)
+		require.Equal(t, want, string(body))
+	}
+
+	_, err = New(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	auto, err := New(filepath.Join(t.TempDir(), "missing.json"), WithMode(ModeAuto))
+	require.NoError(t, err)
+	require.Equal(t, ModeRecord, auto.Mode())
+}
+
+func TestRedactForm(t *testing.T) {
+	r := NewRedactor(nil, []string{"code"}, nil)
+	out := r.Body([]byte("grant_type=authorization_code&code=xyz&client_secret=s3cr3t"), "application/x-www-form-urlencoded")
+	require.Equal(t, "grant_type=authorization_code&code=%5BREDACTED%5D&client_secret=%5BREDACTED%5D", string(out))
+}
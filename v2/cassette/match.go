@@ -0,0 +1,99 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+)
+
+// Matcher reports whether an incoming request, already redacted, matches a
+// recorded one.
+type Matcher func(incoming, recorded Request) bool
+
+// MatchMethod matches the HTTP method.
+func MatchMethod(incoming, recorded Request) bool {
+	return incoming.Method == recorded.Method
+}
+
+// MatchURL matches the full URL, ignoring the order of query parameters.
+func MatchURL(incoming, recorded Request) bool {
+	a, errA := url.Parse(incoming.URL)
+	b, errB := url.Parse(recorded.URL)
+	if errA != nil || errB != nil {
+		return incoming.URL == recorded.URL
+	}
+
+	return a.Scheme == b.Scheme && a.Host == b.Host && a.Path == b.Path &&
+		reflect.DeepEqual(a.Query(), b.Query())
+}
+
+// MatchPath matches the URL path and ignores the host and query.
+func MatchPath(incoming, recorded Request) bool {
+	a, errA := url.Parse(incoming.URL)
+	b, errB := url.Parse(recorded.URL)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	return a.Path == b.Path
+}
+
+// MatchBody matches the request body. JSON bodies are compared semantically.
+func MatchBody(incoming, recorded Request) bool {
+	if bytes.Equal(incoming.Body, recorded.Body) {
+		return true
+	}
+
+	var a, b any
+	if json.Unmarshal(incoming.Body, &a) != nil || json.Unmarshal(recorded.Body, &b) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// MatchHeaders returns a Matcher comparing the given request headers.
+func MatchHeaders(names ...string) Matcher {
+	return func(incoming, recorded Request) bool {
+		for _, name := range names {
+			name = http.CanonicalHeaderKey(name)
+			if !reflect.DeepEqual(incoming.Headers.Values(name), recorded.Headers.Values(name)) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// MatchAll combines matchers; a request matches when every matcher agrees.
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(incoming, recorded Request) bool {
+		for _, m := range matchers {
+			if !m(incoming, recorded) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// DefaultMatcher matches on method and URL.
+var DefaultMatcher = MatchAll(MatchMethod, MatchURL)
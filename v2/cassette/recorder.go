@@ -0,0 +1,302 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder talks to the network.
+type Mode string
+
+const (
+	// ModeReplay serves responses from the cassette and fails on unmatched requests
+	ModeReplay Mode = "replay"
+
+	// ModeRecord forwards requests to the real transport and overwrites the cassette
+	ModeRecord Mode = "record"
+
+	// ModeAuto replays when the cassette exists and records otherwise
+	ModeAuto Mode = "auto"
+
+	// ModePassthrough forwards requests without recording
+	ModePassthrough Mode = "passthrough"
+)
+
+// EnvMode is the environment variable read by ModeFromEnv.
+const EnvMode = "WAKFLO_CASSETTE_MODE"
+
+// ModeFromEnv returns the mode named by $WAKFLO_CASSETTE_MODE, or fallback when unset.
+func ModeFromEnv(fallback Mode) Mode {
+	switch m := Mode(strings.ToLower(os.Getenv(EnvMode))); m {
+	case ModeReplay, ModeRecord, ModeAuto, ModePassthrough:
+		return m
+	default:
+		return fallback
+	}
+}
+
+// ErrNoMatch is returned in replay mode when no recorded interaction matches a request.
+var ErrNoMatch = errors.New("cassette: no recorded interaction matches request")
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithMode sets the recorder mode. Defaults to ModeReplay.
+func WithMode(mode Mode) Option {
+	return func(r *Recorder) { r.mode = mode }
+}
+
+// WithMatcher sets how requests are matched to recordings. Defaults to DefaultMatcher.
+func WithMatcher(m Matcher) Option {
+	return func(r *Recorder) { r.matcher = m }
+}
+
+// WithTransport sets the transport used in record and passthrough modes.
+// Defaults to http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) { r.transport = rt }
+}
+
+// WithRedactHeaders redacts additional header values.
+func WithRedactHeaders(headers ...string) Option {
+	return func(r *Recorder) { r.redactHeaders = append(r.redactHeaders, headers...) }
+}
+
+// WithRedactParams redacts additional query, form and JSON keys.
+func WithRedactParams(params ...string) Option {
+	return func(r *Recorder) { r.redactParams = append(r.redactParams, params...) }
+}
+
+// WithSecrets redacts literal credential values wherever they appear.
+func WithSecrets(secrets ...string) Option {
+	return func(r *Recorder) { r.secrets = append(r.secrets, secrets...) }
+}
+
+// WithRepeats lets a recorded interaction be served more than once in replay.
+// By default each interaction is served once, in recording order.
+func WithRepeats() Option {
+	return func(r *Recorder) { r.repeats = true }
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions.
+type Recorder struct {
+	path          string
+	mode          Mode
+	matcher       Matcher
+	transport     http.RoundTripper
+	redactHeaders []string
+	redactParams  []string
+	secrets       []string
+	repeats       bool
+
+	redactor *Redactor
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// New creates a Recorder backed by the cassette file at path. In replay mode
+// the cassette must exist.
+func New(path string, opts ...Option) (*Recorder, error) {
+	r := &Recorder{path: path, mode: ModeReplay, matcher: DefaultMatcher}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+
+	r.redactor = NewRedactor(r.redactHeaders, r.redactParams, r.secrets)
+
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+
+	switch r.mode {
+	case ModeReplay:
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	case ModeRecord, ModePassthrough:
+		r.cassette = &Cassette{Version: FormatVersion}
+	default:
+		return nil, fmt.Errorf("cassette: unknown mode %q", r.mode)
+	}
+
+	return r, nil
+}
+
+// Mode returns the effective mode; ModeAuto is resolved when the recorder is created.
+func (r *Recorder) Mode() Mode { return r.mode }
+
+// Redactor returns the redactor applied to recordings.
+func (r *Recorder) Redactor() *Redactor { return r.redactor }
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Interaction(nil), r.cassette.Interactions...)
+}
+
+// Client returns an *http.Client that sends requests through the recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// WrapClient returns a copy of client whose transport is routed through the
+// recorder. Transports that add credentials, such as oauth2.Transport, keep
+// doing so in record mode and are bypassed entirely in replay mode.
+func (r *Recorder) WrapClient(client *http.Client) *http.Client {
+	if client == nil {
+		return r.Client()
+	}
+
+	wrapped := *client
+	inner := client.Transport
+	if inner == nil {
+		inner = r.transport
+	}
+
+	wrapped.Transport = &chained{recorder: r, next: inner}
+	return &wrapped
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.roundTrip(req, r.transport)
+}
+
+func (r *Recorder) roundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	incoming := r.redactor.request(Request{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: req.Header,
+		Body:    body,
+	})
+
+	if r.mode == ModeReplay {
+		return r.replay(req, incoming)
+	}
+
+	rsp, err := next.RoundTrip(req)
+	if err != nil || r.mode == ModePassthrough {
+		return rsp, err
+	}
+
+	rspBody, err := readBody(&rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: incoming,
+		Response: r.redactor.response(Response{
+			StatusCode: rsp.StatusCode,
+			Headers:    rsp.Header,
+			Body:       rspBody,
+		}),
+	})
+	r.mu.Unlock()
+
+	return rsp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, incoming Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fallback := -1
+	for i, it := range r.cassette.Interactions {
+		if !r.matcher(incoming, it.Request) {
+			continue
+		}
+
+		if !r.used[i] {
+			r.used[i] = true
+			return it.Response.toHTTP(req), nil
+		}
+
+		if r.repeats && fallback < 0 {
+			fallback = i
+		}
+	}
+
+	if fallback >= 0 {
+		return r.cassette.Interactions[fallback].Response.toHTTP(req), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoMatch, incoming.Method, incoming.URL)
+}
+
+// Unused returns the recorded interactions that were never served in replay mode.
+func (r *Recorder) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []*Interaction
+	for i, it := range r.cassette.Interactions {
+		if i < len(r.used) && !r.used[i] {
+			unused = append(unused, it)
+		}
+	}
+
+	return unused
+}
+
+// Stop writes the cassette in record mode. It is a no-op in other modes.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.Save(r.path)
+}
+
+// chained routes a client's requests through the recorder before its own transport.
+type chained struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+func (c *chained) RoundTrip(req *http.Request) (*http.Response, error) {
+	return c.recorder.roundTrip(req, c.next)
+}
@@ -0,0 +1,216 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces every credential written to a cassette.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactHeaders are the headers whose values are always redacted.
+var DefaultRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+	"X-Access-Token",
+}
+
+// DefaultRedactParams are the query, form and JSON keys whose values are
+// always redacted.
+var DefaultRedactParams = []string{
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"client_secret",
+	"api_key",
+	"apikey",
+	"token",
+	"password",
+	"secret",
+}
+
+// Redactor removes credentials from recorded interactions. Incoming requests
+// are redacted the same way before matching, so recordings made with one set
+// of credentials replay with another.
+type Redactor struct {
+	headers map[string]bool
+	params  map[string]bool
+	keyExpr *regexp.Regexp
+
+	mu      sync.RWMutex
+	secrets []string
+}
+
+// NewRedactor creates a Redactor for the given headers, parameter names and
+// literal secret values in addition to the defaults.
+func NewRedactor(headers, params, secrets []string) *Redactor {
+	r := &Redactor{headers: map[string]bool{}, params: map[string]bool{}}
+
+	for _, h := range append(append([]string{}, DefaultRedactHeaders...), headers...) {
+		r.headers[http.CanonicalHeaderKey(h)] = true
+	}
+
+	keys := make([]string, 0, len(DefaultRedactParams)+len(params))
+	for _, p := range append(append([]string{}, DefaultRedactParams...), params...) {
+		p = strings.ToLower(p)
+		if !r.params[p] {
+			r.params[p] = true
+			keys = append(keys, regexp.QuoteMeta(p))
+		}
+	}
+	r.keyExpr = regexp.MustCompile(`(?i)("(?:` + strings.Join(keys, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+	r.AddSecrets(secrets...)
+	return r
+}
+
+// AddSecrets registers literal values that must never appear in a cassette.
+// Values shorter than four characters are ignored to avoid redacting noise.
+func (r *Redactor) AddSecrets(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range secrets {
+		if len(s) >= 4 {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+
+	// Replace longer secrets first so a secret containing another is fully removed.
+	sort.SliceStable(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+}
+
+// Header redacts a copy of h.
+func (r *Redactor) Header(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+
+	out := make(http.Header, len(h))
+	for k, values := range h {
+		redacted := make([]string, len(values))
+		for i, v := range values {
+			if r.headers[http.CanonicalHeaderKey(k)] {
+				redacted[i] = Redacted
+			} else {
+				redacted[i] = r.String(v)
+			}
+		}
+		out[k] = redacted
+	}
+
+	return out
+}
+
+// URL redacts the query parameters and secrets of a URL.
+func (r *Redactor) URL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return r.String(raw)
+	}
+
+	if u.User != nil {
+		u.User = url.User(Redacted)
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery = r.query(u.RawQuery)
+	}
+
+	return r.String(u.String())
+}
+
+// Body redacts JSON keys, form parameters and secrets in a body.
+func (r *Redactor) Body(body []byte, contentType string) []byte {
+	if len(body) == 0 || isBinaryContent(contentType) {
+		return body
+	}
+
+	if strings.HasPrefix(strings.ToLower(contentType), "application/x-www-form-urlencoded") {
+		return []byte(r.String(r.query(string(body))))
+	}
+
+	body = r.keyExpr.ReplaceAll(body, []byte(`${1}"`+Redacted+`"`))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, s := range r.secrets {
+		body = bytes.ReplaceAll(body, []byte(s), []byte(Redacted))
+	}
+
+	return body
+}
+
+// String replaces literal secrets in s.
+func (r *Redactor) String(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+
+	return s
+}
+
+// query redacts the values of sensitive parameters in an encoded query. The
+// parameter order is preserved so recordings stay stable.
+func (r *Redactor) query(raw string) string {
+	parts := strings.Split(raw, "&")
+	for i, part := range parts {
+		key, _, found := strings.Cut(part, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+
+		if found && r.params[strings.ToLower(name)] {
+			parts[i] = key + "=" + url.QueryEscape(Redacted)
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// request redacts a recorded request.
+func (r *Redactor) request(req Request) Request {
+	return Request{
+		Method:  req.Method,
+		URL:     r.URL(req.URL),
+		Headers: r.Header(req.Headers),
+		Body:    r.Body(req.Body, req.Headers.Get("Content-Type")),
+	}
+}
+
+// response redacts a recorded response.
+func (r *Redactor) response(rsp Response) Response {
+	return Response{
+		StatusCode: rsp.StatusCode,
+		Headers:    r.Header(rsp.Headers),
+		Body:       r.Body(rsp.Body, rsp.Headers.Get("Content-Type")),
+	}
+}
@@ -12,6 +12,9 @@ type DynamicOptionsResponse struct {
 	Items    any                  `json:"items"`
 }
 
+// DefaultDynamicOptionsLimit is the page size used when a filter sets no limit.
+const DefaultDynamicOptionsLimit = 10
+
 type DynamicOptionsFilterParams struct {
 	Offset     int    `json:"offset"` // The offset of the first item to return (default: 0)
 	Limit      int    `json:"limit"`  // The maximum number of items to return (default: 10)
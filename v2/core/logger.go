@@ -38,6 +38,9 @@ type LogEntry struct {
 	Timestamp time.Time `json:"timestamp"` // Timestamp of the log
 	Level     LogLevel  `json:"level"`     // Severity level of the log
 	Message   string    `json:"message"`   // Log message itself
+
+	// Fields holds structured key-value data attached to the entry
+	Fields map[string]interface{} `json:"fields,omitempty"`
 }
 
 // Logger provides a centralized interface for managing logs.
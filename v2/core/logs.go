@@ -15,6 +15,7 @@
 package core
 
 import (
+	"maps"
 	"time"
 
 	"github.com/rs/xid"
@@ -71,8 +72,10 @@ type LogLine struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// Log creates LogBuilders for one step run. Each builder works on its own copy
+// of the entry, so builders may be used concurrently.
 type Log struct {
-	ops     *WriteLogLineOpts
+	ops     WriteLogLineOpts
 	onWrite func(WriteLogLineOpts)
 }
 
@@ -83,43 +86,42 @@ func NewLog(
 	onWrite func(WriteLogLineOpts),
 ) *Log {
 	return &Log{
-		ops: &WriteLogLineOpts{
+		ops: WriteLogLineOpts{
 			StepRunID: stepRunID,
 			FlowID:    flowID,
 			ProjectID: projectID,
-			Message:   "",
-			Metadata:  nil,
 		},
 		onWrite: onWrite,
 	}
 }
 
+func (b *Log) level(level LogLineLevel) *LogBuilder {
+	ops := b.ops
+	lvl := level.String()
+	ops.Level = &lvl
+	return NewLogBuilder(&ops, b.onWrite)
+}
+
 func (b *Log) Error() *LogBuilder {
-	lvl := LogLineLevelError.String()
-	b.ops.Level = &lvl
-	return NewLogBuilder(b.ops, b.onWrite)
+	return b.level(LogLineLevelError)
 }
 
 func (b *Log) Info() *LogBuilder {
-	lvl := LogLineLevelInfo.String()
-	b.ops.Level = &lvl
-	return NewLogBuilder(b.ops, b.onWrite)
+	return b.level(LogLineLevelInfo)
 }
 
 func (b *Log) Warn() *LogBuilder {
-	lvl := LogLineLevelWarn.String()
-	b.ops.Level = &lvl
-	return NewLogBuilder(b.ops, b.onWrite)
+	return b.level(LogLineLevelWarn)
 }
 
 func (b *Log) Debug() *LogBuilder {
-	lvl := LogLineLevelDebug.String()
-	b.ops.Level = &lvl
-	return NewLogBuilder(b.ops, b.onWrite)
+	return b.level(LogLineLevelDebug)
 }
 
+// LogBuilder completes a log line. It copies the options it is given, and
+// every Msg call delivers an independent copy to onWrite.
 type LogBuilder struct {
-	ops     *WriteLogLineOpts
+	ops     WriteLogLineOpts
 	onWrite func(WriteLogLineOpts)
 }
 
@@ -127,7 +129,7 @@ func NewLogBuilder(
 	ops *WriteLogLineOpts,
 	onWrite func(WriteLogLineOpts),
 ) *LogBuilder {
-	return &LogBuilder{ops: ops, onWrite: onWrite}
+	return &LogBuilder{ops: ops.Clone(), onWrite: onWrite}
 }
 
 func (b *LogBuilder) Meta(meta map[string]interface{}) *LogBuilder {
@@ -136,9 +138,32 @@ func (b *LogBuilder) Meta(meta map[string]interface{}) *LogBuilder {
 }
 
 func (b *LogBuilder) Msg(message string) {
-	b.ops.Message = message
+	ops := b.ops.Clone()
+	ops.Message = message
 	t := time.Now()
-	b.ops.CreatedAt = &t
+	ops.CreatedAt = &t
+
+	b.onWrite(ops)
+}
+
+// Clone returns a copy of o that shares no pointers or maps with it.
+func (o *WriteLogLineOpts) Clone() WriteLogLineOpts {
+	out := *o
+	if o.StepRunID != nil {
+		id := *o.StepRunID
+		out.StepRunID = &id
+	}
+	if o.CreatedAt != nil {
+		t := *o.CreatedAt
+		out.CreatedAt = &t
+	}
+	if o.Level != nil {
+		lvl := *o.Level
+		out.Level = &lvl
+	}
+	if o.Metadata != nil {
+		out.Metadata = maps.Clone(o.Metadata)
+	}
 
-	b.onWrite(*b.ops)
+	return out
 }
@@ -0,0 +1,406 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultLogBufferSize is how many entries a StructuredLogger keeps in memory by default.
+const DefaultLogBufferSize = 1000
+
+// badKey is the field name given to a trailing key-value argument without a key.
+const badKey = "!BADKEY"
+
+// Contextual field names attached from a LogScope.
+const (
+	LogFieldProjectID = "project_id"
+	LogFieldFlowID    = "flow_id"
+	LogFieldRunID     = "run_id"
+	LogFieldStepID    = "step_id"
+)
+
+var logLevelRank = map[LogLevel]int{
+	LevelDebug:   0,
+	LevelInfo:    1,
+	LevelWarning: 2,
+	LevelError:   3,
+}
+
+// Enabled reports whether entries at level l pass a minimum level of min.
+// Unknown levels are always enabled.
+func (l LogLevel) Enabled(min LogLevel) bool {
+	rank, ok := logLevelRank[l]
+	if !ok {
+		return true
+	}
+
+	return rank >= logLevelRank[min]
+}
+
+// LogScope identifies the execution a logger belongs to. Non-empty IDs are
+// attached to every entry as fields.
+type LogScope struct {
+	ProjectID string
+	FlowID    string
+	RunID     string
+	StepID    string
+}
+
+func (s LogScope) fields() map[string]interface{} {
+	fields := map[string]interface{}{}
+	for key, value := range map[string]string{
+		LogFieldProjectID: s.ProjectID,
+		LogFieldFlowID:    s.FlowID,
+		LogFieldRunID:     s.RunID,
+		LogFieldStepID:    s.StepID,
+	} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+
+	return fields
+}
+
+// LogSinkFunc adapts a function to a LogSink.
+type LogSinkFunc func(ctx context.Context, entry LogEntry) error
+
+func (f LogSinkFunc) Write(ctx context.Context, entry LogEntry) error {
+	return f(ctx, entry)
+}
+
+// ZerologSink writes entries to a zerolog logger at the matching level.
+type ZerologSink struct {
+	Logger zerolog.Logger
+}
+
+func (s ZerologSink) Write(ctx context.Context, entry LogEntry) error {
+	var event *zerolog.Event
+	switch entry.Level {
+	case LevelDebug:
+		event = s.Logger.Debug()
+	case LevelWarning:
+		event = s.Logger.Warn()
+	case LevelError:
+		event = s.Logger.Error()
+	default:
+		event = s.Logger.Info()
+	}
+
+	event.Time(zerolog.TimestampFieldName, entry.Timestamp).Fields(entry.Fields).Msg(entry.Message)
+	return nil
+}
+
+// LoggerOption configures a StructuredLogger.
+type LoggerOption func(*loggerConfig)
+
+type loggerConfig struct {
+	level      LogLevel
+	bufferSize int
+	sinks      []LogSink
+	scope      LogScope
+	ctx        context.Context
+	onSinkErr  func(LogSink, error)
+}
+
+// WithLogLevel drops entries below level. Defaults to LevelDebug.
+func WithLogLevel(level LogLevel) LoggerOption {
+	return func(c *loggerConfig) { c.level = level }
+}
+
+// WithLogBufferSize keeps the last n entries in memory. Zero disables the buffer.
+func WithLogBufferSize(n int) LoggerOption {
+	return func(c *loggerConfig) { c.bufferSize = max(n, 0) }
+}
+
+// WithLogSinks adds sinks that receive every entry passing the level filter.
+func WithLogSinks(sinks ...LogSink) LoggerOption {
+	return func(c *loggerConfig) { c.sinks = append(c.sinks, sinks...) }
+}
+
+// WithLogScope attaches the IDs of the current execution to every entry.
+func WithLogScope(scope LogScope) LoggerOption {
+	return func(c *loggerConfig) { c.scope = scope }
+}
+
+// WithLogContext sets the context passed to sinks. Defaults to context.Background().
+func WithLogContext(ctx context.Context) LoggerOption {
+	return func(c *loggerConfig) { c.ctx = ctx }
+}
+
+// WithSinkErrorHandler is called when a sink fails to write. Errors are ignored by default.
+func WithSinkErrorHandler(fn func(sink LogSink, err error)) LoggerOption {
+	return func(c *loggerConfig) { c.onSinkErr = fn }
+}
+
+// logCore is the state shared by a logger and the loggers derived from it with WithField.
+type logCore struct {
+	cfg loggerConfig
+
+	mu    sync.RWMutex
+	level LogLevel
+	ring  []LogEntry
+	next  int
+	count int
+}
+
+func (c *logCore) append(entry LogEntry) {
+	c.mu.Lock()
+	if len(c.ring) > 0 {
+		c.ring[c.next] = entry
+		c.next = (c.next + 1) % len(c.ring)
+		c.count = min(c.count+1, len(c.ring))
+	}
+	c.mu.Unlock()
+
+	for _, sink := range c.cfg.sinks {
+		if err := sink.Write(c.cfg.ctx, entry); err != nil && c.cfg.onSinkErr != nil {
+			c.cfg.onSinkErr(sink, err)
+		}
+	}
+}
+
+func (c *logCore) entries() []LogEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make([]LogEntry, 0, c.count)
+	start := (c.next - c.count + len(c.ring)) % max(len(c.ring), 1)
+	for i := range c.count {
+		out = append(out, c.ring[(start+i)%len(c.ring)])
+	}
+
+	return out
+}
+
+func (c *logCore) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.ring)
+	c.next, c.count = 0, 0
+}
+
+func (c *logCore) enabled(level LogLevel) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return level.Enabled(c.level)
+}
+
+// StructuredLogger is a Logger that filters by level, keeps recent entries
+// in a bounded ring buffer and fans every entry out to its sinks. Key-value
+// arguments and WithField values are kept as entry fields rather than being
+// formatted into the message. It is safe for concurrent use.
+//
+// Loggers returned by WithField and WithFields share the buffer, sinks and
+// level of their parent; Clone returns a logger with an independent copy of
+// the buffer.
+type StructuredLogger struct {
+	core   *logCore
+	fields map[string]interface{}
+	prefix atomic.Pointer[string]
+}
+
+var _ Logger = (*StructuredLogger)(nil)
+
+// NewStructuredLogger creates a logger with the given options.
+func NewStructuredLogger(opts ...LoggerOption) *StructuredLogger {
+	cfg := loggerConfig{
+		level:      LevelDebug,
+		bufferSize: DefaultLogBufferSize,
+		ctx:        context.Background(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &StructuredLogger{
+		core:   &logCore{cfg: cfg, level: cfg.level, ring: make([]LogEntry, cfg.bufferSize)},
+		fields: cfg.scope.fields(),
+	}
+}
+
+// SetLevel changes the minimum level of this logger and all loggers sharing its buffer.
+func (l *StructuredLogger) SetLevel(level LogLevel) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+
+	l.core.level = level
+}
+
+// Level returns the minimum level.
+func (l *StructuredLogger) Level() LogLevel {
+	l.core.mu.RLock()
+	defer l.core.mu.RUnlock()
+
+	return l.core.level
+}
+
+// Fields returns a copy of the fields attached to every entry.
+func (l *StructuredLogger) Fields() map[string]interface{} {
+	return maps.Clone(l.fields)
+}
+
+func (l *StructuredLogger) log(level LogLevel, message string, fields map[string]interface{}) {
+	if !l.core.enabled(level) {
+		return
+	}
+
+	if prefix := l.prefix.Load(); prefix != nil {
+		message = *prefix + message
+	}
+
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	maps.Copy(merged, l.fields)
+	maps.Copy(merged, fields)
+	if len(merged) == 0 {
+		merged = nil
+	}
+
+	l.core.append(LogEntry{
+		Timestamp: time.Now(),
+		Level:     level,
+		Message:   message,
+		Fields:    merged,
+	})
+}
+
+func (l *StructuredLogger) AddLog(level LogLevel, message string, a ...any) {
+	if len(a) > 0 {
+		message = fmt.Sprintf(message, a...)
+	}
+
+	l.log(level, message, nil)
+}
+
+// SetPrefix prepends prefix to the messages of this logger.
+func (l *StructuredLogger) SetPrefix(prefix string) {
+	l.prefix.Store(&prefix)
+}
+
+func (l *StructuredLogger) GetLogs() []LogEntry {
+	return l.core.entries()
+}
+
+func (l *StructuredLogger) ClearLogs() {
+	l.core.clear()
+}
+
+func (l *StructuredLogger) Info(message string, keysAndValues ...interface{}) {
+	l.log(LevelInfo, message, keyValueFields(keysAndValues))
+}
+
+func (l *StructuredLogger) Infof(message string, a ...any) {
+	l.AddLog(LevelInfo, message, a...)
+}
+
+func (l *StructuredLogger) Warn(message string, keysAndValues ...interface{}) {
+	l.log(LevelWarning, message, keyValueFields(keysAndValues))
+}
+
+func (l *StructuredLogger) Warnf(message string, a ...any) {
+	l.AddLog(LevelWarning, message, a...)
+}
+
+func (l *StructuredLogger) Error(message string, keysAndValues ...interface{}) {
+	l.log(LevelError, message, keyValueFields(keysAndValues))
+}
+
+// Errorf logs the formatted message with err in the "error" field.
+func (l *StructuredLogger) Errorf(err error, message string, a ...any) {
+	if len(a) > 0 {
+		message = fmt.Sprintf(message, a...)
+	}
+
+	var fields map[string]interface{}
+	if err != nil {
+		fields = map[string]interface{}{"error": err.Error()}
+	}
+
+	l.log(LevelError, message, fields)
+}
+
+func (l *StructuredLogger) Debug(message string, keysAndValues ...interface{}) {
+	l.log(LevelDebug, message, keyValueFields(keysAndValues))
+}
+
+func (l *StructuredLogger) Debugf(message string, a ...any) {
+	l.AddLog(LevelDebug, message, a...)
+}
+
+func (l *StructuredLogger) WithField(key string, value interface{}) Logger {
+	return l.WithFields(map[string]interface{}{key: value})
+}
+
+func (l *StructuredLogger) WithFields(fields map[string]interface{}) Logger {
+	merged := maps.Clone(l.fields)
+	if merged == nil {
+		merged = map[string]interface{}{}
+	}
+	maps.Copy(merged, fields)
+
+	return l.derive(l.core, merged)
+}
+
+// Clone returns a logger with the same fields, level and sinks and its own
+// copy of the buffered entries.
+func (l *StructuredLogger) Clone() Logger {
+	l.core.mu.RLock()
+	c := &logCore{
+		cfg:   l.core.cfg,
+		level: l.core.level,
+		ring:  append([]LogEntry(nil), l.core.ring...),
+		next:  l.core.next,
+		count: l.core.count,
+	}
+	l.core.mu.RUnlock()
+
+	return l.derive(c, maps.Clone(l.fields))
+}
+
+func (l *StructuredLogger) derive(c *logCore, fields map[string]interface{}) *StructuredLogger {
+	child := &StructuredLogger{core: c, fields: fields}
+	child.prefix.Store(l.prefix.Load())
+	return child
+}
+
+// keyValueFields converts alternating keys and values to fields. A trailing
+// value without a key is kept under "!BADKEY".
+func keyValueFields(keysAndValues []interface{}) map[string]interface{} {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			fields[badKey] = keysAndValues[i]
+			break
+		}
+
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+
+	return fields
+}
@@ -0,0 +1,107 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type collectSink struct {
+	mu      sync.Mutex
+	entries []LogEntry
+}
+
+func (s *collectSink) Write(ctx context.Context, entry LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func TestStructuredLogger(t *testing.T) {
+	first, second := &collectSink{}, &collectSink{}
+	var sinkErrs []error
+	failing := LogSinkFunc(func(ctx context.Context, entry LogEntry) error { return errors.New("down") })
+
+	logger := NewStructuredLogger(
+		WithLogLevel(LevelInfo),
+		WithLogSinks(first, second, failing),
+		WithLogScope(LogScope{ProjectID: "p1", RunID: "r1"}),
+		WithSinkErrorHandler(func(sink LogSink, err error) { sinkErrs = append(sinkErrs, err) }),
+	)
+
+	logger.Debug("hidden")
+	logger.Info("fetched", "count", 3, "dangling")
+	logger.WithField("contact", "c1").Warnf("retrying %d", 2)
+	logger.Errorf(errors.New("boom"), "sync failed")
+
+	logs := logger.GetLogs()
+	require.Len(t, logs, 3)
+	require.Equal(t, "fetched", logs[0].Message)
+	require.Equal(t, map[string]interface{}{
+		LogFieldProjectID: "p1", LogFieldRunID: "r1", "count": 3, badKey: "dangling",
+	}, logs[0].Fields)
+	require.Equal(t, "retrying 2", logs[1].Message)
+	require.Equal(t, "c1", logs[1].Fields["contact"])
+	require.Equal(t, "boom", logs[2].Fields["error"])
+	require.NotContains(t, logger.Fields(), "contact")
+
+	require.Len(t, first.entries, 3)
+	require.Equal(t, first.entries, second.entries)
+	require.Len(t, sinkErrs, 3)
+
+	clone := logger.Clone()
+	logger.ClearLogs()
+	require.Empty(t, logger.GetLogs())
+	require.Len(t, clone.GetLogs(), 3)
+}
+
+func TestStructuredLoggerRing(t *testing.T) {
+	logger := NewStructuredLogger(WithLogBufferSize(3))
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.WithField("i", i).Info("tick")
+		}()
+	}
+	wg.Wait()
+	require.Len(t, logger.GetLogs(), 3)
+
+	logger.ClearLogs()
+	for i := range 5 {
+		logger.Infof("line %d", i)
+	}
+
+	var messages []string
+	for _, entry := range logger.GetLogs() {
+		messages = append(messages, entry.Message)
+	}
+	require.Equal(t, []string{"line 2", "line 3", "line 4"}, messages)
+
+	logger.SetPrefix("[step] ")
+	logger.SetLevel(LevelError)
+	logger.Warn("dropped")
+	logger.Error("kept")
+	require.Equal(t, "[step] kept", logger.GetLogs()[2].Message)
+}
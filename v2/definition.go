@@ -0,0 +1,240 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/validator"
+)
+
+// operationIDPattern matches the snake_case identifiers used for actions and triggers.
+var operationIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// DefinitionError describes a single problem found in an integration definition.
+type DefinitionError struct {
+	// Path locates the offending value, e.g. "actions.send_email.displayName"
+	Path string `json:"path"`
+
+	// Message describes what is wrong
+	Message string `json:"message"`
+}
+
+func (e DefinitionError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+
+	return e.Path + ": " + e.Message
+}
+
+// DefinitionErrors is returned by ValidateIntegration when an integration is invalid.
+type DefinitionErrors []DefinitionError
+
+func (e DefinitionErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// NewIntegrationDefinition assembles the IntegrationDefinition of an integration
+// from its metadata, actions and triggers. Actions and triggers are keyed by ID.
+func NewIntegrationDefinition(integration Integration) *IntegrationDefinition {
+	meta := integration.Metadata()
+	if meta.Type == "" {
+		meta.Type = IntegrationTypeStandard
+	}
+
+	auth := integration.Auth()
+	def := &IntegrationDefinition{
+		IntegrationMetadata: meta,
+		ID:                  meta.Name,
+		DisplayName:         meta.DisplayName,
+		Actions:             map[string]*ActionDefinition{},
+		Triggers:            map[string]*TriggerDefinition{},
+		Auth:                auth,
+		Metadata:            meta,
+		Implementation:      integration,
+	}
+
+	if def.DisplayName == "" {
+		def.DisplayName = meta.Name
+	}
+
+	for _, action := range integration.Actions() {
+		am := action.Metadata()
+		actionAuth := action.Auth()
+		if actionAuth == nil {
+			actionAuth = auth
+		}
+
+		def.Actions[am.ID] = &ActionDefinition{
+			Name:           am.ID,
+			DisplayName:    am.DisplayName,
+			Description:    am.Description,
+			HelpText:       am.HelpText,
+			Icon:           am.Icon,
+			Type:           am.Type,
+			Auth:           actionAuth,
+			Documentation:  am.Documentation,
+			SampleOutput:   am.SampleOutput,
+			OutputSchema:   am.EffectiveOutputSchema(),
+			Properties:     action.Properties(),
+			Tags:           am.Tags,
+			Implementation: action,
+			Settings:       am.Settings,
+		}
+	}
+
+	for _, trigger := range integration.Triggers() {
+		tm := trigger.Metadata()
+		triggerAuth := trigger.Auth()
+		if triggerAuth == nil {
+			triggerAuth = auth
+		}
+
+		def.Triggers[tm.ID] = &TriggerDefinition{
+			Name:          tm.ID,
+			DisplayName:   tm.DisplayName,
+			Description:   tm.Description,
+			HelpText:      tm.HelpText,
+			Icon:          tm.Icon,
+			Type:          tm.Type,
+			Auth:          triggerAuth,
+			Documentation: tm.Documentation,
+			SampleOutput:  tm.SampleOutput,
+			OutputSchema:  tm.EffectiveOutputSchema(),
+			Properties:    trigger.Props(),
+			Settings: TriggerSettings{
+				Type:     tm.Type,
+				Criteria: tm.Criteria,
+			},
+			Implementation: trigger,
+		}
+	}
+
+	return def
+}
+
+// ValidateIntegration checks an integration's metadata and the metadata and
+// schemas of its actions and triggers. All problems are reported together as
+// DefinitionErrors.
+func ValidateIntegration(integration Integration) error {
+	var errs DefinitionErrors
+	add := func(path string, format string, args ...any) {
+		errs = append(errs, DefinitionError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	meta := integration.Metadata()
+	if err := validator.NewDefaultValidator().Validate(meta); err != nil {
+		add("metadata", "%s", err)
+	}
+
+	if meta.Version != "" {
+		if _, err := semver.StrictNewVersion(meta.Version); err != nil {
+			add("metadata.version", "%q is not a valid semantic version", meta.Version)
+		}
+	}
+
+	if meta.Group != "" && !slices.Contains(meta.Group.Values(), string(meta.Group)) {
+		add("metadata.group", "must be one of %s", strings.Join(meta.Group.Values(), ", "))
+	}
+
+	validateAuth("auth", integration.Auth(), add)
+
+	actions := integration.Actions()
+	triggers := integration.Triggers()
+	if len(actions) == 0 && len(triggers) == 0 {
+		add("", "integration must provide at least one action or trigger")
+	}
+
+	seen := map[string]bool{}
+	for i, action := range actions {
+		am := action.Metadata()
+		path := fmt.Sprintf("actions[%d]", i)
+		if am.ID != "" {
+			path = "actions." + am.ID
+		}
+
+		validateOperation(path, am.ID, am.DisplayName, am.Description, seen, add)
+		validateAuth(path+".auth", action.Auth(), add)
+		if action.Properties() == nil {
+			add(path+".properties", "input schema is required")
+		}
+	}
+
+	seen = map[string]bool{}
+	for i, trigger := range triggers {
+		tm := trigger.Metadata()
+		path := fmt.Sprintf("triggers[%d]", i)
+		if tm.ID != "" {
+			path = "triggers." + tm.ID
+		}
+
+		validateOperation(path, tm.ID, tm.DisplayName, tm.Description, seen, add)
+		validateAuth(path+".auth", trigger.Auth(), add)
+		if trigger.Props() == nil {
+			add(path+".properties", "input schema is required")
+		}
+
+		if tm.Type == "" {
+			add(path+".type", "trigger type is required")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func validateOperation(path, id, displayName, description string, seen map[string]bool, add func(string, string, ...any)) {
+	switch {
+	case id == "":
+		add(path+".id", "is required")
+	case !operationIDPattern.MatchString(id):
+		add(path+".id", "%q must be snake_case", id)
+	case seen[id]:
+		add(path+".id", "%q is declared more than once", id)
+	}
+	seen[id] = true
+
+	if displayName == "" {
+		add(path+".displayName", "is required")
+	}
+
+	if description == "" {
+		add(path+".description", "is required")
+	}
+}
+
+func validateAuth(path string, auth *core.AuthMetadata, add func(string, string, ...any)) {
+	if auth == nil {
+		return
+	}
+
+	if !slices.Contains(auth.Type.Values(), string(auth.Type)) {
+		add(path+".type", "must be one of %s", strings.Join(auth.Type.Values(), ", "))
+	}
+}
@@ -0,0 +1,361 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package docs generates reference documentation for integrations.
+//
+// New builds a Reference from an IntegrationDefinition, describing every
+// action and trigger with its inputs, auth requirements, sample output and
+// trigger criteria. A Reference is written as Markdown or JSON.
+package docs
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/juicycleff/smartform/v1"
+	sdk "github.com/wakflo/go-sdk/v2"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/form"
+)
+
+// Format is an output format of Write.
+type Format string
+
+// Output formats.
+const (
+	FormatMarkdown Format = "markdown"
+	FormatJSON     Format = "json"
+)
+
+// Reference documents an integration.
+type Reference struct {
+	// Name is the identifier of the integration
+	Name string `json:"name"`
+
+	// DisplayName is the human-readable name of the integration
+	DisplayName string `json:"displayName"`
+
+	// Description explains the purpose of the integration
+	Description string `json:"description,omitempty"`
+
+	// Version is the semantic version of the integration
+	Version string `json:"version,omitempty"`
+
+	// Website links to the service the integration connects to
+	Website string `json:"website,omitempty"`
+
+	// DocumentationURL links to externally hosted documentation
+	DocumentationURL string `json:"documentationUrl,omitempty"`
+
+	// Documentation is free-form usage documentation in Markdown
+	Documentation string `json:"documentation,omitempty"`
+
+	// Auth describes the credentials the integration needs
+	Auth *Auth `json:"auth,omitempty"`
+
+	// Actions are the actions of the integration, sorted by ID
+	Actions []*Operation `json:"actions"`
+
+	// Triggers are the triggers of the integration, sorted by ID
+	Triggers []*Operation `json:"triggers"`
+}
+
+// Auth documents an authentication requirement.
+type Auth struct {
+	// Type is the kind of authentication, such as oauth2 or api_key
+	Type string `json:"type"`
+
+	// Required tells whether a connection must be configured
+	Required bool `json:"required"`
+
+	// Inputs are the fields of the connection form
+	Inputs []*Input `json:"inputs,omitempty"`
+}
+
+// Operation documents an action or trigger.
+type Operation struct {
+	// ID is the identifier of the operation within its integration
+	ID string `json:"id"`
+
+	// DisplayName is the human-readable name of the operation
+	DisplayName string `json:"displayName"`
+
+	// Type is the action or trigger type
+	Type string `json:"type,omitempty"`
+
+	// Description explains what the operation does
+	Description string `json:"description,omitempty"`
+
+	// HelpText gives guidance on configuring the operation
+	HelpText string `json:"helpText,omitempty"`
+
+	// Documentation is free-form usage documentation in Markdown
+	Documentation string `json:"documentation,omitempty"`
+
+	// Auth is set when the operation's auth differs from the integration's
+	Auth *Auth `json:"auth,omitempty"`
+
+	// Inputs are the fields of the operation's form, flattened in form order
+	Inputs []*Input `json:"inputs"`
+
+	// SampleOutput is an example of the operation's output
+	SampleOutput core.JSON `json:"sampleOutput,omitempty"`
+
+	// Criteria configures when a trigger fires
+	Criteria *core.TriggerCriteria `json:"criteria,omitempty"`
+}
+
+// Input documents a form field.
+type Input struct {
+	// Path addresses the field's value, with "." into objects and "[]" into arrays
+	Path string `json:"path"`
+
+	// Label is the human-readable name of the field
+	Label string `json:"label,omitempty"`
+
+	// Type is the field type, such as text or select
+	Type string `json:"type"`
+
+	// Required marks the field as mandatory
+	Required bool `json:"required"`
+
+	// Default is the value used when none is given
+	Default any `json:"default,omitempty"`
+
+	// Description explains the field
+	Description string `json:"description,omitempty"`
+
+	// Options are the values a select field accepts
+	Options []any `json:"options,omitempty"`
+
+	// Constraints describe the validation rules of the field, such as "min 1"
+	Constraints []string `json:"constraints,omitempty"`
+}
+
+// New builds the reference of an integration definition.
+func New(def *sdk.IntegrationDefinition) (*Reference, error) {
+	ref := &Reference{
+		Name:             def.ID,
+		DisplayName:      cmp.Or(def.DisplayName, def.ID),
+		Description:      def.Description,
+		Version:          def.Version,
+		Website:          def.Website,
+		DocumentationURL: def.DocumentationURL,
+		Documentation:    def.IntegrationMetadata.Documentation,
+		Actions:          []*Operation{},
+		Triggers:         []*Operation{},
+	}
+
+	var err error
+	if ref.Auth, err = newAuth(def.Auth); err != nil {
+		return nil, fmt.Errorf("docs: auth: %w", err)
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(def.Actions)) {
+		a := def.Actions[id]
+		op := &Operation{
+			ID:            id,
+			DisplayName:   cmp.Or(a.DisplayName, id),
+			Type:          string(a.Type),
+			Description:   a.Description,
+			HelpText:      a.HelpText,
+			Documentation: a.Documentation,
+			SampleOutput:  a.SampleOutput,
+		}
+		if err := fillOperation(op, a.Properties, a.Auth, def.Auth); err != nil {
+			return nil, fmt.Errorf("docs: action %s: %w", id, err)
+		}
+		ref.Actions = append(ref.Actions, op)
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(def.Triggers)) {
+		t := def.Triggers[id]
+		op := &Operation{
+			ID:            id,
+			DisplayName:   cmp.Or(t.DisplayName, id),
+			Type:          string(t.Type),
+			Description:   t.Description,
+			HelpText:      t.HelpText,
+			Documentation: t.Documentation,
+			SampleOutput:  t.SampleOutput,
+			Criteria:      t.Settings.Criteria,
+		}
+		if err := fillOperation(op, t.Properties, t.Auth, def.Auth); err != nil {
+			return nil, fmt.Errorf("docs: trigger %s: %w", id, err)
+		}
+		ref.Triggers = append(ref.Triggers, op)
+	}
+
+	return ref, nil
+}
+
+// Write renders the reference of an integration definition in the given format.
+func Write(w io.Writer, def *sdk.IntegrationDefinition, format Format) error {
+	ref, err := New(def)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatMarkdown, "":
+		return ref.Markdown(w)
+	case FormatJSON:
+		return ref.JSON(w)
+	default:
+		return fmt.Errorf("docs: unknown format %q", format)
+	}
+}
+
+// JSON writes the reference as indented JSON.
+func (r *Reference) JSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+func fillOperation(op *Operation, schema *smartform.FormSchema, auth, integrationAuth *core.AuthMetadata) error {
+	inputs, err := newInputs(schema)
+	if err != nil {
+		return err
+	}
+	op.Inputs = inputs
+
+	if auth != integrationAuth {
+		if op.Auth, err = newAuth(auth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newAuth(auth *core.AuthMetadata) (*Auth, error) {
+	if auth == nil {
+		return nil, nil
+	}
+
+	inputs, err := newInputs(auth.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Auth{Type: string(auth.Type), Required: auth.Required, Inputs: inputs}, nil
+}
+
+func newInputs(schema *smartform.FormSchema) ([]*Input, error) {
+	inputs := []*Input{}
+
+	view, err := form.FromSmartform(schema)
+	if err != nil || view == nil {
+		return inputs, err
+	}
+
+	fields := slices.Clone(view.Fields)
+	slices.SortStableFunc(fields, func(a, b *form.Field) int { return a.Order - b.Order })
+
+	return appendInputs(inputs, fields, ""), nil
+}
+
+func appendInputs(inputs []*Input, fields []*form.Field, prefix string) []*Input {
+	for _, f := range fields {
+		if f.Type == form.TypeGroup {
+			inputs = appendInputs(inputs, f.Nested, prefix)
+			continue
+		}
+
+		path := prefix + f.ID
+		inputs = append(inputs, &Input{
+			Path:        path,
+			Label:       f.Label,
+			Type:        f.Type,
+			Required:    f.Required,
+			Default:     f.DefaultValue,
+			Description: f.HelpText,
+			Options:     options(f),
+			Constraints: constraints(f),
+		})
+
+		switch f.Type {
+		case form.TypeArray:
+			inputs = appendInputs(inputs, f.Nested, path+"[].")
+		default:
+			inputs = appendInputs(inputs, f.Nested, path+".")
+		}
+	}
+
+	return inputs
+}
+
+func options(f *form.Field) []any {
+	if f.Options == nil {
+		return nil
+	}
+
+	var values []any
+	for _, o := range f.Options.Static {
+		values = append(values, o.Value)
+	}
+
+	return values
+}
+
+func constraints(f *form.Field) []string {
+	var out []string
+	for _, r := range f.ValidationRules {
+		switch r.Type {
+		case form.RuleEmail:
+			out = append(out, "email address")
+		case form.RuleURL:
+			out = append(out, "URL")
+		case form.RulePattern:
+			out = append(out, fmt.Sprintf("pattern `%v`", r.Parameters))
+		default:
+			out = append(out, fmt.Sprintf("%s %v", ruleName(r.Type), r.Parameters))
+		}
+	}
+
+	if f.BoolProperty("secret") {
+		out = append(out, "secret")
+	}
+	if deps, ok := f.Properties["dependsOn"].([]any); ok && len(deps) > 0 {
+		names := make([]string, len(deps))
+		for i, d := range deps {
+			names[i] = fmt.Sprint(d)
+		}
+		out = append(out, "depends on "+strings.Join(names, ", "))
+	}
+
+	return out
+}
+
+func ruleName(typ string) string {
+	switch typ {
+	case form.RuleMinLength:
+		return "min length"
+	case form.RuleMaxLength:
+		return "max length"
+	case form.RuleMinItems:
+		return "min items"
+	case form.RuleMaxItems:
+		return "max items"
+	default:
+		return typ
+	}
+}
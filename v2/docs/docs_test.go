@@ -0,0 +1,115 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/stretchr/testify/require"
+	sdk "github.com/wakflo/go-sdk/v2"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+func testDefinition() *sdk.IntegrationDefinition {
+	auth := &core.AuthMetadata{
+		Type:     core.Secret,
+		Required: true,
+		Schema: &smartform.FormSchema{Fields: []*smartform.Field{
+			{ID: "api_key", Type: "password", Label: "API Key", Required: true},
+		}},
+	}
+
+	def := &sdk.IntegrationDefinition{
+		ID:          "mailer",
+		DisplayName: "Mailer",
+		Auth:        auth,
+		Actions: map[string]*sdk.ActionDefinition{
+			"send_email": {
+				Name:         "send_email",
+				DisplayName:  "Send Email",
+				Description:  "Sends an email.",
+				HelpText:     "Use a verified sender.",
+				Auth:         auth,
+				SampleOutput: map[string]any{"id": "m_1"},
+				Properties: &smartform.FormSchema{Fields: []*smartform.Field{
+					{ID: "to", Type: "email", Label: "To", Required: true, HelpText: "Recipient | address", Order: 1},
+					{ID: "subject", Type: "text", DefaultValue: "Hello", Order: 2},
+					{ID: "attachments", Type: "array", Order: 3, Nested: []*smartform.Field{{ID: "name", Type: "text"}}},
+				}},
+			},
+		},
+		Triggers: map[string]*sdk.TriggerDefinition{
+			"new_email": {
+				Name:        "new_email",
+				DisplayName: "New Email",
+				Type:        core.TriggerTypePolling,
+				Settings: sdk.TriggerSettings{Criteria: &core.TriggerCriteria{
+					Polling: &core.PollingTriggerCriteria{},
+				}},
+			},
+		},
+	}
+	def.Description = "Send and receive email."
+	def.Version = "1.2.0"
+
+	return def
+}
+
+func TestNew(t *testing.T) {
+	ref, err := New(testDefinition())
+	require.NoError(t, err)
+
+	require.Equal(t, "Mailer", ref.DisplayName)
+	require.Equal(t, "secret", ref.Auth.Type)
+	require.Len(t, ref.Actions, 1)
+
+	action := ref.Actions[0]
+	require.Nil(t, action.Auth, "inherited auth is documented once")
+	paths := make([]string, len(action.Inputs))
+	for i, in := range action.Inputs {
+		paths[i] = in.Path
+	}
+	require.Equal(t, []string{"to", "subject", "attachments", "attachments[].name"}, paths)
+	require.Equal(t, "Hello", action.Inputs[1].Default)
+
+	require.NotNil(t, ref.Triggers[0].Criteria.Polling)
+	require.Empty(t, ref.Triggers[0].Inputs)
+}
+
+func TestWrite(t *testing.T) {
+	var md bytes.Buffer
+	require.NoError(t, Write(&md, testDefinition(), FormatMarkdown))
+	out := md.String()
+	require.Contains(t, out, "# Mailer\n\nSend and receive email.\n\n- **Version:** 1.2.0\n")
+	require.Contains(t, out, "## Authentication\n\nType: `secret` (required)\n")
+	require.Contains(t, out, "- [Send Email](#send-email): Sends an email.\n")
+	require.Contains(t, out, "> Use a verified sender.\n")
+	require.Contains(t, out, "| `to` To | email | yes |  | Recipient \\| address |\n")
+	require.Contains(t, out, "| `subject` | text |  | `\"Hello\"` |  |\n")
+	require.Contains(t, out, "#### Sample output\n\n```json\n{\n  \"id\": \"m_1\"\n}\n```\n")
+	require.Contains(t, out, "#### Criteria\n\n```json\n")
+	require.Contains(t, out, "This operation takes no input.")
+
+	var js bytes.Buffer
+	require.NoError(t, Write(&js, testDefinition(), FormatJSON))
+	var ref Reference
+	require.NoError(t, json.Unmarshal(js.Bytes(), &ref))
+	require.Equal(t, "send_email", ref.Actions[0].ID)
+
+	require.Error(t, Write(&js, testDefinition(), "html"))
+}
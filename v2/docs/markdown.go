@@ -0,0 +1,228 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Markdown writes the reference as a Markdown document.
+func (r *Reference) Markdown(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	p := func(format string, args ...any) { fmt.Fprintf(b, format, args...) }
+
+	p("# %s\n\n", r.DisplayName)
+	if r.Description != "" {
+		p("%s\n\n", r.Description)
+	}
+
+	var facts []string
+	if r.Version != "" {
+		facts = append(facts, fmt.Sprintf("- **Version:** %s", r.Version))
+	}
+	if r.Website != "" {
+		facts = append(facts, fmt.Sprintf("- **Website:** <%s>", r.Website))
+	}
+	if r.DocumentationURL != "" {
+		facts = append(facts, fmt.Sprintf("- **Documentation:** <%s>", r.DocumentationURL))
+	}
+	if len(facts) > 0 {
+		p("%s\n\n", strings.Join(facts, "\n"))
+	}
+
+	if r.Documentation != "" {
+		p("%s\n\n", strings.TrimSpace(r.Documentation))
+	}
+
+	if r.Auth != nil {
+		p("## Authentication\n\n")
+		writeAuth(p, r.Auth)
+	}
+
+	writeSection(p, "Actions", r.Actions)
+	writeSection(p, "Triggers", r.Triggers)
+
+	return b.Flush()
+}
+
+func writeSection(p func(string, ...any), title string, ops []*Operation) {
+	if len(ops) == 0 {
+		return
+	}
+
+	p("## %s\n\n", title)
+	for _, op := range ops {
+		p("- [%s](#%s)", op.DisplayName, slug(op.DisplayName))
+		if op.Description != "" {
+			p(": %s", firstLine(op.Description))
+		}
+		p("\n")
+	}
+	p("\n")
+
+	for _, op := range ops {
+		writeOperation(p, op)
+	}
+}
+
+func writeOperation(p func(string, ...any), op *Operation) {
+	p("### %s\n\n", op.DisplayName)
+	p("ID: `%s`", op.ID)
+	if op.Type != "" {
+		p(" · Type: `%s`", op.Type)
+	}
+	p("\n\n")
+
+	if op.Description != "" {
+		p("%s\n\n", op.Description)
+	}
+	if op.HelpText != "" {
+		p("> %s\n\n", strings.ReplaceAll(op.HelpText, "\n", "\n> "))
+	}
+	if op.Documentation != "" {
+		p("%s\n\n", strings.TrimSpace(op.Documentation))
+	}
+
+	if op.Auth != nil {
+		p("#### Authentication\n\n")
+		writeAuth(p, op.Auth)
+	}
+
+	p("#### Input\n\n")
+	writeInputs(p, op.Inputs)
+
+	if op.Criteria != nil {
+		p("#### Criteria\n\n")
+		writeJSON(p, op.Criteria)
+	}
+
+	if op.SampleOutput != nil {
+		p("#### Sample output\n\n")
+		writeJSON(p, op.SampleOutput)
+	}
+}
+
+func writeAuth(p func(string, ...any), auth *Auth) {
+	requirement := "optional"
+	if auth.Required {
+		requirement = "required"
+	}
+	p("Type: `%s` (%s)\n\n", auth.Type, requirement)
+
+	if len(auth.Inputs) > 0 {
+		writeInputs(p, auth.Inputs)
+	}
+}
+
+func writeInputs(p func(string, ...any), inputs []*Input) {
+	if len(inputs) == 0 {
+		p("This operation takes no input.\n\n")
+		return
+	}
+
+	p("| Field | Type | Required | Default | Description |\n")
+	p("| --- | --- | --- | --- | --- |\n")
+	for _, in := range inputs {
+		name := "`" + in.Path + "`"
+		if in.Label != "" {
+			name += " " + in.Label
+		}
+
+		required := ""
+		if in.Required {
+			required = "yes"
+		}
+
+		def := ""
+		if in.Default != nil {
+			def = "`" + compactJSON(in.Default) + "`"
+		}
+
+		desc := in.Description
+		if len(in.Options) > 0 {
+			values := make([]string, len(in.Options))
+			for i, o := range in.Options {
+				values[i] = "`" + compactJSON(o) + "`"
+			}
+			desc = joinSentences(desc, "One of "+strings.Join(values, ", "))
+		}
+		if len(in.Constraints) > 0 {
+			desc = joinSentences(desc, strings.Join(in.Constraints, ", "))
+		}
+
+		p("| %s | %s | %s | %s | %s |\n", cell(name), cell(in.Type), required, cell(def), cell(desc))
+	}
+	p("\n")
+}
+
+func writeJSON(p func(string, ...any), v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		data = []byte(fmt.Sprint(v))
+	}
+	p("```json\n%s\n```\n\n", data)
+}
+
+func compactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+func joinSentences(a, b string) string {
+	a = strings.TrimSpace(a)
+	if a == "" {
+		return b + "."
+	}
+	if !strings.HasSuffix(a, ".") {
+		a += "."
+	}
+
+	return a + " " + b + "."
+}
+
+// cell escapes text for a Markdown table cell.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(strings.TrimSpace(s), "\n", "<br>")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+// slug returns the anchor GitHub generates for a heading.
+func slug(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,94 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/xid"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// ErrNoAuth is returned by AuthContext when the request carries no credentials.
+var ErrNoAuth = errors.New("dynamic: no auth context")
+
+// fieldContext is the DynamicFieldContext passed to options functions.
+type fieldContext struct {
+	ctx    context.Context
+	req    *Request
+	filter *core.DynamicOptionsFilterParams
+	logger core.Logger
+}
+
+var _ sdkcontext.DynamicFieldContext = (*fieldContext)(nil)
+
+func newFieldContext(ctx context.Context, req *Request, filter *core.DynamicOptionsFilterParams) *fieldContext {
+	logger := req.Logger
+	if logger == nil {
+		logger = core.NewStructuredLogger()
+	}
+
+	return &fieldContext{ctx: ctx, req: req, filter: filter, logger: logger}
+}
+
+func (c *fieldContext) Context() context.Context { return c.ctx }
+
+func (c *fieldContext) WorkflowID() xid.ID { return c.req.WorkflowID }
+
+func (c *fieldContext) WorkflowVersionID() xid.ID { return c.req.WorkflowVersionID }
+
+func (c *fieldContext) ProjectID() xid.ID { return c.req.ProjectID }
+
+func (c *fieldContext) Logger() core.Logger { return c.logger }
+
+func (c *fieldContext) Input() core.JSONObject { return c.req.Input }
+
+func (c *fieldContext) Auth() *sdkcontext.AuthContext { return c.req.Auth }
+
+func (c *fieldContext) AuthContext() (*sdkcontext.AuthContext, error) {
+	if c.req.Auth == nil {
+		return nil, ErrNoAuth
+	}
+
+	return c.req.Auth, nil
+}
+
+func (c *fieldContext) Files() sdkcontext.FileResource { return c.req.Files }
+
+func (c *fieldContext) FieldName() string { return c.req.Field }
+
+func (c *fieldContext) OperationID() string { return c.req.OperationID }
+
+func (c *fieldContext) StepID() string { return c.req.StepID }
+
+func (c *fieldContext) Filter() *core.DynamicOptionsFilterParams { return c.filter }
+
+func (c *fieldContext) Respond(data any, totalItems int) (*core.DynamicOptionsResponse, error) {
+	return &core.DynamicOptionsResponse{
+		Metadata: core.OffsetPaginationMeta{
+			Offset:     c.filter.Offset,
+			Limit:      c.filter.Limit,
+			TotalItems: totalItems,
+			HasMore:    c.filter.Offset+c.filter.Limit < totalItems,
+		},
+		Items: data,
+	}, nil
+}
+
+func (c *fieldContext) RespondJSON(data any, totalItems int) (core.JSON, error) {
+	return c.Respond(data, totalItems)
+}
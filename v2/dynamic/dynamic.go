@@ -0,0 +1,385 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dynamic resolves the options of dynamic form fields.
+//
+// A Runtime finds the DynamicOptionsFn of a field by operation and field ID,
+// as reported by the operation's sdk.DynamicOptionsProvider, calls it with a
+// DynamicFieldContext built from a Request, and caches the result. Cache
+// entries are keyed by the auth connection, the values of the fields the
+// dynamic field depends on and the filter, so a change to an unrelated field
+// reuses the cached options.
+//
+// A field declares its dependencies with the "dependsOn" property of its
+// form field, a list of sibling field IDs. Options functions that read other
+// input values must declare them, or they will be served stale results.
+package dynamic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/rs/xid"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/form"
+)
+
+// DefaultTTL is how long resolved options are cached unless WithTTL is used.
+const DefaultTTL = 5 * time.Minute
+
+var (
+	// ErrUnknownOperation is returned for an operation ID that is neither an action nor a trigger
+	ErrUnknownOperation = errors.New("dynamic: unknown action or trigger")
+
+	// ErrUnknownField is returned for a field without an options function
+	ErrUnknownField = errors.New("dynamic: field has no options function")
+)
+
+// Request describes a dynamic options lookup.
+type Request struct {
+	// OperationID is the ID of the action or trigger that owns the field
+	OperationID string
+
+	// Field is the ID of the dynamic field
+	Field string
+
+	// Input holds the current form values
+	Input core.JSONObject
+
+	// Auth holds the credentials of the connection the options are loaded with
+	Auth *sdkcontext.AuthContext
+
+	// ConnectionID identifies the auth connection in cache keys; if empty a
+	// fingerprint of Auth is used
+	ConnectionID string
+
+	// Filter selects the page of options; offset 0 and core.DefaultDynamicOptionsLimit when nil
+	Filter *core.DynamicOptionsFilterParams
+
+	// StepID is the ID of the step being configured
+	StepID string
+
+	// ProjectID is the project of the workflow
+	ProjectID xid.ID
+
+	// WorkflowID is the workflow being configured
+	WorkflowID xid.ID
+
+	// WorkflowVersionID is the workflow version being configured
+	WorkflowVersionID xid.ID
+
+	// Files is returned by the context's Files method
+	Files sdkcontext.FileResource
+
+	// Logger is returned by the context's Logger method; a structured logger when nil
+	Logger core.Logger
+
+	// NoCache bypasses the cache for this lookup and refreshes the entry
+	NoCache bool
+}
+
+// Option configures a Runtime.
+type Option func(*Runtime)
+
+// WithTTL sets how long resolved options are cached. A TTL of zero disables caching.
+func WithTTL(ttl time.Duration) Option {
+	return func(r *Runtime) { r.ttl = ttl }
+}
+
+// WithClock sets the time source used for cache expiry.
+func WithClock(now func() time.Time) Option {
+	return func(r *Runtime) { r.now = now }
+}
+
+// Runtime resolves and caches dynamic options for the actions and triggers of
+// an integration. It is safe for concurrent use.
+type Runtime struct {
+	operations map[string]*operation
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+type operation struct {
+	fns  map[string]sdk.DynamicOptionsFn
+	deps map[string][]string
+}
+
+type entry struct {
+	operationID  string
+	field        string
+	connectionID string
+	response     *core.DynamicOptionsResponse
+	expires      time.Time
+}
+
+// NewRuntime creates a Runtime for the dynamic fields of integration.
+func NewRuntime(integration sdk.Integration, opts ...Option) *Runtime {
+	r := &Runtime{
+		operations: map[string]*operation{},
+		ttl:        DefaultTTL,
+		now:        time.Now,
+		entries:    map[string]*entry{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	for _, action := range integration.Actions() {
+		r.addOperation(action.Metadata().ID, action, action.Properties())
+	}
+	for _, trigger := range integration.Triggers() {
+		r.addOperation(trigger.Metadata().ID, trigger, trigger.Props())
+	}
+
+	return r
+}
+
+func (r *Runtime) addOperation(id string, op any, schema *smartform.FormSchema) {
+	o := &operation{fns: sdk.OperationDynamicOptions(op), deps: map[string][]string{}}
+
+	// a schema that cannot be read has no declared dependencies
+	if view, err := form.FromSmartform(schema); err == nil && view != nil {
+		collectDeps(o.deps, view.Fields)
+	}
+
+	r.operations[id] = o
+}
+
+func collectDeps(deps map[string][]string, fields []*form.Field) {
+	for _, f := range fields {
+		if f.Type == form.TypeGroup {
+			collectDeps(deps, f.Nested)
+			continue
+		}
+
+		switch v := f.Properties["dependsOn"].(type) {
+		case []string:
+			deps[f.ID] = v
+		case []any:
+			for _, d := range v {
+				if s, ok := d.(string); ok {
+					deps[f.ID] = append(deps[f.ID], s)
+				}
+			}
+		}
+	}
+}
+
+// DependsOn returns the fields the dynamic field of an operation depends on.
+func (r *Runtime) DependsOn(operationID, field string) []string {
+	if op := r.operations[operationID]; op != nil {
+		return slices.Clone(op.deps[field])
+	}
+
+	return nil
+}
+
+// Resolve returns the options of a dynamic field, from the cache when a live
+// entry matches the request. Errors from the options function, including
+// panics, are returned and never cached.
+func (r *Runtime) Resolve(ctx context.Context, req *Request) (*core.DynamicOptionsResponse, error) {
+	op := r.operations[req.OperationID]
+	if op == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownOperation, req.OperationID)
+	}
+	fn := op.fns[req.Field]
+	if fn == nil {
+		return nil, fmt.Errorf("%w: %s.%s", ErrUnknownField, req.OperationID, req.Field)
+	}
+
+	filter := normalizeFilter(req.Filter)
+	connectionID := req.ConnectionID
+	if connectionID == "" {
+		connectionID = fingerprint(req.Auth)
+	}
+	key, err := cacheKey(req, op.deps[req.Field], connectionID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	if !req.NoCache && r.ttl > 0 {
+		r.mu.Lock()
+		e, ok := r.entries[key]
+		if ok && now.After(e.expires) {
+			delete(r.entries, key)
+			ok = false
+		}
+		r.mu.Unlock()
+		if ok {
+			return e.response, nil
+		}
+	}
+
+	resp, err := call(fn, newFieldContext(ctx, req, filter))
+	if err != nil {
+		return nil, fmt.Errorf("dynamic: %s.%s: %w", req.OperationID, req.Field, err)
+	}
+
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.entries[key] = &entry{
+			operationID:  req.OperationID,
+			field:        req.Field,
+			connectionID: connectionID,
+			response:     resp,
+			expires:      now.Add(r.ttl),
+		}
+		r.mu.Unlock()
+	}
+
+	return resp, nil
+}
+
+func call(fn sdk.DynamicOptionsFn, ctx sdkcontext.DynamicFieldContext) (resp *core.DynamicOptionsResponse, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("options function panicked: %v", p)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// Invalidate drops the cached options of a field. An empty field drops every
+// field of the operation.
+func (r *Runtime) Invalidate(operationID, field string) {
+	r.drop(func(e *entry) bool {
+		return e.operationID == operationID && (field == "" || e.field == field)
+	})
+}
+
+// InvalidateConnection drops the cached options loaded with a connection, as
+// returned by Request.ConnectionID or the fingerprint of its Auth.
+func (r *Runtime) InvalidateConnection(connectionID string) {
+	r.drop(func(e *entry) bool { return e.connectionID == connectionID })
+}
+
+// InvalidateAuth drops the cached options loaded with the given credentials.
+func (r *Runtime) InvalidateAuth(auth *sdkcontext.AuthContext) {
+	r.InvalidateConnection(fingerprint(auth))
+}
+
+// FieldChanged drops the cached options of every field of the operation that
+// depends on field, directly or through other dependent fields, and returns
+// their IDs in sorted order so a form can reload them.
+func (r *Runtime) FieldChanged(operationID, field string) []string {
+	op := r.operations[operationID]
+	if op == nil {
+		return nil
+	}
+
+	affected := map[string]bool{}
+	queue := []string{field}
+	for len(queue) > 0 {
+		changed := queue[0]
+		queue = queue[1:]
+		for id, deps := range op.deps {
+			if !affected[id] && slices.Contains(deps, changed) {
+				affected[id] = true
+				queue = append(queue, id)
+			}
+		}
+	}
+
+	r.drop(func(e *entry) bool { return e.operationID == operationID && affected[e.field] })
+
+	return slices.Sorted(maps.Keys(affected))
+}
+
+// Purge drops every cached entry.
+func (r *Runtime) Purge() {
+	r.drop(func(*entry) bool { return true })
+}
+
+// Len returns the number of cached entries, including expired ones not yet dropped.
+func (r *Runtime) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.entries)
+}
+
+func (r *Runtime) drop(match func(*entry) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for key, e := range r.entries {
+		if match(e) || now.After(e.expires) {
+			delete(r.entries, key)
+		}
+	}
+}
+
+func normalizeFilter(filter *core.DynamicOptionsFilterParams) *core.DynamicOptionsFilterParams {
+	out := core.DynamicOptionsFilterParams{}
+	if filter != nil {
+		out = *filter
+	}
+	out.Offset = max(out.Offset, 0)
+	if out.Limit <= 0 {
+		out.Limit = core.DefaultDynamicOptionsLimit
+	}
+
+	return &out
+}
+
+func cacheKey(req *Request, deps []string, connectionID string, filter *core.DynamicOptionsFilterParams) (string, error) {
+	values := make(map[string]any, len(deps))
+	for _, dep := range deps {
+		values[dep] = req.Input[dep]
+	}
+
+	// map keys are encoded in sorted order, so equal values give equal keys
+	data, err := json.Marshal(map[string]any{
+		"operation":  req.OperationID,
+		"field":      req.Field,
+		"connection": connectionID,
+		"values":     values,
+		"filter":     filter,
+	})
+	if err != nil {
+		return "", fmt.Errorf("dynamic: encode cache key: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fingerprint identifies credentials without keeping them in cache keys.
+func fingerprint(auth *sdkcontext.AuthContext) string {
+	if auth == nil {
+		return ""
+	}
+
+	data, _ := json.Marshal([]any{auth.AccessToken, auth.TokenType, auth.Username, auth.Password, auth.Secret, auth.Key, auth.Extra})
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,148 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamic
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/stretchr/testify/require"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+type testIntegration struct {
+	action sdk.Action
+}
+
+func (i *testIntegration) Metadata() sdk.IntegrationMetadata { return sdk.IntegrationMetadata{} }
+func (i *testIntegration) Auth() *core.AuthMetadata          { return nil }
+func (i *testIntegration) Triggers() []sdk.Trigger           { return nil }
+func (i *testIntegration) Actions() []sdk.Action             { return []sdk.Action{i.action} }
+
+type listAction struct {
+	calls map[string]int
+	fail  bool
+}
+
+func (a *listAction) Metadata() sdk.ActionMetadata { return sdk.ActionMetadata{ID: "list_rows"} }
+func (a *listAction) Auth() *core.AuthMetadata     { return nil }
+
+func (a *listAction) Perform(sdkcontext.PerformContext) (core.JSON, error) { return nil, nil }
+
+func (a *listAction) Properties() *smartform.FormSchema {
+	return &smartform.FormSchema{
+		ID: "list_rows",
+		Fields: []*smartform.Field{
+			{ID: "sheet", Type: "select"},
+			{ID: "tab", Type: "select", Properties: map[string]interface{}{"dependsOn": []string{"sheet"}}},
+			{ID: "column", Type: "select", Properties: map[string]interface{}{"dependsOn": []string{"tab"}}},
+		},
+	}
+}
+
+func (a *listAction) DynamicOptions() map[string]sdk.DynamicOptionsFn {
+	options := func(ctx sdkcontext.DynamicFieldContext) (*core.DynamicOptionsResponse, error) {
+		a.calls[ctx.FieldName()]++
+		if a.fail {
+			return nil, errors.New("upstream down")
+		}
+		if ctx.FieldName() == "column" {
+			panic("boom")
+		}
+
+		auth, err := ctx.AuthContext()
+		if err != nil {
+			return nil, err
+		}
+
+		return ctx.Respond([]string{auth.AccessToken, ctx.Input()["sheet"].(string)}, 2)
+	}
+
+	return map[string]sdk.DynamicOptionsFn{"sheet": options, "tab": options, "column": options}
+}
+
+func TestRuntimeCache(t *testing.T) {
+	action := &listAction{calls: map[string]int{}}
+	now := time.Unix(0, 0)
+	rt := NewRuntime(&testIntegration{action: action}, WithTTL(time.Minute), WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+	auth := &sdkcontext.AuthContext{AccessToken: "t1"}
+
+	require.Equal(t, []string{"sheet"}, rt.DependsOn("list_rows", "tab"))
+
+	resp, err := rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "tab", Auth: auth, Input: core.JSONObject{"sheet": "a", "other": 1}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"t1", "a"}, resp.Items)
+	require.Equal(t, core.DefaultDynamicOptionsLimit, resp.Metadata.Limit)
+
+	// unrelated input changes hit the cache
+	_, err = rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "tab", Auth: auth, Input: core.JSONObject{"sheet": "a", "other": 2}})
+	require.NoError(t, err)
+	require.Equal(t, 1, action.calls["tab"])
+
+	// dependent values, filters and connections are part of the key
+	_, err = rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "tab", Auth: auth, Input: core.JSONObject{"sheet": "b"}})
+	require.NoError(t, err)
+	_, err = rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "tab", Auth: auth, Input: core.JSONObject{"sheet": "a"}, Filter: &core.DynamicOptionsFilterParams{FilterTerm: "x"}})
+	require.NoError(t, err)
+	_, err = rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "tab", Auth: &sdkcontext.AuthContext{AccessToken: "t2"}, Input: core.JSONObject{"sheet": "a"}})
+	require.NoError(t, err)
+	require.Equal(t, 4, action.calls["tab"])
+	require.Equal(t, 4, rt.Len())
+
+	rt.InvalidateAuth(&sdkcontext.AuthContext{AccessToken: "t2"})
+	require.Equal(t, 3, rt.Len())
+
+	require.Equal(t, []string{"column", "tab"}, rt.FieldChanged("list_rows", "sheet"))
+	require.Equal(t, 0, rt.Len())
+
+	_, err = rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "sheet", Auth: auth, Input: core.JSONObject{"sheet": "a"}})
+	require.NoError(t, err)
+	now = now.Add(2 * time.Minute)
+	_, err = rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "sheet", Auth: auth, Input: core.JSONObject{"sheet": "a"}})
+	require.NoError(t, err)
+	require.Equal(t, 2, action.calls["sheet"])
+
+	rt.Invalidate("list_rows", "")
+	require.Equal(t, 0, rt.Len())
+}
+
+func TestRuntimeErrors(t *testing.T) {
+	action := &listAction{calls: map[string]int{}}
+	rt := NewRuntime(&testIntegration{action: action})
+	ctx := context.Background()
+
+	_, err := rt.Resolve(ctx, &Request{OperationID: "missing", Field: "sheet"})
+	require.ErrorIs(t, err, ErrUnknownOperation)
+	_, err = rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "rows"})
+	require.ErrorIs(t, err, ErrUnknownField)
+
+	_, err = rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "sheet", Input: core.JSONObject{"sheet": "a"}})
+	require.ErrorIs(t, err, ErrNoAuth)
+
+	_, err = rt.Resolve(ctx, &Request{OperationID: "list_rows", Field: "column"})
+	require.ErrorContains(t, err, "panicked: boom")
+
+	action.fail = true
+	req := &Request{OperationID: "list_rows", Field: "sheet", Auth: &sdkcontext.AuthContext{}}
+	_, err = rt.Resolve(ctx, req)
+	require.ErrorContains(t, err, "list_rows.sheet: upstream down")
+	require.Equal(t, 0, rt.Len())
+}
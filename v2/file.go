@@ -0,0 +1,352 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/rs/xid"
+	"github.com/wakflo/go-sdk/autoform"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/files"
+)
+
+// DefaultMaxFileSize is the largest file StringToFile decodes or downloads
+// unless WithMaxFileSize says otherwise.
+const DefaultMaxFileSize int64 = 100 << 20
+
+var (
+	// ErrInvalidFile is returned when a file string matches no supported source
+	ErrInvalidFile = errors.New("invalid file string")
+
+	// ErrFileTooLarge is returned when a file exceeds the configured size limit
+	ErrFileTooLarge = errors.New("file exceeds size limit")
+)
+
+// sniffLen is how many leading bytes are inspected to detect the MIME type.
+const sniffLen = 3072
+
+// FileOption configures StringToFile.
+type FileOption func(*fileOptions)
+
+type fileOptions struct {
+	files   sdkcontext.FileResource
+	client  *http.Client
+	maxSize int64
+}
+
+// WithFileResource resolves file IDs through the given resource.
+func WithFileResource(resource sdkcontext.FileResource) FileOption {
+	return func(o *fileOptions) { o.files = resource }
+}
+
+// WithHTTPClient downloads URLs with the given client instead of http.DefaultClient.
+func WithHTTPClient(client *http.Client) FileOption {
+	return func(o *fileOptions) { o.client = client }
+}
+
+// WithMaxFileSize limits decoded and downloaded files to n bytes. Zero or
+// less removes the limit.
+func WithMaxFileSize(n int64) FileOption {
+	return func(o *fileOptions) { o.maxSize = n }
+}
+
+// StringToFile converts a file string to a *autoform.File. The string may be:
+//
+//   - a data: URI, base64 or percent-encoded
+//   - an http(s) URL, downloaded to a temporary file that Close removes
+//   - a file ID, read through the resource given with WithFileResource; a
+//     missing file is an error rather than being decoded as base64
+//   - raw base64 in the standard or URL alphabet, padded or not
+//
+// The MIME type and extension are detected from the content. A type declared
+// by a data: URI or a Content-Type header is used only when detection finds
+// nothing more specific than application/octet-stream. The returned file must
+// be closed.
+func StringToFile(fileStr string, opts ...FileOption) (*autoform.File, error) {
+	return ResolveFile(context.Background(), fileStr, opts...)
+}
+
+// StringToFileWithContext is StringToFile using the Go context and file
+// resource of an execution context.
+func StringToFileWithContext(ctx sdkcontext.BaseContext, fileStr string, opts ...FileOption) (*autoform.File, error) {
+	opts = append([]FileOption{WithFileResource(ctx.Files())}, opts...)
+	return ResolveFile(ctx.Context(), fileStr, opts...)
+}
+
+// ResolveFile is StringToFile with a Go context that bounds downloads and file lookups.
+func ResolveFile(ctx context.Context, fileStr string, opts ...FileOption) (*autoform.File, error) {
+	o := fileOptions{client: http.DefaultClient, maxSize: DefaultMaxFileSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	fileStr = strings.TrimSpace(fileStr)
+
+	switch {
+	case fileStr == "":
+		return nil, ErrInvalidFile
+	case strings.HasPrefix(fileStr, "data:"):
+		return dataURIToFile(fileStr, o)
+	case strings.HasPrefix(fileStr, "http://"), strings.HasPrefix(fileStr, "https://"):
+		return downloadToFile(ctx, fileStr, o)
+	}
+
+	// A file ID is also valid base64, so a failed lookup must not fall back
+	// to decoding it.
+	if o.files != nil {
+		if _, err := xid.FromString(fileStr); err == nil {
+			return resourceToFile(ctx, fileStr, o)
+		}
+	}
+
+	return base64ToFile(fileStr, o)
+}
+
+func dataURIToFile(uri string, o fileOptions) (*autoform.File, error) {
+	meta, payload, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return nil, fmt.Errorf("%w: data URI has no payload", ErrInvalidFile)
+	}
+
+	declared, isBase64 := strings.CutSuffix(meta, ";base64")
+
+	var data []byte
+	if isBase64 {
+		decoded, err := decodeBase64(payload, o.maxSize)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	} else {
+		decoded, err := url.PathUnescape(payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+		}
+		if o.maxSize > 0 && int64(len(decoded)) > o.maxSize {
+			return nil, ErrFileTooLarge
+		}
+		data = []byte(decoded)
+	}
+
+	name := ""
+	if mediaType, params, err := mime.ParseMediaType(declared); err == nil {
+		declared = mediaType
+		name = params["name"]
+	}
+
+	return newFile(name, declared, int64(len(data)), bytes.NewReader(data), nil)
+}
+
+func base64ToFile(s string, o fileOptions) (*autoform.File, error) {
+	data, err := decodeBase64(s, o.maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFile("", "", int64(len(data)), bytes.NewReader(data), nil)
+}
+
+func decodeBase64(s string, maxSize int64) ([]byte, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\n' || r == '\r' || r == '\t' {
+			return -1
+		}
+		return r
+	}, s)
+
+	if maxSize > 0 && int64(base64.RawStdEncoding.DecodedLen(len(s))) > maxSize+2 {
+		return nil, ErrFileTooLarge
+	}
+
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding,
+	} {
+		data, err := enc.DecodeString(s)
+		if err != nil {
+			continue
+		}
+
+		if maxSize > 0 && int64(len(data)) > maxSize {
+			return nil, ErrFileTooLarge
+		}
+
+		return data, nil
+	}
+
+	return nil, ErrInvalidFile
+}
+
+func resourceToFile(ctx context.Context, id string, o fileOptions) (*autoform.File, error) {
+	var name string
+	size := int64(-1)
+	if store, ok := o.files.(files.Store); ok {
+		out, err := store.Stat(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		name, size = out.Name, out.Size
+	}
+
+	rc, err := o.files.GetFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := newFile(name, "", size, rc, rc)
+	if err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func downloadToFile(ctx context.Context, rawURL string, o fileOptions) (*autoform.File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	rsp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return nil, fmt.Errorf("download %s: %s", req.URL.Redacted(), rsp.Status)
+	}
+
+	if o.maxSize > 0 && rsp.ContentLength > o.maxSize {
+		return nil, ErrFileTooLarge
+	}
+
+	tmp, err := os.CreateTemp("", "wakflo-file-*")
+	if err != nil {
+		return nil, err
+	}
+	file := &tempFile{File: tmp}
+
+	body := io.Reader(rsp.Body)
+	if o.maxSize > 0 {
+		body = io.LimitReader(rsp.Body, o.maxSize+1)
+	}
+
+	size, err := io.Copy(tmp, body)
+	if err == nil && o.maxSize > 0 && size > o.maxSize {
+		err = ErrFileTooLarge
+	}
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	declared, _, _ := mime.ParseMediaType(rsp.Header.Get("Content-Type"))
+	out, err := newFile(downloadName(rsp), declared, size, file, file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// downloadName prefers the Content-Disposition filename over the last URL path segment.
+func downloadName(rsp *http.Response) string {
+	if _, params, err := mime.ParseMediaType(rsp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return path.Base(params["filename"])
+	}
+
+	name := path.Base(rsp.Request.URL.Path)
+	if name == "/" || name == "." {
+		return ""
+	}
+
+	return name
+}
+
+// newFile detects the MIME type from the head of r and returns a file that
+// replays the inspected bytes before the rest of r. A negative size is left unset.
+func newFile(name, declared string, size int64, r io.Reader, closer io.Closer) (*autoform.File, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	detected := mimetype.Detect(head)
+	typ, ext := detected.String(), detected.Extension()
+	if declared != "" && detected.Is("application/octet-stream") {
+		typ = declared
+		if exts, _ := mime.ExtensionsByType(declared); len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+
+	if name == "" {
+		name = "file" + ext
+	}
+
+	if closer == nil {
+		closer = io.NopCloser(r)
+	}
+
+	file := &autoform.File{
+		Extension: ext,
+		Mime:      typ,
+		Name:      name,
+		Data: struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(head), r), closer},
+	}
+
+	if size >= 0 {
+		file.Size = size
+	}
+
+	return file, nil
+}
+
+// tempFile removes itself when closed.
+type tempFile struct {
+	*os.File
+}
+
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if rmErr := os.Remove(f.Name()); err == nil {
+		err = rmErr
+	}
+
+	return err
+}
@@ -0,0 +1,123 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/require"
+	"github.com/wakflo/go-sdk/autoform"
+	"github.com/wakflo/go-sdk/v2/files"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+func readFile(t *testing.T, f *autoform.File) []byte {
+	t.Helper()
+
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	return data
+}
+
+func TestStringToFile(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(pngHeader)
+
+	f, err := StringToFile(encoded)
+	require.NoError(t, err)
+	require.Equal(t, "image/png", f.Mime)
+	require.Equal(t, ".png", f.Extension)
+	require.Equal(t, "file.png", f.Name)
+	require.EqualValues(t, len(pngHeader), f.Size)
+	require.Equal(t, pngHeader, readFile(t, f))
+
+	f, err = StringToFile("data:image/png;base64," + base64.RawURLEncoding.EncodeToString(pngHeader))
+	require.NoError(t, err)
+	require.Equal(t, "image/png", f.Mime)
+	require.Equal(t, pngHeader, readFile(t, f))
+
+	f, err = StringToFile("data:application/x-custom;name=notes.bin,%00%01%02")
+	require.NoError(t, err)
+	require.Equal(t, "application/x-custom", f.Mime)
+	require.Equal(t, "notes.bin", f.Name)
+	require.Equal(t, []byte{0, 1, 2}, readFile(t, f))
+
+	_, err = StringToFile(encoded, WithMaxFileSize(8))
+	require.ErrorIs(t, err, ErrFileTooLarge)
+
+	_, err = StringToFile("not a file!")
+	require.ErrorIs(t, err, ErrInvalidFile)
+}
+
+func TestStringToFileResource(t *testing.T) {
+	store := files.NewMemoryStore()
+	out, err := store.UploadFile(context.Background(), "pixel.png", bytes.NewReader(pngHeader))
+	require.NoError(t, err)
+
+	f, err := StringToFile(out.ID.String(), WithFileResource(store))
+	require.NoError(t, err)
+	require.Equal(t, "pixel.png", f.Name)
+	require.Equal(t, "image/png", f.Mime)
+	require.EqualValues(t, len(pngHeader), f.Size)
+	require.Equal(t, pngHeader, readFile(t, f))
+
+	missing := xid.New().String()
+	_, err = StringToFile(missing, WithFileResource(store))
+	require.ErrorIs(t, err, files.ErrNotFound)
+
+	_, err = base64.RawStdEncoding.DecodeString(missing)
+	require.NoError(t, err, "a file ID must also be valid base64 for this case to matter")
+}
+
+func TestStringToFileDownload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			_, _ = w.Write(bytes.Repeat([]byte("a"), 64))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="report.pdf"`)
+		_, _ = w.Write([]byte("%PDF-1.7\n" + strings.Repeat("x", 100)))
+	}))
+	t.Cleanup(srv.Close)
+
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	f, err := StringToFile(srv.URL + "/download?id=1")
+	require.NoError(t, err)
+	require.Equal(t, "report.pdf", f.Name)
+	require.Equal(t, "application/pdf", f.Mime)
+	require.EqualValues(t, 109, f.Size)
+
+	require.Len(t, readFile(t, f), 109)
+	entries, err := os.ReadDir(tmp)
+	require.NoError(t, err)
+	require.Empty(t, entries, "closing removes the downloaded file")
+
+	_, err = StringToFile(srv.URL+"/large", WithMaxFileSize(32))
+	require.ErrorIs(t, err, ErrFileTooLarge)
+}
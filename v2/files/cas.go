@@ -0,0 +1,262 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/rs/xid"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+)
+
+// ContentAddressedStore keeps each distinct content once, addressed by its
+// SHA-256 digest. Every upload gets its own ID and name, but uploads of the
+// same bytes share one object and ContentURL and use no extra space. The
+// object is removed when the last file referring to it is deleted.
+//
+// The layout under the root directory is:
+//
+//	objects/<digest[:2]>/<digest>  content
+//	refs/<id>.json                 name, size and digest
+//	tmp/                           in-flight uploads
+type ContentAddressedStore struct {
+	dir   string
+	opts  options
+	usage usage
+
+	// mu serialises committing and deleting refs so objects are counted once
+	mu sync.Mutex
+
+	// refs counts the refs pointing at each digest
+	refs map[string]int
+}
+
+var _ Store = (*ContentAddressedStore)(nil)
+
+// NewContentAddressedStore opens or creates a store rooted at dir. ContentURL
+// defaults to cas://sha256/<digest>.
+func NewContentAddressedStore(dir string, opts ...Option) (*ContentAddressedStore, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range []string{"objects", "refs", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	refs, used, err := loadRefs(filepath.Join(dir, "refs"))
+	if err != nil {
+		return nil, err
+	}
+
+	o := newOptions(opts)
+	return &ContentAddressedStore{
+		dir:   dir,
+		opts:  o,
+		usage: usage{quota: o.quota, used: used},
+		refs:  refs,
+	}, nil
+}
+
+// Dir returns the store's root directory.
+func (s *ContentAddressedStore) Dir() string {
+	return s.dir
+}
+
+// UploadFile stores content as a new file, reusing the object of identical
+// bytes that are already present.
+func (s *ContentAddressedStore) UploadFile(ctx context.Context, name string, content io.Reader) (*sdkcontext.FileOutput, error) {
+	// Only the per-file limit applies while streaming: a duplicate needs no
+	// space, so the total quota is checked when new content is committed.
+	limit := int64(-1)
+	if s.opts.quota.MaxFileSize > 0 {
+		limit = s.opts.quota.MaxFileSize
+	}
+
+	h := sha256.New()
+	tmp, n, err := writeTemp(filepath.Join(s.dir, "tmp"), content, limit, h)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+
+	id := xid.New()
+	meta := fileMeta{Name: name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commit(tmp, id, meta); err != nil {
+		return nil, err
+	}
+
+	return s.output(id, meta), nil
+}
+
+// commit writes the ref for id, moving tmp into place as the object unless
+// another ref already holds the same content.
+func (s *ContentAddressedStore) commit(tmp string, id xid.ID, meta fileMeta) error {
+	if s.refs[meta.SHA256] > 0 {
+		if err := writeMeta(s.refPath(id), meta); err != nil {
+			return err
+		}
+
+		s.refs[meta.SHA256]++
+		return nil
+	}
+
+	if err := s.usage.reserve(meta.Size); err != nil {
+		return err
+	}
+
+	object := s.objectPath(meta.SHA256)
+	err := os.MkdirAll(filepath.Dir(object), 0o755)
+	if err == nil {
+		err = os.Rename(tmp, object)
+	}
+	if err == nil {
+		err = writeMeta(s.refPath(id), meta)
+	}
+
+	if err != nil {
+		s.usage.release(meta.Size)
+		return err
+	}
+
+	s.refs[meta.SHA256] = 1
+	return nil
+}
+
+func (s *ContentAddressedStore) GetFile(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	meta, _, err := s.lookup(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(s.objectPath(meta.SHA256))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return f, err
+}
+
+func (s *ContentAddressedStore) GetFileAsBytes(ctx context.Context, fileID string) ([]byte, error) {
+	return readAll(ctx, s, fileID)
+}
+
+func (s *ContentAddressedStore) Stat(ctx context.Context, fileID string) (*sdkcontext.FileOutput, error) {
+	meta, id, err := s.lookup(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.output(id, meta), nil
+}
+
+// Delete removes the file, and its object once no other file refers to it.
+func (s *ContentAddressedStore) Delete(ctx context.Context, fileID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meta, id, err := s.lookup(fileID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.refPath(id)); err != nil {
+		return err
+	}
+
+	if s.refs[meta.SHA256]--; s.refs[meta.SHA256] > 0 {
+		return nil
+	}
+	delete(s.refs, meta.SHA256)
+
+	if err := os.Remove(s.objectPath(meta.SHA256)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	s.usage.release(meta.Size)
+	return nil
+}
+
+func (s *ContentAddressedStore) lookup(fileID string) (fileMeta, xid.ID, error) {
+	id, err := parseID(fileID)
+	if err != nil {
+		return fileMeta{}, id, err
+	}
+
+	meta, err := readMeta(s.refPath(id))
+	return meta, id, err
+}
+
+func (s *ContentAddressedStore) refPath(id xid.ID) string {
+	return filepath.Join(s.dir, "refs", id.String()+metaSuffix)
+}
+
+func (s *ContentAddressedStore) objectPath(digest string) string {
+	return filepath.Join(s.dir, "objects", digest[:2], digest)
+}
+
+func (s *ContentAddressedStore) output(id xid.ID, meta fileMeta) *sdkcontext.FileOutput {
+	return &sdkcontext.FileOutput{
+		ID:         id,
+		ContentURL: s.opts.contentURL(id, "cas://sha256/"+meta.SHA256),
+		Name:       meta.Name,
+		Size:       meta.Size,
+	}
+}
+
+// loadRefs counts the refs in dir by digest and sums the size of each
+// distinct object once.
+func loadRefs(dir string) (map[string]int, int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	refs := make(map[string]int)
+	var used int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), metaSuffix) || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		meta, err := readMeta(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if refs[meta.SHA256] == 0 {
+			used += meta.Size
+		}
+		refs[meta.SHA256]++
+	}
+
+	return refs, used, nil
+}
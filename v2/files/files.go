@@ -0,0 +1,180 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package files provides implementations of context.FileResource: an
+// in-memory store for tests, a local-directory store and a content-addressed
+// store that deduplicates uploads by SHA-256.
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/rs/xid"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+)
+
+var (
+	// ErrNotFound is returned when a file ID does not name a stored file
+	ErrNotFound = errors.New("files: file not found")
+
+	// ErrQuotaExceeded is returned when an upload would exceed a size quota
+	ErrQuotaExceeded = errors.New("files: quota exceeded")
+)
+
+// Store is a FileResource that can also describe and remove files.
+type Store interface {
+	sdkcontext.FileResource
+
+	// Stat returns the description of a stored file without reading its content
+	Stat(ctx context.Context, fileID string) (*sdkcontext.FileOutput, error)
+
+	// Delete removes a stored file
+	Delete(ctx context.Context, fileID string) error
+}
+
+// Quota limits how much a store accepts. Zero values mean unlimited.
+type Quota struct {
+	// MaxFileSize is the largest single upload in bytes
+	MaxFileSize int64
+
+	// MaxTotalSize is the largest total of stored bytes
+	MaxTotalSize int64
+}
+
+// Option configures a store.
+type Option func(*options)
+
+type options struct {
+	quota   Quota
+	baseURL string
+}
+
+// WithQuota limits upload and total sizes.
+func WithQuota(q Quota) Option {
+	return func(o *options) { o.quota = q }
+}
+
+// WithBaseURL makes ContentURL <baseURL>/<id> instead of the store's own scheme.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) { o.baseURL = strings.TrimSuffix(baseURL, "/") }
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+func (o options) contentURL(id xid.ID, fallback string) string {
+	if o.baseURL != "" {
+		return o.baseURL + "/" + id.String()
+	}
+
+	return fallback
+}
+
+// parseID converts a file ID string to an xid.
+func parseID(fileID string) (xid.ID, error) {
+	id, err := xid.FromString(fileID)
+	if err != nil {
+		return xid.NilID(), fmt.Errorf("%w: %q", ErrNotFound, fileID)
+	}
+
+	return id, nil
+}
+
+// usage tracks stored bytes against a quota.
+type usage struct {
+	mu    sync.Mutex
+	quota Quota
+	used  int64
+}
+
+// limit returns how many bytes the next upload may contain, or -1 for no limit.
+func (u *usage) limit() int64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	limit := int64(-1)
+	if u.quota.MaxFileSize > 0 {
+		limit = u.quota.MaxFileSize
+	}
+
+	if u.quota.MaxTotalSize > 0 {
+		remaining := max(u.quota.MaxTotalSize-u.used, 0)
+		if limit < 0 || remaining < limit {
+			limit = remaining
+		}
+	}
+
+	return limit
+}
+
+// reserve records n more stored bytes, failing if the total quota would be exceeded.
+func (u *usage) reserve(n int64) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.quota.MaxTotalSize > 0 && u.used+n > u.quota.MaxTotalSize {
+		return ErrQuotaExceeded
+	}
+
+	u.used += n
+	return nil
+}
+
+func (u *usage) release(n int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.used = max(u.used-n, 0)
+}
+
+// copyLimited streams src into dst and fails with ErrQuotaExceeded once more
+// than limit bytes are read. A negative limit disables the check.
+func copyLimited(dst io.Writer, src io.Reader, limit int64) (int64, error) {
+	if limit < 0 {
+		return io.Copy(dst, src)
+	}
+
+	n, err := io.Copy(dst, io.LimitReader(src, limit+1))
+	if err != nil {
+		return n, err
+	}
+
+	if n > limit {
+		return n, ErrQuotaExceeded
+	}
+
+	return n, nil
+}
+
+// readAll reads a stored file through GetFile.
+func readAll(ctx context.Context, store sdkcontext.FileResource, fileID string) ([]byte, error) {
+	rc, err := store.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
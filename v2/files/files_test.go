@@ -0,0 +1,197 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	ctx := context.Background()
+
+	stores := map[string]func(t *testing.T, opts ...Option) Store{
+		"memory": func(t *testing.T, opts ...Option) Store { return NewMemoryStore(opts...) },
+		"local": func(t *testing.T, opts ...Option) Store {
+			s, err := NewLocalStore(t.TempDir(), opts...)
+			require.NoError(t, err)
+			return s
+		},
+		"cas": func(t *testing.T, opts ...Option) Store {
+			s, err := NewContentAddressedStore(t.TempDir(), opts...)
+			require.NoError(t, err)
+			return s
+		},
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := open(t)
+
+			out, err := s.UploadFile(ctx, "hello.txt", strings.NewReader("hello"))
+			require.NoError(t, err)
+			require.Equal(t, "hello.txt", out.Name)
+			require.EqualValues(t, 5, out.Size)
+			require.NotEmpty(t, out.ContentURL)
+
+			content, err := s.GetFileAsBytes(ctx, out.ID.String())
+			require.NoError(t, err)
+			require.Equal(t, "hello", string(content))
+
+			stat, err := s.Stat(ctx, out.ID.String())
+			require.NoError(t, err)
+			require.Equal(t, out, stat)
+
+			require.NoError(t, s.Delete(ctx, out.ID.String()))
+			_, err = s.GetFile(ctx, out.ID.String())
+			require.ErrorIs(t, err, ErrNotFound)
+			_, err = s.GetFile(ctx, "not-an-id")
+			require.ErrorIs(t, err, ErrNotFound)
+
+			s = open(t, WithQuota(Quota{MaxFileSize: 4, MaxTotalSize: 6}), WithBaseURL("https://files.example.com/"))
+			_, err = s.UploadFile(ctx, "big", strings.NewReader("12345"))
+			require.ErrorIs(t, err, ErrQuotaExceeded)
+
+			out, err = s.UploadFile(ctx, "a", strings.NewReader("1234"))
+			require.NoError(t, err)
+			require.Equal(t, "https://files.example.com/"+out.ID.String(), out.ContentURL)
+
+			_, err = s.UploadFile(ctx, "b", strings.NewReader("567"))
+			require.ErrorIs(t, err, ErrQuotaExceeded)
+
+			require.NoError(t, s.Delete(ctx, out.ID.String()))
+			_, err = s.UploadFile(ctx, "b", strings.NewReader("567"))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLocalStoreReopen(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	s, err := NewLocalStore(dir)
+	require.NoError(t, err)
+	out, err := s.UploadFile(ctx, "a.bin", strings.NewReader("abcdef"))
+	require.NoError(t, err)
+	require.Equal(t, "file://"+filepath.ToSlash(filepath.Join(s.Dir(), out.ID.String())), out.ContentURL)
+
+	s, err = NewLocalStore(dir, WithQuota(Quota{MaxTotalSize: 8}))
+	require.NoError(t, err)
+
+	stat, err := s.Stat(ctx, out.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, out, stat)
+
+	_, err = s.UploadFile(ctx, "b.bin", strings.NewReader("xyz"))
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "failed uploads leave no temporary files")
+}
+
+func TestContentAddressedDedup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	s, err := NewContentAddressedStore(dir, WithQuota(Quota{MaxTotalSize: 10}))
+	require.NoError(t, err)
+
+	first, err := s.UploadFile(ctx, "one.txt", strings.NewReader("same bytes"))
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("cas://sha256/%x", sha256.Sum256([]byte("same bytes"))), first.ContentURL)
+
+	second, err := s.UploadFile(ctx, "two.txt", strings.NewReader("same bytes"))
+	require.NoError(t, err, "duplicates do not count against the quota")
+	require.NotEqual(t, first.ID, second.ID)
+	require.Equal(t, first.ContentURL, second.ContentURL)
+
+	stat, err := s.Stat(ctx, second.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, "two.txt", stat.Name)
+
+	reopened, err := NewContentAddressedStore(dir, WithQuota(Quota{MaxTotalSize: 10}))
+	require.NoError(t, err)
+	_, err = reopened.UploadFile(ctx, "three.txt", strings.NewReader("same bytes"))
+	require.NoError(t, err, "shared objects are counted once on reopen")
+
+	objects, err := filepath.Glob(filepath.Join(dir, "objects", "*", "*"))
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+}
+
+func TestContentAddressedSharedDelete(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	s, err := NewContentAddressedStore(dir)
+	require.NoError(t, err)
+
+	first, err := s.UploadFile(ctx, "one.txt", strings.NewReader("same bytes"))
+	require.NoError(t, err)
+	second, err := s.UploadFile(ctx, "two.txt", strings.NewReader("same bytes"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Delete(ctx, first.ID.String()))
+
+	_, err = s.Stat(ctx, first.ID.String())
+	require.ErrorIs(t, err, ErrNotFound)
+	data, err := s.GetFileAsBytes(ctx, second.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, "same bytes", string(data))
+
+	require.NoError(t, s.Delete(ctx, second.ID.String()))
+	objects, err := filepath.Glob(filepath.Join(dir, "objects", "*", "*"))
+	require.NoError(t, err)
+	require.Empty(t, objects)
+}
+
+func TestStreaming(t *testing.T) {
+	ctx := context.Background()
+	const size = 8 << 20
+
+	s, err := NewContentAddressedStore(t.TempDir())
+	require.NoError(t, err)
+
+	out, err := s.UploadFile(ctx, "large.bin", io.LimitReader(zeros{}, size))
+	require.NoError(t, err)
+	require.EqualValues(t, size, out.Size)
+
+	rc, err := s.GetFile(ctx, out.ID.String())
+	require.NoError(t, err)
+	defer rc.Close()
+
+	n, err := io.Copy(io.Discard, rc)
+	require.NoError(t, err)
+	require.EqualValues(t, size, n)
+	require.NotEqual(t, xid.NilID(), out.ID)
+}
+
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
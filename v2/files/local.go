@@ -0,0 +1,283 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/xid"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+)
+
+// metaSuffix names the JSON sidecar that describes a stored file.
+const metaSuffix = ".json"
+
+type fileMeta struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// LocalStore keeps each file in a directory as <id> with a <id>.json sidecar.
+// Writes go to a temporary file that is renamed into place, so readers never
+// observe partial content.
+type LocalStore struct {
+	dir   string
+	opts  options
+	usage usage
+}
+
+var _ Store = (*LocalStore)(nil)
+
+// NewLocalStore opens or creates a store rooted at dir. Existing files count
+// towards the total quota. ContentURL defaults to the file:// URL of the content.
+func NewLocalStore(dir string, opts ...Option) (*LocalStore, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	used, err := metaSize(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	o := newOptions(opts)
+	return &LocalStore{
+		dir:   dir,
+		opts:  o,
+		usage: usage{quota: o.quota, used: used},
+	}, nil
+}
+
+// Dir returns the store's root directory.
+func (s *LocalStore) Dir() string {
+	return s.dir
+}
+
+func (s *LocalStore) UploadFile(ctx context.Context, name string, content io.Reader) (*sdkcontext.FileOutput, error) {
+	id := xid.New()
+
+	tmp, n, err := writeTemp(s.dir, content, s.usage.limit(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+
+	if err := s.usage.reserve(n); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmp, s.path(id)); err != nil {
+		s.usage.release(n)
+		return nil, err
+	}
+
+	meta := fileMeta{Name: name, Size: n}
+	if err := writeMeta(s.metaPath(id), meta); err != nil {
+		_ = os.Remove(s.path(id))
+		s.usage.release(n)
+		return nil, err
+	}
+
+	return s.output(id, meta), nil
+}
+
+func (s *LocalStore) GetFile(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	id, err := parseID(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return f, err
+}
+
+func (s *LocalStore) GetFileAsBytes(ctx context.Context, fileID string) ([]byte, error) {
+	return readAll(ctx, s, fileID)
+}
+
+func (s *LocalStore) Stat(ctx context.Context, fileID string) (*sdkcontext.FileOutput, error) {
+	id, err := parseID(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := readMeta(s.metaPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.output(id, meta), nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, fileID string) error {
+	id, err := parseID(fileID)
+	if err != nil {
+		return err
+	}
+
+	meta, err := readMeta(s.metaPath(id))
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.metaPath(id)); err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	s.usage.release(meta.Size)
+	return nil
+}
+
+func (s *LocalStore) path(id xid.ID) string {
+	return filepath.Join(s.dir, id.String())
+}
+
+func (s *LocalStore) metaPath(id xid.ID) string {
+	return s.path(id) + metaSuffix
+}
+
+func (s *LocalStore) output(id xid.ID, meta fileMeta) *sdkcontext.FileOutput {
+	return &sdkcontext.FileOutput{
+		ID:         id,
+		ContentURL: s.opts.contentURL(id, fileURL(s.path(id))),
+		Name:       meta.Name,
+		Size:       meta.Size,
+	}
+}
+
+// writeTemp streams content into a new temporary file in dir, also writing it
+// to extra when set. The caller owns the returned path and must rename or
+// remove it.
+func writeTemp(dir string, content io.Reader, limit int64, extra io.Writer) (string, int64, error) {
+	f, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return "", 0, err
+	}
+
+	var w io.Writer = f
+	if extra != nil {
+		w = io.MultiWriter(f, extra)
+	}
+
+	n, err := copyLimited(w, content, limit)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", 0, err
+	}
+
+	return f.Name(), n, nil
+}
+
+// writeMeta atomically replaces the sidecar at path.
+func writeMeta(path string, meta fileMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".meta-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func readMeta(path string) (fileMeta, error) {
+	var meta fileMeta
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return meta, ErrNotFound
+	}
+	if err != nil {
+		return meta, err
+	}
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, fmt.Errorf("files: read %s: %w", path, err)
+	}
+
+	return meta, nil
+}
+
+// metaSize sums the sizes recorded by the sidecars directly inside dir.
+func metaSize(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), metaSuffix) || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		meta, err := readMeta(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return 0, err
+		}
+
+		total += meta.Size
+	}
+
+	return total, nil
+}
+
+func fileURL(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
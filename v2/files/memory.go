@@ -0,0 +1,140 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package files
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/rs/xid"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+)
+
+type memoryFile struct {
+	output  sdkcontext.FileOutput
+	content []byte
+}
+
+// MemoryStore keeps files in memory. It is intended for tests.
+type MemoryStore struct {
+	opts  options
+	usage usage
+
+	mu    sync.RWMutex
+	files map[xid.ID]*memoryFile
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store. ContentURL defaults to mem://<id>.
+func NewMemoryStore(opts ...Option) *MemoryStore {
+	o := newOptions(opts)
+	return &MemoryStore{
+		opts:  o,
+		usage: usage{quota: o.quota},
+		files: map[xid.ID]*memoryFile{},
+	}
+}
+
+func (s *MemoryStore) UploadFile(ctx context.Context, name string, content io.Reader) (*sdkcontext.FileOutput, error) {
+	var buf bytes.Buffer
+	n, err := copyLimited(&buf, content, s.usage.limit())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.usage.reserve(n); err != nil {
+		return nil, err
+	}
+
+	id := xid.New()
+	f := &memoryFile{
+		output: sdkcontext.FileOutput{
+			ID:         id,
+			ContentURL: s.opts.contentURL(id, "mem://"+id.String()),
+			Name:       name,
+			Size:       n,
+		},
+		content: buf.Bytes(),
+	}
+
+	s.mu.Lock()
+	s.files[id] = f
+	s.mu.Unlock()
+
+	out := f.output
+	return &out, nil
+}
+
+func (s *MemoryStore) GetFile(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	f, err := s.get(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(f.content)), nil
+}
+
+func (s *MemoryStore) GetFileAsBytes(ctx context.Context, fileID string) ([]byte, error) {
+	return readAll(ctx, s, fileID)
+}
+
+func (s *MemoryStore) Stat(ctx context.Context, fileID string) (*sdkcontext.FileOutput, error) {
+	f, err := s.get(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := f.output
+	return &out, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, fileID string) error {
+	id, err := parseID(fileID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	delete(s.files, id)
+	s.usage.release(f.output.Size)
+	return nil
+}
+
+func (s *MemoryStore) get(fileID string) (*memoryFile, error) {
+	id, err := parseID(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f, ok := s.files[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return f, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flo defines the flo.toml integration manifest. The Go types in this
+// package are the single source of truth for schemas/integration.schema.json,
+// which is regenerated from them with `go generate`.
+package flo
+
+// Group is the predefined group an integration belongs to.
+type Group string
+
+const (
+	// GroupApps is used by integrations with third-party applications
+	GroupApps Group = "apps"
+
+	// GroupAI is used by AI model and agent integrations
+	GroupAI Group = "ai"
+
+	// GroupCore is used by the built-in platform integrations
+	GroupCore Group = "core"
+
+	// GroupTools is used by utility integrations
+	GroupTools Group = "tools"
+)
+
+// Values provides list valid values for Enum
+func (Group) Values() []string {
+	return []string{
+		string(GroupApps),
+		string(GroupAI),
+		string(GroupCore),
+		string(GroupTools),
+	}
+}
+
+// File is the root of a flo.toml document.
+type File struct {
+	// Integration holds the [integration] table
+	Integration Integration `toml:"integration" json:"integration"`
+}
+
+// Integration is the [integration] table of a flo.toml document.
+type Integration struct {
+	// Name is the unique identifier of the integration
+	Name string `toml:"name" json:"name" description:"Unique identifier for the integration." jsonschema:"required,minLength=1"`
+
+	// DisplayName is the human-readable name of the integration
+	DisplayName string `toml:"display_name" json:"display_name" description:"Human-readable name of the integration." jsonschema:"required,minLength=1"`
+
+	// Description provides details about the integration's purpose
+	Description string `toml:"description" json:"description" description:"Detailed description of the integration." jsonschema:"required,minLength=1"`
+
+	// Version is the semantic version of the integration
+	Version string `toml:"version" json:"version" description:"Version of the integration." jsonschema:"required,format=semver,examples=1.0.0|2.1.3"`
+
+	// Group is the predefined group the integration belongs to
+	Group Group `toml:"group" json:"group" description:"Predefined group or category that the integration belongs to." jsonschema:"required,enum=apps|ai|core|tools"`
+
+	// Icon is an icon name, URL or base64-encoded image for the integration
+	Icon string `toml:"icon" json:"icon" description:"Icon name, URL or base64-encoded image for the integration." jsonschema:"required,minLength=1"`
+
+	// Logo is a URL to the logo image
+	Logo string `toml:"logo,omitempty" json:"logo,omitempty" description:"URL of the logo image." jsonschema:"format=uri,examples=https://example.com/logo.png"`
+
+	// Authors lists who created the integration
+	Authors []string `toml:"authors" json:"authors" description:"List of authors for the integration." jsonschema:"required,minItems=1,itemDescription=Author name.,itemMinLength=1"`
+
+	// Categories lists the categories the integration appears under
+	Categories []string `toml:"categories" json:"categories" description:"List of categories associated with the integration." jsonschema:"required,itemDescription=Category name.,itemMinLength=1"`
+
+	// Tags are searchable labels for the integration
+	Tags []string `toml:"tags,omitempty" json:"tags,omitempty" description:"Searchable labels for the integration." jsonschema:"itemDescription=Tag name.,itemMinLength=1"`
+
+	// Website is the URL of the integrated product
+	Website string `toml:"website,omitempty" json:"website,omitempty" description:"Website of the integrated product." jsonschema:"format=uri,examples=https://example.com"`
+
+	// Documentation is a URL to the integration's documentation
+	Documentation string `toml:"documentation,omitempty" json:"documentation,omitempty" description:"Documentation URL." jsonschema:"format=uri,examples=https://example.com/docs"`
+}
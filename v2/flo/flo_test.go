@@ -0,0 +1,95 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flo
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const validFlo = `[integration]
+name = "google-sheets"
+display_name = "Google Sheets"
+description = "Read and write spreadsheets"
+version = "1.0.0"
+group = "apps"
+icon = "mdi:google-spreadsheet"
+authors = ["Wakflo <integrations@wakflo.com>"]
+categories = ["productivity"]
+website = "https://sheets.google.com"
+`
+
+func TestSchemaIsUpToDate(t *testing.T) {
+	want, err := MarshalSchema()
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../schemas/integration.schema.json")
+	require.NoError(t, err)
+
+	require.Equal(t, string(want), string(got), "schema is stale, run `go generate ./v2/flo`")
+}
+
+func TestParseValid(t *testing.T) {
+	file, err := Parse([]byte(validFlo))
+	require.NoError(t, err)
+
+	require.Equal(t, "google-sheets", file.Integration.Name)
+	require.Equal(t, "Google Sheets", file.Integration.DisplayName)
+	require.Equal(t, GroupApps, file.Integration.Group)
+	require.Equal(t, []string{"productivity"}, file.Integration.Categories)
+}
+
+func TestParseReportsPositions(t *testing.T) {
+	content := `[integration]
+name = "google-sheets"
+description = "Read and write spreadsheets"
+version = "1.0"
+group = "office"
+icon = "mdi:google-spreadsheet"
+authors = []
+categories = ["productivity"]
+color = "green"
+`
+
+	_, err := Parse([]byte(content))
+	require.Error(t, err)
+
+	errs, ok := err.(ValidationErrors)
+	require.True(t, ok)
+
+	byPath := map[string]ValidationError{}
+	for _, e := range errs {
+		byPath[e.Path] = e
+	}
+
+	require.Len(t, byPath, 5)
+	require.Equal(t, 1, byPath["integration.display_name"].Line, "missing keys point at their table")
+	require.Equal(t, 4, byPath["integration.version"].Line)
+	require.Equal(t, 1, byPath["integration.version"].Column)
+	require.Equal(t, 5, byPath["integration.group"].Line)
+	require.Equal(t, 7, byPath["integration.authors"].Line)
+	require.Equal(t, 9, byPath["integration.color"].Line)
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	_, err := Parse([]byte("[integration]\nname = \n"))
+	require.Error(t, err)
+
+	errs, ok := err.(ValidationErrors)
+	require.True(t, ok)
+	require.Equal(t, 2, errs[0].Line)
+}
@@ -0,0 +1,39 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command genschema writes the flo.toml JSON Schema generated from the flo
+// package types to the path given as its only argument.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/wakflo/go-sdk/v2/flo"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalf("usage: %s <output>", os.Args[0])
+	}
+
+	out, err := flo.MarshalSchema()
+	if err != nil {
+		log.Fatalf("failed to generate schema: %v", err)
+	}
+
+	if err := os.WriteFile(os.Args[1], out, 0o644); err != nil {
+		log.Fatalf("failed to write schema: %v", err)
+	}
+}
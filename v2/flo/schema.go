@@ -0,0 +1,260 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flo
+
+//go:generate go run ./internal/genschema ../../schemas/integration.schema.json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema (draft-07) used to describe flo.toml.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	FileMatch            []string           `json:"fileMatch,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           Properties         `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Examples             []string           `json:"examples,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// Property is a named entry of Properties.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties keeps object properties in declaration order so the generated
+// schema follows the order of the Go struct fields.
+type Properties []Property
+
+// Get returns the schema of the named property.
+func (p Properties) Get(name string) (*Schema, bool) {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Schema, true
+		}
+	}
+
+	return nil, false
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (p Properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, prop := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(prop.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(prop.Schema)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	*p = nil
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		var s Schema
+		if err := dec.Decode(&s); err != nil {
+			return err
+		}
+
+		*p = append(*p, Property{Name: tok.(string), Schema: &s})
+	}
+
+	_, err := dec.Token()
+	return err
+}
+
+const integrationModel = "IntegrationModel"
+
+// GenerateSchema derives the flo.toml JSON Schema from the Go types in this package.
+func GenerateSchema() *Schema {
+	model := structSchema(reflect.TypeOf(Integration{}))
+	model.Title = "Integration"
+	model.Description = "Defines a single integration."
+
+	return &Schema{
+		Schema:               "http://json-schema.org/draft-07/schema#",
+		ID:                   "https://json.schemastore.org/wakflo.json",
+		Type:                 "object",
+		Title:                "Wakflo Integration Configuration",
+		Description:          "Schema for defining a wakflo integration.",
+		AdditionalProperties: boolPtr(false),
+		FileMatch:            []string{"flo.toml", "integration.toml"},
+		Required:             []string{"integration"},
+		Properties: Properties{
+			{Name: "integration", Schema: &Schema{Ref: "#/definitions/" + integrationModel}},
+		},
+		Definitions: map[string]*Schema{integrationModel: model},
+	}
+}
+
+// MarshalSchema renders the generated schema exactly as it is stored in
+// schemas/integration.schema.json.
+func MarshalSchema() ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(GenerateSchema()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", AdditionalProperties: boolPtr(false)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		prop, required := fieldSchema(field)
+		if required {
+			s.Required = append(s.Required, name)
+		}
+
+		s.Properties = append(s.Properties, Property{Name: name, Schema: prop})
+	}
+
+	return s
+}
+
+func fieldSchema(field reflect.StructField) (*Schema, bool) {
+	s := &Schema{Description: field.Tag.Get("description")}
+
+	switch field.Type.Kind() {
+	case reflect.Struct:
+		nested := structSchema(field.Type)
+		nested.Description = s.Description
+		s = nested
+	case reflect.Slice:
+		s.Type = "array"
+		s.Items = &Schema{Type: jsonType(field.Type.Elem().Kind())}
+	default:
+		s.Type = jsonType(field.Type.Kind())
+	}
+
+	required := false
+	for _, opt := range strings.Split(field.Tag.Get("jsonschema"), ",") {
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "required":
+			required = true
+		case "minLength":
+			s.MinLength = intPtr(value)
+		case "minItems":
+			s.MinItems = intPtr(value)
+		case "format":
+			s.Format = value
+		case "enum":
+			s.Enum = strings.Split(value, "|")
+		case "examples":
+			s.Examples = strings.Split(value, "|")
+		case "itemDescription":
+			s.Items.Description = value
+		case "itemMinLength":
+			s.Items.MinLength = intPtr(value)
+		}
+	}
+
+	return s, required
+}
+
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func intPtr(v string) *int {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		panic(fmt.Sprintf("flo: invalid jsonschema integer %q", v))
+	}
+
+	return &n
+}
@@ -0,0 +1,315 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// ValidationError describes a single problem found in a flo.toml document.
+type ValidationError struct {
+	// Path is the dotted key of the offending value, e.g. "integration.version"
+	Path string `json:"path"`
+
+	// Line is the 1-based line of the offending key
+	Line int `json:"line"`
+
+	// Column is the 1-based column of the offending key
+	Column int `json:"column"`
+
+	// Message describes what is wrong
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("flo.toml:%d:%d: %s", e.Line, e.Column, e.Message)
+	}
+
+	return fmt.Sprintf("flo.toml:%d:%d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// ValidationErrors is returned by Parse when a document does not satisfy the schema.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Parse decodes and validates a flo.toml document against the generated schema.
+// Syntax and schema violations are reported as ValidationErrors carrying the
+// line and column of the offending key.
+func Parse(content []byte) (*File, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(content, &doc); err != nil {
+		var derr *toml.DecodeError
+		if errors.As(err, &derr) {
+			line, col := derr.Position()
+			return nil, ValidationErrors{{Line: line, Column: col, Message: derr.Error()}}
+		}
+
+		return nil, err
+	}
+
+	root := GenerateSchema()
+	v := &validator{root: root, positions: keyPositions(content)}
+	v.validate("", doc, root)
+
+	if len(v.errs) > 0 {
+		return nil, v.errs
+	}
+
+	var file File
+	if err := toml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// Validate reports whether content is a valid flo.toml document.
+func Validate(content []byte) error {
+	_, err := Parse(content)
+	return err
+}
+
+type position struct {
+	line   int
+	column int
+}
+
+// keyPositions maps every dotted key and table header in the document to the
+// position where it is declared.
+func keyPositions(content []byte) map[string]position {
+	positions := map[string]position{}
+
+	p := unstable.Parser{}
+	p.Reset(content)
+
+	prefix := ""
+	for p.NextExpression() {
+		expr := p.Expression()
+
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			key, start := joinKey(&p, expr.Key())
+			prefix = key
+			positions[key] = start
+		case unstable.KeyValue:
+			key, start := joinKey(&p, expr.Key())
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			positions[key] = start
+		}
+	}
+
+	return positions
+}
+
+func joinKey(p *unstable.Parser, it unstable.Iterator) (string, position) {
+	var parts []string
+	var start position
+
+	for it.Next() {
+		node := it.Node()
+		if len(parts) == 0 {
+			shape := p.Shape(node.Raw)
+			start = position{line: shape.Start.Line, column: shape.Start.Column}
+		}
+		parts = append(parts, string(node.Data))
+	}
+
+	return strings.Join(parts, "."), start
+}
+
+type validator struct {
+	root      *Schema
+	positions map[string]position
+	errs      ValidationErrors
+}
+
+func (v *validator) fail(path string, format string, args ...any) {
+	pos := v.lookup(path)
+	v.errs = append(v.errs, ValidationError{
+		Path:    path,
+		Line:    pos.line,
+		Column:  pos.column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// lookup returns the position of path, falling back to its closest declared
+// ancestor for values that have no key of their own (missing keys, array items).
+func (v *validator) lookup(path string) position {
+	for path != "" {
+		if pos, ok := v.positions[path]; ok {
+			return pos
+		}
+
+		if i := strings.LastIndexAny(path, ".["); i >= 0 {
+			path = path[:i]
+		} else {
+			path = ""
+		}
+	}
+
+	return position{line: 1, column: 1}
+}
+
+func (v *validator) resolve(s *Schema) *Schema {
+	if s.Ref == "" {
+		return s
+	}
+
+	name := strings.TrimPrefix(s.Ref, "#/definitions/")
+	if def, ok := v.root.Definitions[name]; ok {
+		return def
+	}
+
+	return s
+}
+
+func (v *validator) validate(path string, value any, s *Schema) {
+	s = v.resolve(s)
+
+	switch s.Type {
+	case "object":
+		v.validateObject(path, value, s)
+	case "array":
+		v.validateArray(path, value, s)
+	case "string":
+		v.validateString(path, value, s)
+	case "integer":
+		if _, ok := value.(int64); !ok {
+			v.fail(path, "must be an integer")
+		}
+	case "number":
+		switch value.(type) {
+		case int64, float64:
+		default:
+			v.fail(path, "must be a number")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(path, "must be a boolean")
+		}
+	}
+}
+
+func (v *validator) validateObject(path string, value any, s *Schema) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		v.fail(path, "must be a table")
+		return
+	}
+
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			v.fail(join(path, name), "is required")
+		}
+	}
+
+	for _, name := range sortedKeys(obj) {
+		prop, ok := s.Properties.Get(name)
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				v.fail(join(path, name), "is not allowed")
+			}
+			continue
+		}
+
+		v.validate(join(path, name), obj[name], prop)
+	}
+}
+
+func (v *validator) validateArray(path string, value any, s *Schema) {
+	items, ok := value.([]any)
+	if !ok {
+		v.fail(path, "must be an array")
+		return
+	}
+
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		v.fail(path, "must contain at least %d item(s)", *s.MinItems)
+	}
+
+	if s.Items == nil {
+		return
+	}
+
+	for i, item := range items {
+		v.validate(fmt.Sprintf("%s[%d]", path, i), item, s.Items)
+	}
+}
+
+func (v *validator) validateString(path string, value any, s *Schema) {
+	str, ok := value.(string)
+	if !ok {
+		v.fail(path, "must be a string")
+		return
+	}
+
+	if s.MinLength != nil && utf8.RuneCountInString(str) < *s.MinLength {
+		v.fail(path, "must be at least %d character(s) long", *s.MinLength)
+	}
+
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+		v.fail(path, "must be one of [%s], got %q", strings.Join(s.Enum, ", "), str)
+	}
+
+	switch s.Format {
+	case "semver":
+		if _, err := semver.StrictNewVersion(str); err != nil {
+			v.fail(path, "must be a semantic version (e.g. 1.0.0), got %q", str)
+		}
+	case "uri":
+		if u, err := url.Parse(str); err != nil || u.Scheme == "" || u.Host == "" {
+			v.fail(path, "must be an absolute URI, got %q", str)
+		}
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
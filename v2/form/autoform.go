@@ -0,0 +1,192 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	sdkcore "github.com/wakflo/go-sdk/core"
+)
+
+// AutoForm converts s to the JSON-schema form used by v1 integrations, so
+// both schema kinds share one validator. Group fields are layout only and
+// their nested fields are lifted into the enclosing object.
+//
+// Field properties carry what the field model has no place for: "hidden",
+// "disabled", "dependsOn" ([]string of sibling field IDs), "const" and
+// "uniqueItems".
+func (s *Schema) AutoForm() *sdkcore.AutoFormSchema {
+	out := objectSchema(s.Fields)
+	out.ID = s.ID
+	out.Title = s.Title
+	out.Description = s.Description
+
+	return out
+}
+
+func objectSchema(fields []*Field) *sdkcore.AutoFormSchema {
+	out := &sdkcore.AutoFormSchema{
+		Type:       sdkcore.Object,
+		Properties: map[string]*sdkcore.AutoFormSchema{},
+	}
+	addProperties(out, fields)
+
+	return out
+}
+
+func addProperties(out *sdkcore.AutoFormSchema, fields []*Field) {
+	for _, f := range fields {
+		if f.Type == TypeGroup {
+			addProperties(out, f.Nested)
+			continue
+		}
+
+		out.Properties[f.ID] = fieldSchema(f)
+		out.Order = append(out.Order, f.ID)
+		if f.Required {
+			out.Required = append(out.Required, f.ID)
+		}
+	}
+}
+
+func fieldSchema(f *Field) *sdkcore.AutoFormSchema {
+	var out *sdkcore.AutoFormSchema
+	switch f.Type {
+	case TypeObject:
+		if f.BoolProperty("anyType") {
+			out = &sdkcore.AutoFormSchema{}
+		} else {
+			out = objectSchema(f.Nested)
+		}
+	case TypeArray, TypeMultiSelect:
+		out = &sdkcore.AutoFormSchema{Type: sdkcore.Array, Items: itemSchema(f)}
+	default:
+		out = &sdkcore.AutoFormSchema{Type: scalarType(f.Type, f.BoolProperty("integer"))}
+		out.Enum = optionValues(f)
+	}
+
+	out.Title = f.Label
+	out.Description = f.HelpText
+	out.Default = f.DefaultValue
+	out.IsRequired = f.Required
+	applyRules(out, f)
+	applyProperties(out, f)
+
+	return out
+}
+
+func itemSchema(f *Field) *sdkcore.AutoFormSchema {
+	if f.Type == TypeMultiSelect {
+		return &sdkcore.AutoFormSchema{Enum: optionValues(f)}
+	}
+	if len(f.Nested) > 0 {
+		return objectSchema(f.Nested)
+	}
+	if typ := f.StringProperty("itemType"); typ != "" {
+		return &sdkcore.AutoFormSchema{Type: scalarType(typ, f.BoolProperty("integer"))}
+	}
+
+	return nil
+}
+
+func scalarType(typ string, integer bool) sdkcore.AutoFormType {
+	switch typ {
+	case TypeText, TypeTextarea, TypeEmail, TypePassword, TypeDate, TypeDateTime:
+		return sdkcore.String
+	case TypeNumber:
+		if integer {
+			return sdkcore.Integer
+		}
+		return sdkcore.Number
+	case TypeCheckbox:
+		return sdkcore.Boolean
+	case TypeObject:
+		return sdkcore.Object
+	case TypeArray:
+		return sdkcore.Array
+	default:
+		// select values and files have no fixed JSON type
+		return ""
+	}
+}
+
+func optionValues(f *Field) []any {
+	if f.Options == nil || len(f.Options.Static) == 0 {
+		return nil
+	}
+
+	values := make([]any, len(f.Options.Static))
+	for i, o := range f.Options.Static {
+		values[i] = o.Value
+	}
+
+	return values
+}
+
+func applyRules(out *sdkcore.AutoFormSchema, f *Field) {
+	for _, r := range f.ValidationRules {
+		switch r.Type {
+		case RuleMin:
+			out.Minimum = r.Parameters
+		case RuleMax:
+			out.Maximum = r.Parameters
+		case RuleMinLength:
+			out.MinLength = intParam(r.Parameters)
+		case RuleMaxLength:
+			out.MaxLength = intParam(r.Parameters)
+		case RuleMinItems:
+			out.MinItems = intParam(r.Parameters)
+		case RuleMaxItems:
+			out.MaxItems = intParam(r.Parameters)
+		case RulePattern:
+			out.Pattern, _ = r.Parameters.(string)
+		case RuleEmail:
+			out.Format = "email"
+		case RuleURL:
+			out.Format = "uri"
+		}
+	}
+}
+
+func applyProperties(out *sdkcore.AutoFormSchema, f *Field) {
+	hidden, disabled := f.BoolProperty("hidden"), f.BoolProperty("disabled")
+	if hidden || disabled {
+		out.UIProps = &sdkcore.AutoFormFieldProps{Hidden: hidden, Disabled: disabled}
+	}
+	out.Disabled = disabled
+	out.UniqueItems = f.BoolProperty("uniqueItems")
+	if v, ok := f.Properties["const"]; ok {
+		out.Const = v
+	}
+
+	switch deps := f.Properties["dependsOn"].(type) {
+	case []string:
+		out.DependsOn = deps
+	case []any:
+		for _, d := range deps {
+			if s, ok := d.(string); ok {
+				out.DependsOn = append(out.DependsOn, s)
+			}
+		}
+	}
+}
+
+func intParam(v any) *int {
+	n, ok := toFloat(v)
+	if !ok {
+		return nil
+	}
+	i := int(n)
+
+	return &i
+}
@@ -0,0 +1,236 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package form is a JSON-level view of smartform schemas.
+//
+// Smartform schemas are exchanged as JSON, so this package reads and writes
+// them through their JSON form. Tooling that inspects or generates schemas,
+// such as validation, visibility and documentation, works on Schema and Field
+// without depending on the smartform builder API.
+package form
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/juicycleff/smartform/v1"
+)
+
+// Field types.
+const (
+	TypeText        = "text"
+	TypeTextarea    = "textarea"
+	TypeNumber      = "number"
+	TypeEmail       = "email"
+	TypePassword    = "password"
+	TypeSelect      = "select"
+	TypeMultiSelect = "multiselect"
+	TypeCheckbox    = "checkbox"
+	TypeDate        = "date"
+	TypeDateTime    = "datetime"
+	TypeFile        = "file"
+	TypeObject      = "object"
+	TypeGroup       = "group"
+	TypeArray       = "array"
+)
+
+// Validation rule types.
+const (
+	RuleMin       = "min"
+	RuleMax       = "max"
+	RuleMinLength = "minLength"
+	RuleMaxLength = "maxLength"
+	RuleMinItems  = "minItems"
+	RuleMaxItems  = "maxItems"
+	RulePattern   = "pattern"
+	RuleEmail     = "email"
+	RuleURL       = "url"
+)
+
+// Schema is a form schema.
+type Schema struct {
+	// ID is the unique identifier of the form
+	ID string `json:"id"`
+
+	// Title is the human-readable name of the form
+	Title string `json:"title"`
+
+	// Description explains the purpose of the form
+	Description string `json:"description,omitempty"`
+
+	// Type is the kind of form
+	Type string `json:"type,omitempty"`
+
+	// Fields are the top-level fields of the form
+	Fields []*Field `json:"fields"`
+
+	// Properties holds additional form attributes
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Field is a form field.
+type Field struct {
+	// ID is the key of the field's value in the form input
+	ID string `json:"id"`
+
+	// Type is the kind of input, such as TypeText or TypeObject
+	Type string `json:"type"`
+
+	// Label is the human-readable name of the field
+	Label string `json:"label,omitempty"`
+
+	// Required marks the field as mandatory
+	Required bool `json:"required,omitempty"`
+
+	// DefaultValue is used when no value is given
+	DefaultValue interface{} `json:"defaultValue,omitempty"`
+
+	// Placeholder is shown in an empty input
+	Placeholder string `json:"placeholder,omitempty"`
+
+	// HelpText describes the field
+	HelpText string `json:"helpText,omitempty"`
+
+	// Options lists the values a select field accepts
+	Options *Options `json:"options,omitempty"`
+
+	// ValidationRules constrain the field's value
+	ValidationRules []*Rule `json:"validationRules,omitempty"`
+
+	// Nested are the child fields of an object or group, or the item fields of an array of objects
+	Nested []*Field `json:"nested,omitempty"`
+
+	// Properties holds additional field attributes
+	Properties map[string]interface{} `json:"properties,omitempty"`
+
+	// Order is the position of the field in its form
+	Order int `json:"order"`
+}
+
+// Options describes the choices of a select field.
+type Options struct {
+	// Type is how the options are provided, "static" for a fixed list
+	Type string `json:"type"`
+
+	// Static is the fixed list of options
+	Static []*Option `json:"static,omitempty"`
+}
+
+// Option is a choice of a select field.
+type Option struct {
+	// Value is the value stored when the option is chosen
+	Value interface{} `json:"value"`
+
+	// Label is the human-readable name of the option
+	Label string `json:"label"`
+}
+
+// Rule is a validation rule.
+type Rule struct {
+	// Type is the kind of rule, such as RuleMin or RulePattern
+	Type string `json:"type"`
+
+	// Message is shown when the rule fails
+	Message string `json:"message,omitempty"`
+
+	// Parameters configure the rule, such as the bound of RuleMin
+	Parameters interface{} `json:"parameters,omitempty"`
+}
+
+// FromSmartform returns the view of a smartform schema. A nil schema yields nil.
+func FromSmartform(schema *smartform.FormSchema) (*Schema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("form: encode smartform schema: %w", err)
+	}
+
+	var out Schema
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("form: decode smartform schema: %w", err)
+	}
+
+	return &out, nil
+}
+
+// Smartform converts s to a smartform schema.
+func (s *Schema) Smartform() (*smartform.FormSchema, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("form: encode schema: %w", err)
+	}
+
+	var out smartform.FormSchema
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("form: decode smartform schema: %w", err)
+	}
+
+	return &out, nil
+}
+
+// Field returns the field at a dot-separated path, descending through nested fields.
+func (s *Schema) Field(path string) *Field {
+	fields := s.Fields
+	var found *Field
+	for _, id := range strings.Split(path, ".") {
+		found = nil
+		for _, f := range fields {
+			if f.ID == id {
+				found = f
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		fields = found.Nested
+	}
+
+	return found
+}
+
+// Rule returns the field's first rule of type typ.
+func (f *Field) Rule(typ string) *Rule {
+	for _, r := range f.ValidationRules {
+		if r.Type == typ {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// BoolProperty returns the boolean field property key, which is false if unset.
+func (f *Field) BoolProperty(key string) bool {
+	v, _ := f.Properties[key].(bool)
+	return v
+}
+
+// StringProperty returns the string field property key, which is empty if unset.
+func (f *Field) StringProperty(key string) string {
+	v, _ := f.Properties[key].(string)
+	return v
+}
+
+// SetProperty sets a field property.
+func (f *Field) SetProperty(key string, value interface{}) {
+	if f.Properties == nil {
+		f.Properties = map[string]interface{}{}
+	}
+	f.Properties[key] = value
+}
@@ -0,0 +1,173 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	Street string `json:"street" form:"required"`
+	City   string `json:"city"`
+}
+
+type baseInput struct {
+	AccountID string `json:"account_id" validate:"required"`
+}
+
+type sendInput struct {
+	baseInput
+	APIKey     string            `json:"api_key" label:"API Key" form:"secret,required"`
+	Recipient  string            `json:"recipient" description:"Who receives the message." form:"format=email"`
+	Priority   string            `json:"priority" form:"enum=low|normal|high,default=normal"`
+	Retries    int               `json:"retries" form:"min=0,max=5,default=3"`
+	Subject    string            `json:"subject" form:"max=120" pattern:"^[^\\n]*$"`
+	Tags       []string          `json:"tags,omitempty" form:"max=10"`
+	Channels   []string          `json:"channels" form:"enum=email|sms"`
+	Address    *address          `json:"address"`
+	Recipients []address         `json:"recipients"`
+	Headers    map[string]string `json:"headers"`
+	SendAt     time.Time         `json:"send_at"`
+	Timeout    time.Duration     `json:"timeout" form:"default=30s"`
+	Internal   string            `json:"-"`
+	Ignored    string            `form:"-"`
+	hidden     string
+}
+
+func TestFromSmartformBuilder(t *testing.T) {
+	builder := smartform.NewForm("send_message", "Send Message")
+	builder.TextField("channel", "Channel").
+		Placeholder("#general").
+		HelpText("Where to post.").
+		Required(true)
+	builder.TextField("text", "Text")
+	built := builder.Build()
+
+	text := built.Fields[1]
+	text.Nested = []*smartform.Field{{ID: "street", Type: "text", Label: "Street"}}
+	text.Properties = map[string]interface{}{"dependsOn": []interface{}{"channel"}}
+
+	schema, err := FromSmartform(built)
+	require.NoError(t, err)
+	require.Equal(t, "send_message", schema.ID)
+	require.Equal(t, "Send Message", schema.Title)
+
+	channel := schema.Field("channel")
+	require.NotNil(t, channel)
+	require.Equal(t, TypeText, channel.Type)
+	require.Equal(t, "Channel", channel.Label)
+	require.True(t, channel.Required)
+	require.Equal(t, "#general", channel.Placeholder)
+	require.Equal(t, "Where to post.", channel.HelpText)
+
+	require.Equal(t, "Street", schema.Field("text.street").Label)
+	require.Equal(t, []interface{}{"channel"}, schema.Field("text").Properties["dependsOn"])
+
+	back, err := schema.Smartform()
+	require.NoError(t, err)
+	require.Len(t, back.Fields, 2)
+	require.Equal(t, built.Fields[0].Placeholder, back.Fields[0].Placeholder)
+	require.True(t, back.Fields[0].Required)
+	require.Equal(t, "street", back.Fields[1].Nested[0].ID)
+}
+
+func TestFromStruct(t *testing.T) {
+	schema, err := FromStruct(reflect.TypeFor[sendInput]())
+	require.NoError(t, err)
+	require.Equal(t, "send_input", schema.ID)
+
+	var ids []string
+	for _, f := range schema.Fields {
+		ids = append(ids, f.ID)
+	}
+	require.Equal(t, []string{"account_id", "api_key", "recipient", "priority", "retries", "subject", "tags", "channels", "address", "recipients", "headers", "send_at", "timeout"}, ids)
+
+	require.True(t, schema.Field("account_id").Required)
+
+	apiKey := schema.Field("api_key")
+	require.Equal(t, "API Key", apiKey.Label)
+	require.Equal(t, TypePassword, apiKey.Type)
+	require.True(t, apiKey.Required)
+	require.True(t, apiKey.BoolProperty("secret"))
+
+	recipient := schema.Field("recipient")
+	require.Equal(t, TypeEmail, recipient.Type)
+	require.Equal(t, "Who receives the message.", recipient.HelpText)
+	require.NotNil(t, recipient.Rule(RuleEmail))
+
+	priority := schema.Field("priority")
+	require.Equal(t, TypeSelect, priority.Type)
+	require.Equal(t, "normal", priority.DefaultValue)
+	require.Len(t, priority.Options.Static, 3)
+
+	retries := schema.Field("retries")
+	require.Equal(t, TypeNumber, retries.Type)
+	require.Equal(t, int64(3), retries.DefaultValue)
+	require.Equal(t, 5.0, retries.Rule(RuleMax).Parameters)
+
+	require.Equal(t, 120.0, schema.Field("subject").Rule(RuleMaxLength).Parameters)
+	require.Equal(t, `^[^\n]*$`, schema.Field("subject").Rule(RulePattern).Parameters)
+	require.Equal(t, 10.0, schema.Field("tags").Rule(RuleMaxItems).Parameters)
+	require.Equal(t, TypeMultiSelect, schema.Field("channels").Type)
+
+	require.Equal(t, TypeObject, schema.Field("address").Type)
+	require.True(t, schema.Field("address.street").Required)
+	require.Equal(t, "Street", schema.Field("address.street").Label)
+
+	recipients := schema.Field("recipients")
+	require.Equal(t, TypeArray, recipients.Type)
+	require.Equal(t, TypeObject, recipients.StringProperty("itemType"))
+	require.Len(t, recipients.Nested, 2)
+
+	require.Equal(t, TypeText, schema.Field("headers").StringProperty("valueType"))
+	require.Equal(t, TypeDateTime, schema.Field("send_at").Type)
+	require.Equal(t, "duration", schema.Field("timeout").StringProperty("format"))
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	var decoded Schema
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.True(t, decoded.Field("api_key").BoolProperty("secret"))
+	require.Equal(t, "street", decoded.Field("recipients").Nested[0].ID)
+}
+
+func TestFromStructErrors(t *testing.T) {
+	type badOption struct {
+		Name string `form:"colour=red"`
+	}
+	type badBound struct {
+		Ok bool `form:"min=1"`
+	}
+	type node struct {
+		Children []node `json:"children"`
+	}
+
+	for _, typ := range []reflect.Type{reflect.TypeFor[badOption](), reflect.TypeFor[badBound](), reflect.TypeFor[node](), reflect.TypeFor[string]()} {
+		_, err := FromStruct(typ)
+		require.Error(t, err, typ.String())
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	for in, want := range map[string]string{"APIKey": "API Key", "UserID": "User ID", "first_name": "First Name", "HTTPServerURL": "HTTP Server URL", "Retries2": "Retries2"} {
+		require.Equal(t, want, humanize(in))
+	}
+}
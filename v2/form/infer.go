@@ -0,0 +1,200 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	sdkcore "github.com/wakflo/go-sdk/core"
+)
+
+var (
+	// ErrUnknownPath is returned by SchemaAt for a path the schema does not describe
+	ErrUnknownPath = errors.New("form: unknown path")
+
+	// ErrTypeMismatch is returned by CheckType when a value has the wrong type
+	ErrTypeMismatch = errors.New("form: type mismatch")
+)
+
+// InferSchema describes the shape of a sample value, such as an action's
+// SampleOutput. Whole numbers are integers and RFC 3339 strings have the
+// date-time format. Array items are merged into one schema; items of
+// different types, and null values, leave the type open. No property is
+// required, since a sample cannot tell which are optional.
+func InferSchema(sample any) *sdkcore.AutoFormSchema {
+	value, err := normalize(sample)
+	if err != nil {
+		return &sdkcore.AutoFormSchema{}
+	}
+
+	return infer(value)
+}
+
+func infer(value any) *sdkcore.AutoFormSchema {
+	switch val := value.(type) {
+	case string:
+		s := &sdkcore.AutoFormSchema{Type: sdkcore.String}
+		if _, err := time.Parse(time.RFC3339, val); err == nil {
+			s.Format = "date-time"
+		}
+		return s
+	case json.Number:
+		if hasType(sdkcore.Integer, val) {
+			return &sdkcore.AutoFormSchema{Type: sdkcore.Integer}
+		}
+		return &sdkcore.AutoFormSchema{Type: sdkcore.Number}
+	case bool:
+		return &sdkcore.AutoFormSchema{Type: sdkcore.Boolean}
+	case []any:
+		s := &sdkcore.AutoFormSchema{Type: sdkcore.Array}
+		for i, item := range val {
+			if i == 0 {
+				s.Items = infer(item)
+			} else {
+				s.Items = mergeInferred(s.Items, infer(item))
+			}
+		}
+		return s
+	case map[string]any:
+		s := &sdkcore.AutoFormSchema{Type: sdkcore.Object, Properties: map[string]*sdkcore.AutoFormSchema{}}
+		for key, item := range val {
+			s.Properties[key] = infer(item)
+		}
+		return s
+	default:
+		return &sdkcore.AutoFormSchema{}
+	}
+}
+
+func mergeInferred(a, b *sdkcore.AutoFormSchema) *sdkcore.AutoFormSchema {
+	switch {
+	case a.Type == b.Type:
+	case a.Type == sdkcore.Integer && b.Type == sdkcore.Number, a.Type == sdkcore.Number && b.Type == sdkcore.Integer:
+		return &sdkcore.AutoFormSchema{Type: sdkcore.Number}
+	default:
+		return &sdkcore.AutoFormSchema{}
+	}
+
+	out := *a
+	if a.Format != b.Format {
+		out.Format = ""
+	}
+
+	switch a.Type {
+	case sdkcore.Array:
+		switch {
+		case a.Items == nil:
+			out.Items = b.Items
+		case b.Items != nil:
+			out.Items = mergeInferred(a.Items, b.Items)
+		}
+	case sdkcore.Object:
+		out.Properties = make(map[string]*sdkcore.AutoFormSchema, len(a.Properties))
+		for key, prop := range a.Properties {
+			out.Properties[key] = prop
+		}
+		for key, prop := range b.Properties {
+			if existing, ok := out.Properties[key]; ok {
+				out.Properties[key] = mergeInferred(existing, prop)
+			} else {
+				out.Properties[key] = prop
+			}
+		}
+	}
+
+	return &out
+}
+
+var indexPattern = regexp.MustCompile(`\[(\d*)\]`)
+
+// SchemaAt returns the schema of the value at a path in values described by
+// schema. Path segments are separated by dots and array items are addressed
+// as "items.0", "items[0]" or "items[]". A schema with no type or properties
+// describes any value, so every path below it resolves to an empty schema.
+func SchemaAt(schema *sdkcore.AutoFormSchema, path string) (*sdkcore.AutoFormSchema, error) {
+	path = indexPattern.ReplaceAllStringFunc(path, func(m string) string {
+		if m == "[]" {
+			return ".0"
+		}
+		return "." + m[1:len(m)-1]
+	})
+
+	current := schema
+	var walked []string
+	for _, seg := range strings.Split(strings.Trim(path, "."), ".") {
+		if seg == "" {
+			continue
+		}
+		if current == nil || (current.Type == "" && current.Properties == nil && current.Items == nil) {
+			return &sdkcore.AutoFormSchema{}, nil
+		}
+
+		walked = append(walked, seg)
+		switch {
+		case current.Type == sdkcore.Array || current.Items != nil:
+			if !isIndex(seg) {
+				return nil, fmt.Errorf("%w: %s is an array", ErrUnknownPath, strings.Join(walked[:len(walked)-1], "."))
+			}
+			current = current.Items
+		default:
+			prop, ok := current.Properties[seg]
+			if !ok {
+				if current.Type == sdkcore.Object && len(current.Properties) == 0 && current.AdditionalProperties == nil {
+					return &sdkcore.AutoFormSchema{}, nil
+				}
+				return nil, fmt.Errorf("%w: %s", ErrUnknownPath, strings.Join(walked, "."))
+			}
+			current = prop
+		}
+	}
+
+	if current == nil {
+		return &sdkcore.AutoFormSchema{}, nil
+	}
+
+	return current, nil
+}
+
+// CheckType reports whether the value at path can be used where a value of
+// type want is expected. Open types match anything and integers are numbers.
+func CheckType(schema *sdkcore.AutoFormSchema, path string, want sdkcore.AutoFormType) error {
+	s, err := SchemaAt(schema, path)
+	if err != nil {
+		return err
+	}
+
+	got := s.Type
+	if got == "" || got == sdkcore.Undefined || want == "" || want == sdkcore.Undefined ||
+		got == want || (want == sdkcore.Number && got == sdkcore.Integer) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s is %s, want %s", ErrTypeMismatch, path, got, want)
+}
+
+func isIndex(seg string) bool {
+	for _, r := range seg {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return seg != ""
+}
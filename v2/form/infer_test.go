@@ -0,0 +1,87 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkcore "github.com/wakflo/go-sdk/core"
+)
+
+func TestInferSchema(t *testing.T) {
+	schema := InferSchema(map[string]any{
+		"id":      42,
+		"created": "2024-01-02T03:04:05Z",
+		"score":   0.5,
+		"tags":    []string{"a"},
+		"rows": []any{
+			map[string]any{"n": 1, "name": "x"},
+			map[string]any{"n": 1.5, "extra": true},
+		},
+		"mixed": []any{1, "a"},
+		"note":  nil,
+	})
+
+	require.Equal(t, sdkcore.Object, schema.Type)
+	require.Equal(t, sdkcore.Integer, schema.Properties["id"].Type)
+	require.Equal(t, "date-time", schema.Properties["created"].Format)
+	require.Equal(t, sdkcore.Number, schema.Properties["score"].Type)
+	require.Equal(t, sdkcore.String, schema.Properties["tags"].Items.Type)
+	require.Empty(t, schema.Properties["mixed"].Items.Type)
+	require.Empty(t, schema.Properties["note"].Type)
+
+	rows := schema.Properties["rows"].Items
+	require.Equal(t, sdkcore.Number, rows.Properties["n"].Type)
+	require.Len(t, rows.Properties, 3)
+	require.Empty(t, schema.Required)
+
+	require.NoError(t, Validate(schema, map[string]any{"id": 7, "rows": []any{}}))
+	require.Error(t, Validate(schema, map[string]any{"id": "7"}))
+}
+
+func TestSchemaAt(t *testing.T) {
+	schema := InferSchema(map[string]any{
+		"user":  map[string]any{"name": "Ada", "age": 36},
+		"items": []any{map[string]any{"sku": "a1"}},
+		"meta":  map[string]any{},
+		"raw":   nil,
+	})
+
+	s, err := SchemaAt(schema, "user.name")
+	require.NoError(t, err)
+	require.Equal(t, sdkcore.String, s.Type)
+
+	for _, path := range []string{"items.0.sku", "items[3].sku", "items[].sku"} {
+		s, err = SchemaAt(schema, path)
+		require.NoError(t, err, path)
+		require.Equal(t, sdkcore.String, s.Type, path)
+	}
+
+	_, err = SchemaAt(schema, "user.email")
+	require.ErrorIs(t, err, ErrUnknownPath)
+	_, err = SchemaAt(schema, "items.sku")
+	require.ErrorIs(t, err, ErrUnknownPath)
+
+	// open schemas accept any path
+	_, err = SchemaAt(schema, "meta.anything")
+	require.NoError(t, err)
+	_, err = SchemaAt(schema, "raw.a.b")
+	require.NoError(t, err)
+
+	require.NoError(t, CheckType(schema, "user.age", sdkcore.Number))
+	require.ErrorIs(t, CheckType(schema, "user.age", sdkcore.String), ErrTypeMismatch)
+	require.ErrorIs(t, CheckType(schema, "user.nope", sdkcore.String), ErrUnknownPath)
+}
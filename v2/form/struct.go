@@ -0,0 +1,467 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+	rawType      = reflect.TypeFor[json.RawMessage]()
+)
+
+// FromStruct derives a schema from the exported fields of the struct type t,
+// keyed by their JSON names so that input decoded with encoding/json matches
+// the form. Fields are described by struct tags:
+//
+//	label:"API Key"             field label, derived from the Go name by default
+//	description:"..."           help text
+//	placeholder:"..."           placeholder text
+//	pattern:"^[a-z]+$"          regular expression the value must match
+//	form:"required,secret,..."  comma-separated options
+//
+// The form options are:
+//
+//	form:"-"        skip the field
+//	required        the field is mandatory; validate:"required" also marks it
+//	secret          the value is a credential, rendered as a password field
+//	type=textarea   override the field type
+//	format=email    value format: email, url, date, date-time or textarea
+//	default=10      default value, with slice elements separated by '|'
+//	enum=a|b|c      allowed values, making the field a select or multiselect
+//	min=1,max=10    bounds on numbers, string lengths or item counts
+//
+// Structs become object fields with nested fields, slices become array fields
+// whose nested fields describe struct items, maps become object fields,
+// time.Time becomes a datetime field and time.Duration a text field with the
+// duration format. Embedded structs without a JSON name are flattened.
+func FromStruct(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("form: %s is not a struct", t)
+	}
+
+	fields, err := structFields(t, "", map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{ID: snakeCase(t.Name()), Title: humanize(t.Name()), Fields: fields}, nil
+}
+
+func structFields(t reflect.Type, prefix string, seen map[reflect.Type]bool) ([]*Field, error) {
+	if seen[t] {
+		return nil, fmt.Errorf("form: %s: recursive type %s", prefix, t)
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	var fields []*Field
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		name, skip := jsonName(sf)
+		if skip || strings.TrimSpace(sf.Tag.Get("form")) == "-" {
+			continue
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded, err := structFields(ft, prefix, seen)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embedded...)
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		f, err := structField(sf, ft, name, path, seen)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+
+	for i, f := range fields {
+		f.Order = i
+	}
+
+	return fields, nil
+}
+
+// jsonName returns the name encoding/json uses for sf, and whether it skips sf.
+func jsonName(sf reflect.StructField) (string, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	return name, false
+}
+
+type fieldTags struct {
+	required bool
+	secret   bool
+	typ      string
+	format   string
+	def      *string
+	enum     []string
+	min, max *float64
+}
+
+func parseTags(sf reflect.StructField, path string) (fieldTags, error) {
+	var tags fieldTags
+	for _, opt := range strings.Split(sf.Tag.Get("form"), ",") {
+		key, value, hasValue := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "":
+		case "required":
+			tags.required = true
+		case "secret":
+			tags.secret = true
+		case "type":
+			tags.typ = value
+		case "format":
+			tags.format = value
+		case "default":
+			tags.def = &value
+		case "enum":
+			tags.enum = strings.Split(value, "|")
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil || !hasValue {
+				return tags, fmt.Errorf("form: %s: invalid %s %q", path, key, value)
+			}
+			if key == "min" {
+				tags.min = &n
+			} else {
+				tags.max = &n
+			}
+		default:
+			return tags, fmt.Errorf("form: %s: unknown form option %q", path, key)
+		}
+	}
+
+	for _, rule := range strings.Split(sf.Tag.Get("validate"), ",") {
+		if rule == "required" {
+			tags.required = true
+		}
+	}
+
+	return tags, nil
+}
+
+func structField(sf reflect.StructField, t reflect.Type, name, path string, seen map[reflect.Type]bool) (*Field, error) {
+	tags, err := parseTags(sf, path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Field{
+		ID:          name,
+		Label:       sf.Tag.Get("label"),
+		HelpText:    sf.Tag.Get("description"),
+		Placeholder: sf.Tag.Get("placeholder"),
+		Required:    tags.required,
+	}
+	if f.Label == "" {
+		f.Label = humanize(sf.Name)
+	}
+
+	if err := setType(f, t, tags, path, seen); err != nil {
+		return nil, err
+	}
+
+	if len(tags.enum) > 0 {
+		if err := setEnum(f, t, tags.enum, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if tags.def != nil {
+		def, err := parseDefault(t, *tags.def)
+		if err != nil {
+			return nil, fmt.Errorf("form: %s: invalid default: %w", path, err)
+		}
+		f.DefaultValue = def
+	}
+
+	if err := setBounds(f, t, tags); err != nil {
+		return nil, fmt.Errorf("form: %s: %w", path, err)
+	}
+
+	if pattern := sf.Tag.Get("pattern"); pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("form: %s: invalid pattern: %w", path, err)
+		}
+		f.ValidationRules = append(f.ValidationRules, &Rule{Type: RulePattern, Parameters: pattern})
+	}
+
+	if tags.secret {
+		f.Type = TypePassword
+		f.SetProperty("secret", true)
+	}
+	if tags.typ != "" {
+		f.Type = tags.typ
+	}
+
+	return f, nil
+}
+
+// setType sets the type of f from the Go type t.
+func setType(f *Field, t reflect.Type, tags fieldTags, path string, seen map[reflect.Type]bool) error {
+	switch {
+	case t == timeType:
+		f.Type = TypeDateTime
+		if tags.format == "date" {
+			f.Type = TypeDate
+		}
+		f.SetProperty("format", cmp.Or(tags.format, "date-time"))
+		return nil
+	case t == durationType:
+		f.Type = TypeText
+		f.SetProperty("format", "duration")
+		return nil
+	case t == rawType:
+		f.Type = TypeObject
+		f.SetProperty("anyType", true)
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		f.Type = TypeText
+		switch tags.format {
+		case "":
+		case "email":
+			f.Type = TypeEmail
+			f.ValidationRules = append(f.ValidationRules, &Rule{Type: RuleEmail})
+		case "url", "uri":
+			f.ValidationRules = append(f.ValidationRules, &Rule{Type: RuleURL})
+		case "textarea":
+			f.Type = TypeTextarea
+		case "date":
+			f.Type = TypeDate
+		case "date-time":
+			f.Type = TypeDateTime
+		default:
+			return fmt.Errorf("form: %s: unknown format %q", path, tags.format)
+		}
+		if tags.format != "" && tags.format != "textarea" {
+			f.SetProperty("format", tags.format)
+		}
+	case reflect.Bool:
+		f.Type = TypeCheckbox
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.Type = TypeNumber
+		f.SetProperty("integer", true)
+	case reflect.Float32, reflect.Float64:
+		f.Type = TypeNumber
+	case reflect.Struct:
+		nested, err := structFields(t, path, seen)
+		if err != nil {
+			return err
+		}
+		f.Type = TypeObject
+		f.Nested = nested
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			f.Type = TypeFile
+			return nil
+		}
+
+		item := &Field{ID: "item"}
+		elem := t.Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if err := setType(item, elem, fieldTags{format: tags.format}, path+"[]", seen); err != nil {
+			return err
+		}
+
+		f.Type = TypeArray
+		f.SetProperty("itemType", item.Type)
+		f.Nested = item.Nested
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return fmt.Errorf("form: %s: map keys must be strings", path)
+		}
+
+		value := &Field{ID: "value"}
+		elem := t.Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if err := setType(value, elem, fieldTags{}, path+"{}", seen); err != nil {
+			return err
+		}
+
+		f.Type = TypeObject
+		f.SetProperty("valueType", value.Type)
+	case reflect.Interface:
+		f.Type = TypeObject
+		f.SetProperty("anyType", true)
+	default:
+		return fmt.Errorf("form: %s: unsupported type %s", path, t)
+	}
+
+	return nil
+}
+
+func setEnum(f *Field, t reflect.Type, enum []string, path string) error {
+	elem := t
+	f.Type = TypeSelect
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		elem = t.Elem()
+		f.Type = TypeMultiSelect
+	}
+
+	f.Options = &Options{Type: "static"}
+	for _, raw := range enum {
+		value, err := parseScalar(elem, raw)
+		if err != nil {
+			return fmt.Errorf("form: %s: invalid enum value: %w", path, err)
+		}
+		f.Options.Static = append(f.Options.Static, &Option{Value: value, Label: raw})
+	}
+
+	return nil
+}
+
+func setBounds(f *Field, t reflect.Type, tags fieldTags) error {
+	if tags.min == nil && tags.max == nil {
+		return nil
+	}
+
+	var minRule, maxRule string
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		minRule, maxRule = RuleMin, RuleMax
+	case reflect.String:
+		minRule, maxRule = RuleMinLength, RuleMaxLength
+	case reflect.Slice, reflect.Array, reflect.Map:
+		minRule, maxRule = RuleMinItems, RuleMaxItems
+	default:
+		return fmt.Errorf("min and max do not apply to %s", t)
+	}
+
+	if tags.min != nil {
+		f.ValidationRules = append(f.ValidationRules, &Rule{Type: minRule, Parameters: *tags.min})
+	}
+	if tags.max != nil {
+		f.ValidationRules = append(f.ValidationRules, &Rule{Type: maxRule, Parameters: *tags.max})
+	}
+
+	return nil
+}
+
+func parseDefault(t reflect.Type, raw string) (interface{}, error) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return parseScalar(t, raw)
+	}
+
+	out := []interface{}{}
+	for _, item := range strings.Split(raw, "|") {
+		value, err := parseScalar(t.Elem(), item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, value)
+	}
+
+	return out, nil
+}
+
+func parseScalar(t reflect.Type, raw string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == durationType {
+		if _, err := time.ParseDuration(raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(raw, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(raw, 10, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(raw, t.Bits())
+	}
+
+	return raw, nil
+}
+
+// humanize turns a Go identifier such as "APIKey" or "first_name" into a
+// label such as "API Key" or "First Name".
+func humanize(name string) string {
+	runes := []rune(strings.ReplaceAll(name, "_", " "))
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && runes[i-1] != ' ' {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteRune(' ')
+			}
+		}
+		if i == 0 || runes[i-1] == ' ' {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func snakeCase(name string) string {
+	return strings.ToLower(strings.ReplaceAll(humanize(name), " ", "_"))
+}
@@ -0,0 +1,491 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sdkcore "github.com/wakflo/go-sdk/core"
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// ValidationError is a value that does not satisfy its schema.
+type ValidationError struct {
+	// Pointer is the RFC 6901 JSON pointer of the value, empty for the input itself
+	Pointer string `json:"pointer"`
+
+	// Keyword is the schema keyword that failed, such as "required" or "pattern"
+	Keyword string `json:"keyword"`
+
+	// Message describes the failure
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	if e.Pointer == "" {
+		return e.Message
+	}
+
+	return e.Pointer + ": " + e.Message
+}
+
+// ValidationErrors are all the failures found in an input, in schema order.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return "form: invalid input: " + strings.Join(msgs, "; ")
+}
+
+// Is matches sdkerrors.ErrValidation.
+func (e ValidationErrors) Is(target error) bool {
+	return target == sdkerrors.ErrValidation
+}
+
+// Validate checks input against s. See Validate for the semantics.
+func (s *Schema) Validate(input core.JSONObject) error {
+	return Validate(s.AutoForm(), input)
+}
+
+// Validate checks input against a JSON schema and returns ValidationErrors
+// listing every failure, or nil.
+//
+// The type, required, enum, const, pattern, format, minimum, maximum,
+// minLength, maxLength, minItems, maxItems, uniqueItems, minProperties,
+// maxProperties, additionalProperties (false only), allOf, anyOf, oneOf,
+// not and if/then/else keywords are enforced. A property is skipped, even
+// when required, if it is disabled or hidden, or if any of the sibling
+// properties it DependsOn is missing, null, false or empty. Missing and null
+// values count as absent.
+func Validate(schema *sdkcore.AutoFormSchema, input any) error {
+	value, err := normalize(input)
+	if err != nil {
+		return fmt.Errorf("form: encode input: %w", err)
+	}
+
+	v := &validator{patterns: map[string]*regexp.Regexp{}}
+	v.validate(schema, value, "")
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+
+	return nil
+}
+
+type validator struct {
+	errs     ValidationErrors
+	patterns map[string]*regexp.Regexp
+}
+
+func (v *validator) fail(ptr, keyword, format string, args ...any) {
+	v.errs = append(v.errs, &ValidationError{Pointer: ptr, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches reports whether value satisfies schema, without recording errors.
+func (v *validator) matches(schema *sdkcore.AutoFormSchema, value any, ptr string) bool {
+	sub := &validator{patterns: v.patterns}
+	sub.validate(schema, value, ptr)
+
+	return len(sub.errs) == 0
+}
+
+func (v *validator) validate(s *sdkcore.AutoFormSchema, value any, ptr string) {
+	if s == nil {
+		return
+	}
+
+	if !hasType(s.Type, value) {
+		v.fail(ptr, "type", "must be %s, got %s", s.Type, typeName(value))
+		return
+	}
+
+	if s.Const != nil && !equal(mustNormalize(s.Const), value) {
+		v.fail(ptr, "const", "must equal %v", s.Const)
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return equal(mustNormalize(e), value) }) {
+		v.fail(ptr, "enum", "must be one of %v", s.Enum)
+	}
+
+	switch val := value.(type) {
+	case string:
+		v.validateString(s, val, ptr)
+	case json.Number:
+		v.validateNumber(s, val, ptr)
+	case []any:
+		v.validateArray(s, val, ptr)
+	case map[string]any:
+		v.validateObject(s, val, ptr)
+	}
+
+	for _, sub := range s.AllOf {
+		v.validate(sub, value, ptr)
+	}
+	if len(s.AnyOf) > 0 && !slices.ContainsFunc(s.AnyOf, func(sub *sdkcore.AutoFormSchema) bool { return v.matches(sub, value, ptr) }) {
+		v.fail(ptr, "anyOf", "must match at least one schema")
+	}
+	if len(s.OneOf) > 0 {
+		n := 0
+		for _, sub := range s.OneOf {
+			if v.matches(sub, value, ptr) {
+				n++
+			}
+		}
+		if n != 1 {
+			v.fail(ptr, "oneOf", "must match exactly one schema, matched %d", n)
+		}
+	}
+	if s.Not != nil && v.matches(s.Not, value, ptr) {
+		v.fail(ptr, "not", "must not match schema")
+	}
+	if s.If != nil {
+		if v.matches(s.If, value, ptr) {
+			v.validate(s.Then, value, ptr)
+		} else {
+			v.validate(s.Else, value, ptr)
+		}
+	}
+}
+
+func (v *validator) validateString(s *sdkcore.AutoFormSchema, val, ptr string) {
+	n := utf8.RuneCountInString(val)
+	if s.MinLength != nil && n < *s.MinLength {
+		v.fail(ptr, "minLength", "must be at least %d characters", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		v.fail(ptr, "maxLength", "must be at most %d characters", *s.MaxLength)
+	}
+
+	if s.Pattern != "" {
+		re, ok := v.patterns[s.Pattern]
+		if !ok {
+			re, _ = regexp.Compile(s.Pattern)
+			v.patterns[s.Pattern] = re
+		}
+		if re == nil {
+			v.fail(ptr, "pattern", "schema pattern %q is invalid", s.Pattern)
+		} else if !re.MatchString(val) {
+			v.fail(ptr, "pattern", "must match pattern %q", s.Pattern)
+		}
+	}
+
+	if !validFormat(s.Format, val) {
+		v.fail(ptr, "format", "must be a valid %s", s.Format)
+	}
+}
+
+func validFormat(format, val string) bool {
+	switch format {
+	case "email":
+		addr, err := mail.ParseAddress(val)
+		return err == nil && addr.Address == val
+	case "uri", "url":
+		u, err := url.Parse(val)
+		return err == nil && u.Scheme != ""
+	case "date":
+		_, err := time.Parse(time.DateOnly, val)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, val)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+func (v *validator) validateNumber(s *sdkcore.AutoFormSchema, val json.Number, ptr string) {
+	n, _ := toFloat(val)
+	if min, ok := toFloat(s.Minimum); ok && n < min {
+		v.fail(ptr, "minimum", "must be at least %v", s.Minimum)
+	}
+	if max, ok := toFloat(s.Maximum); ok && n > max {
+		v.fail(ptr, "maximum", "must be at most %v", s.Maximum)
+	}
+	if min, ok := toFloat(s.ExclusiveMinimum); ok && n <= min {
+		v.fail(ptr, "exclusiveMinimum", "must be greater than %v", s.ExclusiveMinimum)
+	}
+	if max, ok := toFloat(s.ExclusiveMaximum); ok && n >= max {
+		v.fail(ptr, "exclusiveMaximum", "must be less than %v", s.ExclusiveMaximum)
+	}
+}
+
+func (v *validator) validateArray(s *sdkcore.AutoFormSchema, val []any, ptr string) {
+	if s.MinItems != nil && len(val) < *s.MinItems {
+		v.fail(ptr, "minItems", "must have at least %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && len(val) > *s.MaxItems {
+		v.fail(ptr, "maxItems", "must have at most %d items", *s.MaxItems)
+	}
+
+	if s.UniqueItems {
+	outer:
+		for i := 1; i < len(val); i++ {
+			for j := 0; j < i; j++ {
+				if equal(val[i], val[j]) {
+					v.fail(ptr, "uniqueItems", "items %d and %d are equal", j, i)
+					break outer
+				}
+			}
+		}
+	}
+
+	for i, item := range val {
+		v.validate(s.Items, item, ptr+"/"+strconv.Itoa(i))
+	}
+}
+
+func (v *validator) validateObject(s *sdkcore.AutoFormSchema, val map[string]any, ptr string) {
+	required := map[string]bool{}
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	for name, prop := range s.Properties {
+		if prop != nil && (prop.IsRequired || (prop.UIProps != nil && prop.UIProps.Required)) {
+			required[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(s.Properties)+len(required))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	for name := range required {
+		if _, ok := s.Properties[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		prop := s.Properties[name]
+		if !active(prop, val) {
+			continue
+		}
+
+		child := ptr + "/" + escapePointer(name)
+		item, ok := val[name]
+		if !ok || item == nil {
+			if required[name] {
+				v.fail(child, "required", "is required")
+			}
+			continue
+		}
+		v.validate(prop, item, child)
+	}
+
+	if s.MinProperties != nil && len(val) < *s.MinProperties {
+		v.fail(ptr, "minProperties", "must have at least %d properties", *s.MinProperties)
+	}
+	if s.MaxProperties != nil && len(val) > *s.MaxProperties {
+		v.fail(ptr, "maxProperties", "must have at most %d properties", *s.MaxProperties)
+	}
+
+	if allowed, ok := s.AdditionalProperties.(bool); ok && !allowed {
+		var extra []string
+		for name := range val {
+			if _, ok := s.Properties[name]; !ok {
+				extra = append(extra, name)
+			}
+		}
+		slices.Sort(extra)
+		for _, name := range extra {
+			v.fail(ptr+"/"+escapePointer(name), "additionalProperties", "is not allowed")
+		}
+	}
+}
+
+// active reports whether a property takes part in validation given the
+// values of its siblings.
+func active(prop *sdkcore.AutoFormSchema, siblings map[string]any) bool {
+	if prop == nil {
+		return true
+	}
+	if prop.Disabled || (prop.UIProps != nil && (prop.UIProps.Hidden || prop.UIProps.Disabled)) {
+		return false
+	}
+
+	for _, dep := range prop.DependsOn {
+		if isEmpty(siblings[dep]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isEmpty(value any) bool {
+	switch val := value.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case bool:
+		return !val
+	case []any:
+		return len(val) == 0
+	case map[string]any:
+		return len(val) == 0
+	default:
+		return false
+	}
+}
+
+func hasType(typ sdkcore.AutoFormType, value any) bool {
+	switch typ {
+	case sdkcore.String:
+		_, ok := value.(string)
+		return ok
+	case sdkcore.Number:
+		_, ok := value.(json.Number)
+		return ok
+	case sdkcore.Integer:
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == float64(int64(f))
+	case sdkcore.Boolean:
+		_, ok := value.(bool)
+		return ok
+	case sdkcore.Object:
+		_, ok := value.(map[string]any)
+		return ok
+	case sdkcore.Array:
+		_, ok := value.([]any)
+		return ok
+	case sdkcore.Nullable:
+		return value == nil
+	default:
+		return true
+	}
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// equal compares normalized values, treating numbers by value.
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fx, _ := x.Float64()
+		fy, _ := y.Float64()
+		return fx == fy
+	case []any:
+		y, ok := b.([]any)
+		return ok && slices.EqualFunc(x, y, equal)
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, xv := range x {
+			yv, ok := y[k]
+			if !ok || !equal(xv, yv) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// normalize converts value to its generic JSON form, with numbers as json.Number.
+func normalize(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func mustNormalize(value any) any {
+	out, err := normalize(value)
+	if err != nil {
+		return value
+	}
+
+	return out
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case nil:
+		return 0, false
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), true
+	case rv.CanUint():
+		return float64(rv.Uint()), true
+	case rv.CanFloat():
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
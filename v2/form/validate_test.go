@@ -0,0 +1,141 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkcore "github.com/wakflo/go-sdk/core"
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+)
+
+func intPtr(n int) *int { return &n }
+
+func validationErrors(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	out := map[string]string{}
+	for _, e := range errs {
+		out[e.Pointer] = e.Keyword
+	}
+
+	return out
+}
+
+func TestValidateKeywords(t *testing.T) {
+	schema := &sdkcore.AutoFormSchema{
+		Type:     sdkcore.Object,
+		Required: []string{"name"},
+		Properties: map[string]*sdkcore.AutoFormSchema{
+			"name":  {Type: sdkcore.String, MinLength: intPtr(2), Pattern: "^[a-z]+$"},
+			"count": {Type: sdkcore.Integer, Minimum: 1, Maximum: 10},
+			"kind":  {Enum: []any{"a", "b"}},
+			"v":     {Const: 2},
+			"tags": {
+				Type:        sdkcore.Array,
+				MaxItems:    intPtr(3),
+				UniqueItems: true,
+				Items:       &sdkcore.AutoFormSchema{Type: sdkcore.String},
+			},
+			"a/b": {Type: sdkcore.Boolean},
+		},
+	}
+
+	require.NoError(t, Validate(schema, map[string]any{
+		"name": "ada", "count": 3, "kind": "b", "v": 2.0, "tags": []string{"x", "y"}, "a/b": true,
+	}))
+
+	err := Validate(schema, map[string]any{
+		"count": 2.5, "kind": "c", "v": 3, "tags": []any{"x", 1, "x", "z"}, "a/b": "yes",
+	})
+	require.Equal(t, map[string]string{
+		"/name":   "required",
+		"/count":  "type",
+		"/kind":   "enum",
+		"/v":      "const",
+		"/tags":   "uniqueItems",
+		"/tags/1": "type",
+		"/a~1b":   "type",
+	}, validationErrors(t, err))
+
+	require.ErrorIs(t, err, sdkerrors.ErrValidation)
+
+	err = Validate(schema, map[string]any{"name": "A", "count": 11})
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 3)
+	require.Equal(t, "maximum", errs[0].Keyword)
+	require.Equal(t, "minLength", errs[1].Keyword)
+	require.Equal(t, "pattern", errs[2].Keyword)
+}
+
+func TestValidateCombinators(t *testing.T) {
+	str := &sdkcore.AutoFormSchema{Type: sdkcore.String}
+	num := &sdkcore.AutoFormSchema{Type: sdkcore.Number}
+
+	require.NoError(t, Validate(&sdkcore.AutoFormSchema{AnyOf: []*sdkcore.AutoFormSchema{str, num}}, 1))
+	require.Error(t, Validate(&sdkcore.AutoFormSchema{AnyOf: []*sdkcore.AutoFormSchema{str, num}}, true))
+	require.Error(t, Validate(&sdkcore.AutoFormSchema{OneOf: []*sdkcore.AutoFormSchema{num, {Type: sdkcore.Integer}}}, 1))
+	require.NoError(t, Validate(&sdkcore.AutoFormSchema{OneOf: []*sdkcore.AutoFormSchema{num, {Type: sdkcore.Integer}}}, 1.5))
+	require.Error(t, Validate(&sdkcore.AutoFormSchema{Not: str}, "x"))
+	require.Error(t, Validate(&sdkcore.AutoFormSchema{AllOf: []*sdkcore.AutoFormSchema{str, {MaxLength: intPtr(1)}}}, "xy"))
+
+	// if mode is "token", a token is required, otherwise a password
+	cond := &sdkcore.AutoFormSchema{
+		Type: sdkcore.Object,
+		If: &sdkcore.AutoFormSchema{
+			Properties: map[string]*sdkcore.AutoFormSchema{"mode": {Const: "token"}},
+		},
+		Then: &sdkcore.AutoFormSchema{Required: []string{"token"}},
+		Else: &sdkcore.AutoFormSchema{Required: []string{"password"}},
+	}
+	require.NoError(t, Validate(cond, map[string]any{"mode": "token", "token": "t"}))
+	require.Equal(t, map[string]string{"/password": "required"}, validationErrors(t, Validate(cond, map[string]any{"mode": "basic"})))
+}
+
+func TestValidateFormSchema(t *testing.T) {
+	schema := &Schema{
+		ID: "input",
+		Fields: []*Field{
+			{ID: "email", Type: TypeEmail, Required: true, ValidationRules: []*Rule{{Type: RuleEmail}}},
+			{ID: "advanced", Type: TypeCheckbox},
+			{ID: "timeout", Type: TypeNumber, Required: true, Properties: map[string]any{"integer": true, "dependsOn": []any{"advanced"}}},
+			{ID: "legacy", Type: TypeText, Required: true, Properties: map[string]any{"hidden": true}},
+			{ID: "layout", Type: TypeGroup, Nested: []*Field{
+				{ID: "color", Type: TypeSelect, Options: &Options{Type: "static", Static: []*Option{{Value: "red"}, {Value: "blue"}}}},
+			}},
+			{ID: "items", Type: TypeArray, Nested: []*Field{
+				{ID: "sku", Type: TypeText, Required: true},
+			}, ValidationRules: []*Rule{{Type: RuleMinItems, Parameters: 1.0}}},
+		},
+	}
+
+	require.NoError(t, schema.Validate(map[string]any{
+		"email": "ada@example.com", "color": "red", "items": []any{map[string]any{"sku": "a1"}},
+	}))
+
+	err := schema.Validate(map[string]any{
+		"email": "not an email", "advanced": true, "color": "green", "items": []any{map[string]any{}},
+	})
+	require.Equal(t, map[string]string{
+		"/email":       "format",
+		"/timeout":     "required",
+		"/color":       "enum",
+		"/items/0/sku": "required",
+	}, validationErrors(t, err))
+}
@@ -0,0 +1,301 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	"encoding/json"
+	"maps"
+	"regexp"
+	"slices"
+
+	sdkcore "github.com/wakflo/go-sdk/core"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// ResolveVisibleFields returns the effective schema of a form for the given
+// values. The input schema is not modified.
+//
+// In each object, the branch of if/then/else picked by the values is merged
+// in, as are the dependencies whose key has a value and the conditional
+// members of allOf; the resolved keywords are then cleared. Properties are
+// removed when they are hidden, when a field they DependsOn is empty, or when
+// the object matches their ui:component:remove schema. A merged property that
+// already exists is constrained with allOf rather than replaced. Disabled
+// properties stay visible. Array items are left unresolved, as each item may
+// resolve differently.
+func ResolveVisibleFields(schema *sdkcore.AutoFormSchema, values core.JSONObject) *sdkcore.AutoFormSchema {
+	norm, _ := normalize(values)
+	return newResolver().resolve(schema, norm)
+}
+
+// StripHiddenValues returns a copy of values without the values of fields that
+// are not visible, so stale inputs from collapsed branches never reach an
+// operation. Values not described by the schema at all are kept. Objects in
+// arrays are stripped against the array's item schema.
+func StripHiddenValues(schema *sdkcore.AutoFormSchema, values core.JSONObject) core.JSONObject {
+	norm, _ := normalize(values)
+	normObj, _ := norm.(map[string]any)
+
+	return newResolver().stripObject(schema, values, normObj)
+}
+
+// ResolveVisibleFields returns the effective schema of s for the given values.
+// See the package-level ResolveVisibleFields.
+func (s *Schema) ResolveVisibleFields(values core.JSONObject) *sdkcore.AutoFormSchema {
+	return ResolveVisibleFields(s.AutoForm(), values)
+}
+
+// StripHiddenValues returns values without the values of fields of s that are
+// not visible. See the package-level StripHiddenValues.
+func (s *Schema) StripHiddenValues(values core.JSONObject) core.JSONObject {
+	return StripHiddenValues(s.AutoForm(), values)
+}
+
+type resolver struct {
+	v *validator
+}
+
+func newResolver() *resolver {
+	return &resolver{v: &validator{patterns: map[string]*regexp.Regexp{}}}
+}
+
+func (r *resolver) resolve(s *sdkcore.AutoFormSchema, value any) *sdkcore.AutoFormSchema {
+	if s == nil {
+		return nil
+	}
+
+	obj, _ := value.(map[string]any)
+	out := r.level(s, obj)
+	for name, prop := range out.Properties {
+		out.Properties[name] = r.resolve(prop, obj[name])
+	}
+
+	return out
+}
+
+// level resolves the conditionals and visibility of one object schema,
+// leaving its properties unresolved. Conditionals of other schemas constrain
+// a value rather than select fields, so they are kept.
+func (r *resolver) level(s *sdkcore.AutoFormSchema, obj map[string]any) *sdkcore.AutoFormSchema {
+	out := *s
+	if s.Type != sdkcore.Object && s.Properties == nil {
+		return &out
+	}
+
+	out.Properties = maps.Clone(s.Properties)
+	out.Required = slices.Clone(s.Required)
+	out.Order = slices.Clone(s.Order)
+	out.AllOf = nil
+	out.If, out.Then, out.Else = nil, nil, nil
+	out.Dependencies = nil
+
+	r.applyConditionals(&out, s, obj)
+
+	for name, prop := range out.Properties {
+		if !r.visible(prop, obj) {
+			delete(out.Properties, name)
+		}
+	}
+	live := func(name string) bool { _, ok := out.Properties[name]; return ok }
+	out.Required = slices.DeleteFunc(out.Required, func(name string) bool { return !live(name) })
+	out.Order = slices.DeleteFunc(out.Order, func(name string) bool { return !live(name) })
+
+	return &out
+}
+
+func (r *resolver) applyConditionals(out, s *sdkcore.AutoFormSchema, obj map[string]any) {
+	if s.If != nil {
+		branch := s.Else
+		if r.v.matches(s.If, obj, "") {
+			branch = s.Then
+		}
+		r.merge(out, branch, obj)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(s.Dependencies)) {
+		if isEmpty(obj[key]) {
+			continue
+		}
+
+		switch dep := s.Dependencies[key].(type) {
+		case []string:
+			out.Required = appendMissing(out.Required, dep...)
+		case []any:
+			for _, name := range dep {
+				if name, ok := name.(string); ok {
+					out.Required = appendMissing(out.Required, name)
+				}
+			}
+		default:
+			r.merge(out, dependencySchema(dep), obj)
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		if sub != nil && (sub.If != nil || len(sub.Dependencies) > 0) {
+			r.applyConditionals(out, sub, obj)
+		} else {
+			out.AllOf = append(out.AllOf, sub)
+		}
+	}
+}
+
+func (r *resolver) merge(out, branch *sdkcore.AutoFormSchema, obj map[string]any) {
+	if branch == nil {
+		return
+	}
+
+	if out.Properties == nil && len(branch.Properties) > 0 {
+		out.Properties = map[string]*sdkcore.AutoFormSchema{}
+	}
+	for _, name := range slices.Sorted(maps.Keys(branch.Properties)) {
+		prop := branch.Properties[name]
+		existing, ok := out.Properties[name]
+		if !ok || existing == nil {
+			out.Properties[name] = prop
+			out.Order = appendMissing(out.Order, name)
+			continue
+		}
+
+		constrained := *existing
+		constrained.AllOf = append(slices.Clone(existing.AllOf), prop)
+		out.Properties[name] = &constrained
+	}
+	out.Required = appendMissing(out.Required, branch.Required...)
+
+	r.applyConditionals(out, branch, obj)
+}
+
+func (r *resolver) visible(prop *sdkcore.AutoFormSchema, obj map[string]any) bool {
+	if prop == nil {
+		return true
+	}
+	if prop.UIProps != nil && prop.UIProps.Hidden {
+		return false
+	}
+	for _, dep := range prop.DependsOn {
+		if isEmpty(obj[dep]) {
+			return false
+		}
+	}
+	if prop.UIComponentRemove != nil && r.v.matches(prop.UIComponentRemove, obj, "") {
+		return false
+	}
+
+	return true
+}
+
+func (r *resolver) stripObject(s *sdkcore.AutoFormSchema, values, norm map[string]any) map[string]any {
+	if s == nil || values == nil {
+		return values
+	}
+
+	level := r.level(s, norm)
+	declared := declaredProperties(s, map[string]bool{})
+
+	out := make(map[string]any, len(values))
+	for name, value := range values {
+		prop, live := level.Properties[name]
+		if !live && declared[name] {
+			continue
+		}
+		out[name] = r.stripValue(prop, value, norm[name])
+	}
+
+	return out
+}
+
+func (r *resolver) stripValue(s *sdkcore.AutoFormSchema, value, norm any) any {
+	if s == nil {
+		return value
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		normObj, _ := norm.(map[string]any)
+		return r.stripObject(s, val, normObj)
+	case []any:
+		normArr, _ := norm.([]any)
+		out := make([]any, len(val))
+		for i, item := range val {
+			var normItem any
+			if i < len(normArr) {
+				normItem = normArr[i]
+			}
+			out[i] = r.stripValue(s.Items, item, normItem)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// declaredProperties collects the property names an object schema can have
+// under any branch.
+func declaredProperties(s *sdkcore.AutoFormSchema, names map[string]bool) map[string]bool {
+	if s == nil {
+		return names
+	}
+
+	for name := range s.Properties {
+		names[name] = true
+	}
+	for _, sub := range []*sdkcore.AutoFormSchema{s.If, s.Then, s.Else} {
+		declaredProperties(sub, names)
+	}
+	for _, subs := range [][]*sdkcore.AutoFormSchema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, sub := range subs {
+			declaredProperties(sub, names)
+		}
+	}
+	for _, dep := range s.Dependencies {
+		declaredProperties(dependencySchema(dep), names)
+	}
+
+	return names
+}
+
+// dependencySchema returns the schema form of a dependencies entry, which is
+// nil for a list of required property names.
+func dependencySchema(dep any) *sdkcore.AutoFormSchema {
+	switch d := dep.(type) {
+	case *sdkcore.AutoFormSchema:
+		return d
+	case sdkcore.AutoFormSchema:
+		return &d
+	case map[string]any:
+		data, err := json.Marshal(d)
+		if err != nil {
+			return nil
+		}
+		var out sdkcore.AutoFormSchema
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil
+		}
+		return &out
+	default:
+		return nil
+	}
+}
+
+func appendMissing(list []string, names ...string) []string {
+	for _, name := range names {
+		if !slices.Contains(list, name) {
+			list = append(list, name)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,112 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package form
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkcore "github.com/wakflo/go-sdk/core"
+)
+
+func authSchema() *sdkcore.AutoFormSchema {
+	str := func() *sdkcore.AutoFormSchema { return &sdkcore.AutoFormSchema{Type: sdkcore.String} }
+
+	return &sdkcore.AutoFormSchema{
+		Type:  sdkcore.Object,
+		Order: []string{"mode", "legacy", "proxy", "proxy_port"},
+		Properties: map[string]*sdkcore.AutoFormSchema{
+			"mode":       {Type: sdkcore.String, Enum: []any{"token", "basic"}},
+			"legacy":     {Type: sdkcore.String, UIProps: &sdkcore.AutoFormFieldProps{Hidden: true}},
+			"proxy":      str(),
+			"proxy_port": {Type: sdkcore.Integer, DependsOn: []string{"proxy"}},
+			"nested": {
+				Type: sdkcore.Object,
+				Properties: map[string]*sdkcore.AutoFormSchema{
+					"debug": {Type: sdkcore.Boolean},
+					"level": {Type: sdkcore.String, UIComponentRemove: &sdkcore.AutoFormSchema{
+						Properties: map[string]*sdkcore.AutoFormSchema{"debug": {Const: false}},
+						Required:   []string{"debug"},
+					}},
+				},
+			},
+		},
+		Required: []string{"mode"},
+		If: &sdkcore.AutoFormSchema{
+			Properties: map[string]*sdkcore.AutoFormSchema{"mode": {Const: "token"}},
+			Required:   []string{"mode"},
+		},
+		Then: &sdkcore.AutoFormSchema{
+			Properties: map[string]*sdkcore.AutoFormSchema{"token": str()},
+			Required:   []string{"token"},
+		},
+		Else: &sdkcore.AutoFormSchema{
+			Properties: map[string]*sdkcore.AutoFormSchema{"username": str(), "password": str()},
+			Required:   []string{"username", "password"},
+		},
+		Dependencies: map[string]any{
+			"proxy": map[string]any{
+				"properties": map[string]any{"proxy_auth": map[string]any{"type": "string"}},
+			},
+		},
+	}
+}
+
+func TestResolveVisibleFields(t *testing.T) {
+	schema := authSchema()
+
+	eff := ResolveVisibleFields(schema, map[string]any{"mode": "token"})
+	require.ElementsMatch(t, []string{"mode", "proxy", "nested", "token"}, keys(eff.Properties))
+	require.Equal(t, []string{"mode", "token"}, eff.Required)
+	require.Equal(t, []string{"mode", "proxy", "token"}, eff.Order)
+	require.Nil(t, eff.If)
+	require.Contains(t, schema.Properties, "legacy", "input schema must not change")
+
+	eff = ResolveVisibleFields(schema, map[string]any{"mode": "basic", "proxy": "p:1", "nested": map[string]any{"debug": false}})
+	require.ElementsMatch(t, []string{"mode", "proxy", "proxy_port", "proxy_auth", "nested", "username", "password"}, keys(eff.Properties))
+	require.Equal(t, []string{"debug"}, keys(eff.Properties["nested"].Properties))
+
+	eff = ResolveVisibleFields(schema, map[string]any{"nested": map[string]any{"debug": true}})
+	require.ElementsMatch(t, []string{"debug", "level"}, keys(eff.Properties["nested"].Properties))
+}
+
+func TestStripHiddenValues(t *testing.T) {
+	values := map[string]any{
+		"mode":       "token",
+		"token":      "t",
+		"username":   "stale",
+		"legacy":     "x",
+		"proxy_port": 8080,
+		"extra":      1,
+		"nested":     map[string]any{"debug": false, "level": "info"},
+	}
+
+	require.Equal(t, map[string]any{
+		"mode":   "token",
+		"token":  "t",
+		"extra":  1,
+		"nested": map[string]any{"debug": false},
+	}, StripHiddenValues(authSchema(), values))
+	require.Contains(t, values, "username", "input values must not change")
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+
+	return out
+}
@@ -5,17 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 
 	"github.com/cavaliergopher/grab/v3"
 	"github.com/juicycleff/smartform/v1"
-	"github.com/pelletier/go-toml/v2"
-	"github.com/wakflo/go-sdk/autoform"
 	sdkcore "github.com/wakflo/go-sdk/core"
 	sdkcontext "github.com/wakflo/go-sdk/v2/context"
 	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/flo"
 	"github.com/wakflo/go-sdk/validator"
 )
 
@@ -92,55 +92,19 @@ func DynamicInputToType[T any](ctx sdkcontext.BaseContext) *T {
 	return &rsp
 }
 
-// StringToFile converts a file string to a *autoform.File object.
-//
-// The function checks if the file string is a base64-encoded data or a URL. If the file string is base64-encoded data, it decodes the data and assigns it to the `Data` field of the
-func StringToFile(fileStr string) (*autoform.File, error) {
-	// file := &autoform.File{}
-	//
-	// if valid.IsBase64(fileStr) {
-	// 	data, err := base64.StdEncoding.DecodeString(fileStr)
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	//
-	// 	mime := mimetype.Detect(data)
-	// 	file.Data = bytes.NewReader(data)
-	// 	file.Extension = mime.Extension()
-	// 	file.Mime = mime.String()
-	//
-	// 	return file, nil
-	// }
-	//
-	// if valid.IsURL(fileStr) {
-	// 	data, err := DownloadFile(fileStr)
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	//
-	// 	bt, err := data.Bytes()
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	//
-	// 	mime := mimetype.Detect(bt)
-	// 	file.Data = bytes.NewReader(bt)
-	// 	file.Extension = mime.Extension()
-	// 	file.Size = data.Size()
-	// 	file.Name = data.Filename
-	// 	file.Mime = mime.String()
-	//
-	// 	return file, nil
-	// }
-
-	return nil, errors.New("invalid file string")
-}
-
-// DownloadFile downloads a file from the specified URL using the grab package.
-// It returns the grab.Response object and an error if any.
+// DownloadFile downloads a file from the specified URL using the grab package
+// into a new temporary directory, which the caller should remove when done.
+// It returns the grab.Response object and an error if any. StringToFile is
+// preferred for file inputs as it enforces size limits and detects the MIME type.
 func DownloadFile(url string) (*grab.Response, error) {
-	resp, err := grab.Get(".", url)
+	dir, err := os.MkdirTemp("", "wakflo-download-*")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := grab.Get(dir, url)
 	if err != nil {
+		_ = os.RemoveAll(dir)
 		return nil, err
 	}
 
@@ -189,36 +153,39 @@ func registerInternal(flow string, readme string) (*IntegrationMetadata, error)
 	return info, nil
 }
 
-type IntegrationSchemaModel struct {
-	Name        string   `json:"name" toml:"name" yaml:"name" validate:"required"`
-	Description string   `json:"description" toml:"description"  yaml:"description" validate:"required"`
-	Version     string   `json:"version" toml:"version" yaml:"version" validate:"required"`
-	Authors     []string `json:"authors" toml:"authors" yaml:"authors" validate:"required"`
-	Website     *string  `json:"website" toml:"website" yaml:"website"`
-	Categories  []string `json:"categories" toml:"categories" yaml:"categories" validate:"required"`
-	Icon        string   `json:"icon" toml:"icon" yaml:"icon" validate:"required"`
-}
+// IntegrationSchemaModel is the [integration] table of flo.toml as described by
+// schemas/integration.schema.json.
+type IntegrationSchemaModel = flo.Integration
 
 type SchemaConfig struct {
 	Integration IntegrationMetadata `json:"integration" toml:"integration" yaml:"integration" validate:"required"`
 }
 
+// ReadFloFile validates a flo.toml document against the integration schema and
+// returns the metadata it declares. Validation errors are reported as
+// flo.ValidationErrors with the line and column of each offending key.
 func ReadFloFile(content string) (*IntegrationMetadata, error) {
-	// Deserialize the file content into SchemaConfig
-	var config SchemaConfig
-	err := toml.Unmarshal([]byte(content), &config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to deserialize TOML: %w", err)
-	}
-
-	v := validator.NewDefaultValidator()
-
-	err = v.Validate(&config)
+	file, err := flo.Parse([]byte(content))
 	if err != nil {
 		return nil, err
 	}
 
-	return &config.Integration, nil
+	info := file.Integration
+
+	return &IntegrationMetadata{
+		Name:             info.Name,
+		DisplayName:      info.DisplayName,
+		Description:      info.Description,
+		Group:            info.Group,
+		Version:          info.Version,
+		Icon:             info.Icon,
+		Logo:             info.Logo,
+		Authors:          info.Authors,
+		Website:          info.Website,
+		Categories:       info.Categories,
+		Tags:             info.Tags,
+		DocumentationURL: info.Documentation,
+	}, nil
 }
 
 // ReadREADME extracts the content of README.md from the current directory.
@@ -267,22 +234,47 @@ func GetRelativePathWithDepth(relativePath string, depth int) (string, error) {
 // DynamicOptionsFn defines a function type that processes a DynamicFieldContext and returns a DynamicOptionsResponse or an error.
 type DynamicOptionsFn = func(ctx sdkcontext.DynamicFieldContext) (*core.DynamicOptionsResponse, error)
 
+// DynamicOptionsProvider is implemented by actions and triggers whose fields load
+// their options at runtime, so the options functions can be invoked outside the form.
+// Functions handed to smartform with WithDynamicFunctionCalling cannot be read back
+// from the schema, so they must be reported here too.
+type DynamicOptionsProvider interface {
+	// DynamicOptions returns the options function of each dynamic field, keyed by field ID
+	DynamicOptions() map[string]DynamicOptionsFn
+}
+
+// OperationDynamicOptions returns the options function of each dynamic field
+// of an action or trigger, keyed by field ID, as reported by its
+// DynamicOptionsProvider. It is empty when op is not a provider.
+func OperationDynamicOptions(op any) map[string]DynamicOptionsFn {
+	fns := map[string]DynamicOptionsFn{}
+	if provider, ok := op.(DynamicOptionsProvider); ok {
+		for field, fn := range provider.DynamicOptions() {
+			if fn != nil {
+				fns[field] = fn
+			}
+		}
+	}
+
+	return fns
+}
+
+// ErrNoDynamicFieldContext is returned by a function built with
+// WithDynamicFunctionCalling when its "ctx" argument is missing or is not a
+// DynamicFieldContext.
+var ErrNoDynamicFieldContext = errors.New("dynamic function: \"ctx\" argument must be a DynamicFieldContext")
+
 // WithDynamicFunctionCalling wraps a DynamicOptionsFn into a smartform.DynamicFunction to execute dynamic field actions.
-// If the provided function is nil or required arguments are missing in the call context, it returns nil.
+// The call arguments must carry the DynamicFieldContext under "ctx"; otherwise, or if fn is nil, an error is returned.
 func WithDynamicFunctionCalling(fn *DynamicOptionsFn) smartform.DynamicFunction {
 	return func(args map[string]interface{}, formState map[string]interface{}) (interface{}, error) {
-		if fn == nil {
-			return nil, nil
-		}
-
-		ctxRaw, ok := args["ctx"]
-		if !ok {
-			return nil, nil
+		if fn == nil || *fn == nil {
+			return nil, errors.New("dynamic function: no options function")
 		}
 
-		ctx, ok := ctxRaw.(sdkcontext.DynamicFieldContext)
+		ctx, ok := args["ctx"].(sdkcontext.DynamicFieldContext)
 		if !ok {
-			return nil, nil
+			return nil, ErrNoDynamicFieldContext
 		}
 
 		return (*fn)(ctx)
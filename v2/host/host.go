@@ -0,0 +1,265 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package host runs an integration inside a throwaway process on behalf of the
+// wakflo CLI. The CLI generates a main package that calls Main with the
+// integration under development and talks to it through command-line
+// arguments and JSON on stdout.
+package host
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/docs"
+	"github.com/wakflo/go-sdk/v2/sdktest"
+)
+
+// Exit codes reported by Main.
+const (
+	// ExitOK means the command succeeded
+	ExitOK = 0
+
+	// ExitFailure means the command ran but reported a problem
+	ExitFailure = 1
+
+	// ExitUsage means the command line was not understood
+	ExitUsage = 2
+)
+
+// RunRequest is read from stdin by the run-* commands.
+type RunRequest struct {
+	// Input is the resolved step input
+	Input core.JSONObject `json:"input,omitempty"`
+
+	// Auth holds the credentials exposed through AuthContext
+	Auth *sdkcontext.AuthContext `json:"auth,omitempty"`
+
+	// LastRun is passed to a trigger's ExecuteContext
+	LastRun *time.Time `json:"lastRun,omitempty"`
+
+	// Filter selects the page of dynamic options
+	Filter *core.DynamicOptionsFilterParams `json:"filter,omitempty"`
+
+	// Environment is the execution environment, core.EnvironmentDev if empty
+	Environment core.Environment `json:"environment,omitempty"`
+}
+
+// Main dispatches os.Args to Run and exits with its status code. Log lines are
+// reported by the run-* commands, so the global logger is silenced.
+func Main(integration sdk.Integration) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(Run(integration, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Run executes a single host command against integration and returns the exit code.
+//
+// Supported commands:
+//
+//	describe                       print the IntegrationDefinition as JSON
+//	validate                       check metadata and schemas, printing one problem per line
+//	run-action <action>            perform an action
+//	run-trigger <trigger>          call Start, Execute and Stop on a trigger
+//	run-options <operation> <field> invoke a dynamic options function
+//	docs [markdown|json]           print the reference documentation
+//
+// The run-* commands read a RunRequest from stdin and print the sdktest result as
+// JSON. Outside production, action and trigger outputs are checked against their
+// declared OutputSchema.
+func Run(integration sdk.Integration, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "host: missing command")
+		return ExitUsage
+	}
+
+	switch args[0] {
+	case "describe":
+		return describe(integration, stdout, stderr)
+	case "validate":
+		return validate(integration, stdout, stderr)
+	case "docs":
+		return writeDocs(integration, args[1:], stdout, stderr)
+	case "run-action", "run-trigger", "run-options":
+		return runOperation(integration, args, stdin, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "host: unknown command %q\n", args[0])
+		return ExitUsage
+	}
+}
+
+func describe(integration sdk.Integration, stdout, stderr io.Writer) int {
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(sdk.NewIntegrationDefinition(integration)); err != nil {
+		fmt.Fprintf(stderr, "describe: %s\n", err)
+		return ExitFailure
+	}
+
+	return ExitOK
+}
+
+func validate(integration sdk.Integration, stdout, stderr io.Writer) int {
+	if err := sdk.ValidateIntegration(integration); err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitFailure
+	}
+
+	fmt.Fprintf(stdout, "%s %s is valid\n", integration.Metadata().Name, integration.Metadata().Version)
+	return ExitOK
+}
+
+func writeDocs(integration sdk.Integration, args []string, stdout, stderr io.Writer) int {
+	format := docs.FormatMarkdown
+	if len(args) > 0 {
+		format = docs.Format(args[0])
+	}
+
+	if err := docs.Write(stdout, sdk.NewIntegrationDefinition(integration), format); err != nil {
+		fmt.Fprintf(stderr, "docs: %s\n", err)
+		return ExitFailure
+	}
+
+	return ExitOK
+}
+
+func runOperation(integration sdk.Integration, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	want := 2
+	if args[0] == "run-options" {
+		want = 3
+	}
+
+	if len(args) != want {
+		fmt.Fprintf(stderr, "%s: wrong number of arguments\n", args[0])
+		return ExitUsage
+	}
+
+	var req RunRequest
+	if err := json.NewDecoder(stdin).Decode(&req); err != nil && err != io.EOF {
+		fmt.Fprintf(stderr, "%s: invalid request: %s\n", args[0], err)
+		return ExitUsage
+	}
+
+	if req.Input == nil {
+		req.Input = core.JSONObject{}
+	}
+
+	if req.Environment == "" {
+		req.Environment = core.EnvironmentDev
+	}
+
+	opts := []sdktest.Option{
+		sdktest.WithInput(req.Input),
+		sdktest.WithAuth(req.Auth),
+		sdktest.WithEnvironment(req.Environment),
+	}
+
+	var (
+		result any
+		err    error
+	)
+
+	switch args[0] {
+	case "run-action":
+		action := findAction(integration, args[1])
+		if action == nil {
+			fmt.Fprintf(stderr, "run-action: unknown action %q\n", args[1])
+			return ExitFailure
+		}
+
+		res := sdktest.RunAction(sdk.WithOutputValidation(action, req.Environment), opts...)
+		result, err = res, res.Err()
+	case "run-trigger":
+		trigger := findTrigger(integration, args[1])
+		if trigger == nil {
+			fmt.Fprintf(stderr, "run-trigger: unknown trigger %q\n", args[1])
+			return ExitFailure
+		}
+
+		res := sdktest.RunTrigger(sdk.WithTriggerOutputValidation(trigger, req.Environment), req.LastRun, opts...)
+		result, err = res, res.Err()
+	case "run-options":
+		fn, ferr := findOptions(integration, args[1], args[2])
+		if ferr != nil {
+			fmt.Fprintf(stderr, "run-options: %s\n", ferr)
+			return ExitFailure
+		}
+
+		res := sdktest.RunOptions(fn, args[1], args[2], append(opts, sdktest.WithFilter(req.Filter))...)
+		result, err = res, res.Err()
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if encErr := enc.Encode(result); encErr != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", args[0], encErr)
+		return ExitFailure
+	}
+
+	if err != nil {
+		return ExitFailure
+	}
+
+	return ExitOK
+}
+
+func findAction(integration sdk.Integration, id string) sdk.Action {
+	for _, action := range integration.Actions() {
+		if action.Metadata().ID == id {
+			return action
+		}
+	}
+
+	return nil
+}
+
+func findTrigger(integration sdk.Integration, id string) sdk.Trigger {
+	for _, trigger := range integration.Triggers() {
+		if trigger.Metadata().ID == id {
+			return trigger
+		}
+	}
+
+	return nil
+}
+
+// findOptions looks up the options function of field on the action or trigger
+// with the given ID, as reported by sdk.DynamicOptionsProvider.
+func findOptions(integration sdk.Integration, operationID, field string) (sdk.DynamicOptionsFn, error) {
+	var fns map[string]sdk.DynamicOptionsFn
+	if action := findAction(integration, operationID); action != nil {
+		fns = sdk.OperationDynamicOptions(action)
+	} else if trigger := findTrigger(integration, operationID); trigger != nil {
+		fns = sdk.OperationDynamicOptions(trigger)
+	} else {
+		return nil, fmt.Errorf("unknown action or trigger %q", operationID)
+	}
+
+	if len(fns) == 0 {
+		return nil, fmt.Errorf("%s has no dynamic fields", operationID)
+	}
+
+	fn, ok := fns[field]
+	if !ok {
+		return nil, fmt.Errorf("%s has no dynamic field %q", operationID, field)
+	}
+
+	return fn, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/stretchr/testify/require"
+	sdkcore "github.com/wakflo/go-sdk/core"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+type echoIntegration struct{}
+
+func (echoIntegration) Metadata() sdk.IntegrationMetadata {
+	return sdk.IntegrationMetadata{Name: "echo"}
+}
+
+func (echoIntegration) Auth() *core.AuthMetadata { return nil }
+
+func (echoIntegration) Triggers() []sdk.Trigger { return nil }
+
+func (echoIntegration) Actions() []sdk.Action { return []sdk.Action{&echoAction{}} }
+
+type echoAction struct{}
+
+func (a *echoAction) Metadata() sdk.ActionMetadata {
+	return sdk.ActionMetadata{
+		ID:          "echo",
+		DisplayName: "Echo",
+		Description: "Echoes the message.",
+		Type:        core.ActionTypeAction,
+		OutputSchema: &sdkcore.AutoFormSchema{
+			Type:       sdkcore.Object,
+			Properties: map[string]*sdkcore.AutoFormSchema{"message": {Type: sdkcore.Integer}},
+		},
+	}
+}
+
+func (a *echoAction) Properties() *smartform.FormSchema { return &smartform.FormSchema{ID: "echo"} }
+
+func (a *echoAction) Auth() *core.AuthMetadata { return nil }
+
+func (a *echoAction) Perform(ctx sdkcontext.PerformContext) (core.JSON, error) {
+	return map[string]any{"message": ctx.Input()["message"]}, nil
+}
+
+func TestRunActionValidatesOutput(t *testing.T) {
+	run := func(request string) (int, string) {
+		var stdout, stderr bytes.Buffer
+		code := Run(echoIntegration{}, []string{"run-action", "echo"}, strings.NewReader(request), &stdout, &stderr)
+		return code, stdout.String()
+	}
+
+	code, out := run(`{"input":{"message":"hi"}}`)
+	require.Equal(t, ExitFailure, code)
+	require.Contains(t, out, "/message: must be integer")
+
+	code, out = run(`{"input":{"message":1}}`)
+	require.Equal(t, ExitOK, code, out)
+
+	code, out = run(`{"input":{"message":"hi"},"environment":"prod"}`)
+	require.Equal(t, ExitOK, code, out)
+
+	validated := sdk.WithOutputValidation(&echoAction{}, core.EnvironmentDev)
+	require.Same(t, validated, sdk.WithOutputValidation(validated, core.EnvironmentDev))
+}
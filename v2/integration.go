@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/flo"
 )
 
 // IntegrationType defines the type of integration
@@ -136,9 +137,15 @@ type IntegrationMetadata struct {
 	// Name is the human-readable name of the integration
 	Name string `json:"name" toml:"name" yaml:"name" validate:"required"`
 
+	// DisplayName is the name shown to users in the workflow builder
+	DisplayName string `json:"displayName,omitempty" toml:"display_name" yaml:"display_name"`
+
 	// Description provides details about the integration's purpose
 	Description string `json:"description" toml:"description"  yaml:"description" validate:"required"`
 
+	// Group is the predefined group the integration belongs to (apps, ai, core or tools)
+	Group flo.Group `json:"group,omitempty" toml:"group" yaml:"group"`
+
 	// Type categorizes the integration functionality
 	Type IntegrationType `json:"type"`
 
@@ -150,6 +157,9 @@ type IntegrationMetadata struct {
 	// Icon is a URL or base64-encoded image for the integration icon
 	Icon string `json:"icon" toml:"icon" yaml:"icon" validate:"required"`
 
+	// Logo is a URL to the integration logo image
+	Logo string `json:"logo,omitempty" toml:"logo,omitempty" yaml:"logo,omitempty"`
+
 	// Publisher identifies who created the integration
 	Authors []string `json:"authors" toml:"authors" yaml:"authors" validate:"required"`
 
@@ -166,7 +176,10 @@ type IntegrationMetadata struct {
 	ReleaseNotes string `json:"releaseNotes,omitempty"`
 
 	// Documentation provides comprehensive usage instructions
-	Documentation string `json:"documentation,omitempty"`
+	Documentation string `json:"documentation,omitempty" toml:"-"`
+
+	// DocumentationURL links to externally hosted documentation
+	DocumentationURL string `json:"documentationUrl,omitempty" toml:"documentation,omitempty" yaml:"documentation,omitempty"`
 }
 
 func LoadMetadataFromFlo(flo string, readme string) IntegrationMetadata {
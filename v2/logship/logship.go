@@ -0,0 +1,381 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logship delivers log lines asynchronously in batches.
+//
+// A Shipper's Write method matches the onWrite callback of core.NewLog, so it
+// can be plugged in directly:
+//
+//	shipper := logship.New(deliver)
+//	defer shipper.Close(ctx)
+//	log := core.NewLog(projectID, flowID, &stepRunID, shipper.Write)
+package logship
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/validator"
+)
+
+// ErrClosed is returned by Flush after the Shipper is closed.
+var ErrClosed = errors.New("logship: shipper closed")
+
+// DeliverFunc sends a batch of log lines to their destination.
+type DeliverFunc func(ctx context.Context, batch []core.WriteLogLineOpts) error
+
+// OverflowPolicy decides what happens when a Write finds the queue full.
+type OverflowPolicy int
+
+const (
+	// DropDebugFirst drops an incoming debug line, or else evicts the oldest
+	// queued debug line, or else the oldest queued line
+	DropDebugFirst OverflowPolicy = iota
+
+	// DropNewest drops the incoming line
+	DropNewest
+
+	// Block makes Write wait until the queue has room or the shipper closes
+	Block
+)
+
+// Stats counts what happened to written lines.
+type Stats struct {
+	// Accepted is the number of lines queued
+	Accepted int64
+
+	// Delivered is the number of lines in batches that were delivered
+	Delivered int64
+
+	// Dropped is the number of lines dropped or evicted because the queue was full
+	Dropped int64
+
+	// Invalid is the number of lines rejected by validation
+	Invalid int64
+
+	// Failed is the number of lines in batches whose delivery failed
+	Failed int64
+}
+
+// Option configures a Shipper.
+type Option func(*config)
+
+type config struct {
+	batchSize     int
+	flushInterval time.Duration
+	queueSize     int
+	overflow      OverflowPolicy
+	timeout       time.Duration
+	onError       func(err error, batch []core.WriteLogLineOpts)
+	validate      func(core.WriteLogLineOpts) error
+}
+
+// WithBatchSize delivers a batch as soon as n lines are queued. Defaults to
+// 100, and never exceeds the queue size.
+func WithBatchSize(n int) Option {
+	return func(c *config) { c.batchSize = max(n, 1) }
+}
+
+// WithFlushInterval delivers queued lines at least this often. Defaults to one second.
+func WithFlushInterval(d time.Duration) Option {
+	return func(c *config) { c.flushInterval = d }
+}
+
+// WithQueueSize bounds how many lines wait for delivery. Defaults to 10000.
+func WithQueueSize(n int) Option {
+	return func(c *config) { c.queueSize = max(n, 1) }
+}
+
+// WithOverflowPolicy sets what happens when the queue is full. Defaults to DropDebugFirst.
+func WithOverflowPolicy(p OverflowPolicy) Option {
+	return func(c *config) { c.overflow = p }
+}
+
+// WithDeliveryTimeout bounds each delivery. Defaults to ten seconds.
+func WithDeliveryTimeout(d time.Duration) Option {
+	return func(c *config) { c.timeout = d }
+}
+
+// WithErrorHandler is called with invalid lines and failed batches. Errors are
+// otherwise only counted in Stats.
+func WithErrorHandler(fn func(err error, batch []core.WriteLogLineOpts)) Option {
+	return func(c *config) { c.onError = fn }
+}
+
+// WithValidator replaces the check applied to each line before delivery. By
+// default lines are validated against the WriteLogLineOpts struct tags.
+func WithValidator(fn func(core.WriteLogLineOpts) error) Option {
+	return func(c *config) { c.validate = fn }
+}
+
+// Shipper queues log lines and delivers them in batches from a background
+// goroutine. It is safe for concurrent use.
+type Shipper struct {
+	deliver DeliverFunc
+	cfg     config
+
+	mu      sync.Mutex
+	room    *sync.Cond
+	queue   []core.WriteLogLineOpts
+	stats   Stats
+	closed  bool
+	flushes []chan struct{}
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New starts a Shipper that delivers batches with deliver.
+func New(deliver DeliverFunc, opts ...Option) *Shipper {
+	v := validator.NewDefaultValidator()
+	cfg := config{
+		batchSize:     100,
+		flushInterval: time.Second,
+		queueSize:     10000,
+		timeout:       10 * time.Second,
+		validate:      func(line core.WriteLogLineOpts) error { return v.Validate(line) },
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.batchSize = min(cfg.batchSize, cfg.queueSize)
+
+	s := &Shipper{
+		deliver: deliver,
+		cfg:     cfg,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	s.room = sync.NewCond(&s.mu)
+
+	go s.run()
+	return s
+}
+
+// Write queues a copy of line. It never blocks unless the overflow policy is
+// Block. Lines written after Close are dropped.
+func (s *Shipper) Write(line core.WriteLogLineOpts) {
+	line = line.Clone()
+	if line.CreatedAt == nil {
+		now := time.Now()
+		line.CreatedAt = &now
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for !s.closed && len(s.queue) >= s.cfg.queueSize {
+		if s.cfg.overflow == Block {
+			s.room.Wait()
+			continue
+		}
+
+		if !s.makeRoom(line) {
+			s.stats.Dropped++
+			return
+		}
+	}
+
+	if s.closed {
+		s.stats.Dropped++
+		return
+	}
+
+	s.queue = append(s.queue, line)
+	s.stats.Accepted++
+
+	if len(s.queue) >= s.cfg.batchSize {
+		s.signal()
+	}
+}
+
+// makeRoom evicts a queued line for line and reports whether line should be queued.
+func (s *Shipper) makeRoom(line core.WriteLogLineOpts) bool {
+	if s.cfg.overflow == DropNewest || isDebug(line) {
+		return false
+	}
+
+	evict := 0
+	for i, queued := range s.queue {
+		if isDebug(queued) {
+			evict = i
+			break
+		}
+	}
+
+	s.queue = append(s.queue[:evict], s.queue[evict+1:]...)
+	s.stats.Dropped++
+	return true
+}
+
+func isDebug(line core.WriteLogLineOpts) bool {
+	return line.Level != nil && *line.Level == core.LogLineLevelDebug.String()
+}
+
+// Flush delivers every line queued before the call and waits for it to finish.
+func (s *Shipper) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+
+	flushed := make(chan struct{})
+	s.flushes = append(s.flushes, flushed)
+	s.mu.Unlock()
+
+	s.signal()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting lines, delivers everything queued and waits until
+// delivery finishes or ctx is done.
+func (s *Shipper) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		s.room.Broadcast()
+		close(s.stop)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of the shipper's counters.
+func (s *Shipper) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stats
+}
+
+// signal wakes the delivery goroutine without blocking.
+func (s *Shipper) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Shipper) run() {
+	defer close(s.done)
+
+	var tick <-chan time.Time
+	if s.cfg.flushInterval > 0 {
+		ticker := time.NewTicker(s.cfg.flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-s.stop:
+			s.drain(true)
+			return
+		case <-s.wake:
+			s.drain(false)
+		case <-tick:
+			s.drain(true)
+		}
+	}
+}
+
+// drain delivers full batches, or every queued line when all is set or a
+// flush is pending, then releases pending flushes.
+func (s *Shipper) drain(all bool) {
+	s.mu.Lock()
+	flushes := s.flushes
+	s.flushes = nil
+	all = all || len(flushes) > 0
+	s.mu.Unlock()
+
+	for {
+		s.mu.Lock()
+		n := min(len(s.queue), s.cfg.batchSize)
+		if n == 0 || (!all && n < s.cfg.batchSize) {
+			s.mu.Unlock()
+			break
+		}
+
+		batch := make([]core.WriteLogLineOpts, n)
+		copy(batch, s.queue)
+		s.queue = append(s.queue[:0], s.queue[n:]...)
+		s.room.Broadcast()
+		s.mu.Unlock()
+
+		s.send(batch)
+	}
+
+	for _, flushed := range flushes {
+		close(flushed)
+	}
+}
+
+func (s *Shipper) send(batch []core.WriteLogLineOpts) {
+	valid := batch[:0]
+	var invalid int64
+	for _, line := range batch {
+		if err := s.cfg.validate(line); err != nil {
+			invalid++
+			s.report(fmt.Errorf("logship: invalid log line: %w", err), []core.WriteLogLineOpts{line})
+			continue
+		}
+
+		valid = append(valid, line)
+	}
+
+	var err error
+	if len(valid) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), s.cfg.timeout)
+		err = s.deliver(ctx, valid)
+		cancel()
+	}
+
+	s.mu.Lock()
+	s.stats.Invalid += invalid
+	if err != nil {
+		s.stats.Failed += int64(len(valid))
+	} else {
+		s.stats.Delivered += int64(len(valid))
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.report(fmt.Errorf("logship: deliver: %w", err), valid)
+	}
+}
+
+func (s *Shipper) report(err error, batch []core.WriteLogLineOpts) {
+	if s.cfg.onError != nil {
+		s.cfg.onError(err, batch)
+	}
+}
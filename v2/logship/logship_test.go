@@ -0,0 +1,158 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logship
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+type collector struct {
+	mu      sync.Mutex
+	batches [][]core.WriteLogLineOpts
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (c *collector) deliver(ctx context.Context, batch []core.WriteLogLineOpts) error {
+	if c.entered != nil {
+		select {
+		case c.entered <- struct{}{}:
+		default:
+		}
+	}
+
+	if c.release != nil {
+		<-c.release
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.batches = append(c.batches, append([]core.WriteLogLineOpts(nil), batch...))
+	return nil
+}
+
+func (c *collector) messages() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []string
+	for _, batch := range c.batches {
+		for _, line := range batch {
+			out = append(out, line.Message)
+		}
+	}
+
+	return out
+}
+
+func newLog(onWrite func(core.WriteLogLineOpts)) *core.Log {
+	stepRunID := uuid.NewString()
+	return core.NewLog(uuid.NewString(), uuid.NewString(), &stepRunID, onWrite)
+}
+
+func TestShipperBatches(t *testing.T) {
+	c := &collector{}
+	var invalid []error
+	s := New(c.deliver,
+		WithBatchSize(3),
+		WithFlushInterval(time.Hour),
+		WithErrorHandler(func(err error, batch []core.WriteLogLineOpts) { invalid = append(invalid, err) }),
+	)
+	log := newLog(s.Write)
+
+	var wg sync.WaitGroup
+	for i := range 7 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Info().Meta(map[string]interface{}{"i": i}).Msg(fmt.Sprintf("line %d", i))
+		}()
+	}
+	wg.Wait()
+
+	s.Write(core.WriteLogLineOpts{ProjectID: "not-a-uuid", Message: "bad"})
+	require.NoError(t, s.Flush(context.Background()))
+	require.Len(t, c.messages(), 7)
+	require.Len(t, invalid, 1)
+
+	for _, batch := range c.batches {
+		require.LessOrEqual(t, len(batch), 3)
+		for _, line := range batch {
+			require.Equal(t, "INFO", *line.Level)
+			require.Equal(t, line.Message, fmt.Sprintf("line %d", line.Metadata["i"]))
+		}
+	}
+
+	require.NoError(t, s.Close(context.Background()))
+	require.ErrorIs(t, s.Flush(context.Background()), ErrClosed)
+	require.Equal(t, Stats{Accepted: 8, Delivered: 7, Invalid: 1}, s.Stats())
+}
+
+func TestShipperOverflow(t *testing.T) {
+	c := &collector{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	s := New(c.deliver, WithQueueSize(3), WithBatchSize(1), WithFlushInterval(0))
+	log := newLog(s.Write)
+
+	// Hold the delivery goroutine in a batch so the queue fills up.
+	log.Info().Msg("in flight")
+	<-c.entered
+
+	log.Info().Msg("info 1")
+	log.Debug().Msg("debug 1")
+	log.Info().Msg("info 2")
+	log.Debug().Msg("debug 2")
+	log.Error().Msg("error 1")
+	log.Warn().Msg("warn 1")
+
+	close(c.release)
+	require.NoError(t, s.Close(context.Background()))
+	require.Equal(t, []string{"in flight", "info 2", "error 1", "warn 1"}, c.messages())
+	require.EqualValues(t, 3, s.Stats().Dropped)
+}
+
+func TestShipperTimeAndBlock(t *testing.T) {
+	c := &collector{release: make(chan struct{})}
+	s := New(c.deliver, WithQueueSize(1), WithOverflowPolicy(Block), WithFlushInterval(10*time.Millisecond))
+	log := newLog(s.Write)
+
+	log.Info().Msg("first")
+
+	written := make(chan struct{})
+	go func() {
+		log.Info().Msg("second")
+		log.Info().Msg("third")
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("Write did not block while the queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(c.release)
+	<-written
+	require.NoError(t, s.Close(context.Background()))
+	require.Equal(t, []string{"first", "second", "third"}, c.messages())
+}
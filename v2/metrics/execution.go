@@ -0,0 +1,263 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// PollYieldBuckets are the buckets of the poll yield histogram, in items.
+var PollYieldBuckets = []float64{0, 1, 5, 10, 25, 50, 100, 250, 500, 1000}
+
+// Error codes recorded for failures that carry no code of their own.
+const (
+	CodeError    = "error"
+	CodeTimeout  = "timeout"
+	CodeCanceled = "canceled"
+)
+
+// Execution holds the metrics recorded for action and trigger execution.
+type Execution struct {
+	// ActionInvocations counts Perform calls by integration and action
+	ActionInvocations *Counter
+
+	// ActionFailures counts failed Perform calls by integration, action and error code
+	ActionFailures *Counter
+
+	// ActionDuration observes Perform latency in seconds by integration and action
+	ActionDuration *Histogram
+
+	// ActionRetries counts retries scheduled by actions by integration and action
+	ActionRetries *Counter
+
+	// TriggerInvocations counts trigger calls by integration, trigger and operation
+	TriggerInvocations *Counter
+
+	// TriggerFailures counts failed trigger calls by integration, trigger, operation and error code
+	TriggerFailures *Counter
+
+	// TriggerDuration observes trigger call latency in seconds by integration, trigger and operation
+	TriggerDuration *Histogram
+
+	// PollYield observes the number of items each trigger execution returned by integration and trigger
+	PollYield *Histogram
+
+	// RateLimitWait observes time spent waiting on rate limits in seconds by integration
+	RateLimitWait *Histogram
+}
+
+// NewExecution registers the execution metrics on r, or returns the ones
+// already registered.
+func NewExecution(r *Registry) *Execution {
+	return &Execution{
+		ActionInvocations:  r.Counter("wakflo_action_invocations_total", "Action Perform calls.", "integration", "action"),
+		ActionFailures:     r.Counter("wakflo_action_failures_total", "Failed action Perform calls.", "integration", "action", "code"),
+		ActionDuration:     r.Histogram("wakflo_action_duration_seconds", "Action Perform latency.", DefBuckets, "integration", "action"),
+		ActionRetries:      r.Counter("wakflo_action_retries_total", "Retries scheduled by actions.", "integration", "action"),
+		TriggerInvocations: r.Counter("wakflo_trigger_invocations_total", "Trigger Start, Stop and Execute calls.", "integration", "trigger", "operation"),
+		TriggerFailures:    r.Counter("wakflo_trigger_failures_total", "Failed trigger calls.", "integration", "trigger", "operation", "code"),
+		TriggerDuration:    r.Histogram("wakflo_trigger_duration_seconds", "Trigger call latency.", DefBuckets, "integration", "trigger", "operation"),
+		PollYield:          r.Histogram("wakflo_trigger_poll_yield_items", "Items returned by each trigger execution.", PollYieldBuckets, "integration", "trigger"),
+		RateLimitWait:      r.Histogram("wakflo_rate_limit_wait_seconds", "Time spent waiting on rate limits.", DefBuckets, "integration"),
+	}
+}
+
+// ObserveRateLimitWait records that a call to integration waited d for a rate limit.
+func (e *Execution) ObserveRateLimitWait(integration string, d time.Duration) {
+	e.RateLimitWait.Observe(d.Seconds(), integration)
+}
+
+// ErrorCode returns the code failures caused by err are recorded under: the
+// result of a Code method if err or an error it wraps has one, the code of an
+// sdk.ActionError or other sdkerrors.Categorizer, falling back to its
+// category, otherwise CodeTimeout, CodeCanceled or CodeError. Codes are
+// declared by integrations rather than built from messages, so the label's
+// cardinality stays bounded.
+func ErrorCode(err error) string {
+	var coder interface{ Code() string }
+	if errors.As(err, &coder) && coder.Code() != "" {
+		return coder.Code()
+	}
+
+	var categorizer sdkerrors.Categorizer
+	if errors.As(err, &categorizer) {
+		e := categorizer.Categorized()
+		if e.Code != "" {
+			return e.Code
+		}
+		if e.Category != "" {
+			return string(e.Category)
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	}
+
+	return CodeError
+}
+
+// Option configures instrumentation.
+type Option func(*config)
+
+type config struct {
+	registry    *Registry
+	integration string
+	errorCode   func(error) string
+}
+
+// WithRegistry sets the registry metrics are recorded on. Defaults to Default().
+func WithRegistry(r *Registry) Option {
+	return func(c *config) { c.registry = r }
+}
+
+// WithIntegration sets the integration label of recorded metrics.
+func WithIntegration(integrationID string) Option {
+	return func(c *config) { c.integration = integrationID }
+}
+
+// WithErrorCode replaces ErrorCode as the function that labels failures.
+func WithErrorCode(fn func(error) string) Option {
+	return func(c *config) { c.errorCode = fn }
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{registry: defaultRegistry, errorCode: ErrorCode}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WrapAction returns action with its Perform calls, failures, latency and
+// retries recorded.
+func WrapAction(action sdk.Action, opts ...Option) sdk.Action {
+	cfg := newConfig(opts)
+	return &measuredAction{Action: action, cfg: cfg, m: NewExecution(cfg.registry)}
+}
+
+type measuredAction struct {
+	sdk.Action
+	cfg config
+	m   *Execution
+}
+
+// Unwrap returns the instrumented action.
+func (a *measuredAction) Unwrap() sdk.Action { return a.Action }
+
+func (a *measuredAction) Perform(ctx sdkcontext.PerformContext) (output core.JSON, err error) {
+	id := a.Metadata().ID
+	start := time.Now()
+	a.m.ActionInvocations.Inc(a.cfg.integration, id)
+
+	defer func() {
+		a.m.ActionDuration.Observe(time.Since(start).Seconds(), a.cfg.integration, id)
+		if err != nil {
+			a.m.ActionFailures.Inc(a.cfg.integration, id, a.cfg.errorCode(err))
+		}
+	}()
+
+	return a.Action.Perform(&performContext{PerformContext: ctx, retried: func() {
+		a.m.ActionRetries.Inc(a.cfg.integration, id)
+	}})
+}
+
+type performContext struct {
+	sdkcontext.PerformContext
+	retried func()
+}
+
+func (c *performContext) Retry(after time.Duration, reason string) error {
+	c.retried()
+	return c.PerformContext.Retry(after, reason)
+}
+
+// WrapTrigger returns trigger with its Start, Stop and Execute calls,
+// failures and latency recorded, and the number of items each Execute returns
+// observed as its poll yield.
+func WrapTrigger(trigger sdk.Trigger, opts ...Option) sdk.Trigger {
+	cfg := newConfig(opts)
+	return &measuredTrigger{Trigger: trigger, cfg: cfg, m: NewExecution(cfg.registry)}
+}
+
+type measuredTrigger struct {
+	sdk.Trigger
+	cfg config
+	m   *Execution
+}
+
+// Unwrap returns the instrumented trigger.
+func (t *measuredTrigger) Unwrap() sdk.Trigger { return t.Trigger }
+
+func (t *measuredTrigger) Start(ctx sdkcontext.LifecycleContext) error {
+	return t.measure("start", func() error { return t.Trigger.Start(ctx) })
+}
+
+func (t *measuredTrigger) Stop(ctx sdkcontext.LifecycleContext) error {
+	return t.measure("stop", func() error { return t.Trigger.Stop(ctx) })
+}
+
+func (t *measuredTrigger) Execute(ctx sdkcontext.ExecuteContext) (output core.JSON, err error) {
+	err = t.measure("execute", func() error {
+		output, err = t.Trigger.Execute(ctx)
+		return err
+	})
+
+	if err == nil {
+		t.m.PollYield.Observe(float64(itemCount(output)), t.cfg.integration, t.Metadata().ID)
+	}
+
+	return output, err
+}
+
+func (t *measuredTrigger) measure(operation string, fn func() error) error {
+	id := t.Metadata().ID
+	start := time.Now()
+	t.m.TriggerInvocations.Inc(t.cfg.integration, id, operation)
+
+	err := fn()
+	t.m.TriggerDuration.Observe(time.Since(start).Seconds(), t.cfg.integration, id, operation)
+	if err != nil {
+		t.m.TriggerFailures.Inc(t.cfg.integration, id, operation, t.cfg.errorCode(err))
+	}
+
+	return err
+}
+
+// itemCount is the number of items in a trigger's output: the length of a
+// slice, zero for nil and one for anything else.
+func itemCount(output core.JSON) int {
+	if output == nil {
+		return 0
+	}
+
+	if v := reflect.ValueOf(output); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		return v.Len()
+	}
+
+	return 1
+}
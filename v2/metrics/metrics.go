@@ -0,0 +1,321 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics collects counters, gauges and histograms for workers and
+// action execution, and exposes them in the Prometheus text format.
+//
+// Metrics are registered on a Registry and recorded with positional label
+// values:
+//
+//	reg := metrics.NewRegistry()
+//	requests := reg.Counter("requests_total", "Requests served.", "code")
+//	requests.Inc("200")
+//
+//	http.Handle("/metrics", reg)
+package metrics
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are the default histogram buckets, in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Kind is the type of a metric.
+type Kind int
+
+const (
+	KindCounter Kind = iota
+	KindGauge
+	KindHistogram
+)
+
+// String returns the Prometheus type name of k.
+func (k Kind) String() string {
+	switch k {
+	case KindCounter:
+		return "counter"
+	case KindGauge:
+		return "gauge"
+	case KindHistogram:
+		return "histogram"
+	}
+
+	return "untyped"
+}
+
+// Registry holds a set of metrics. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{families: map[string]*family{}}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default returns the process-wide Registry used when none is given.
+func Default() *Registry { return defaultRegistry }
+
+// Counter registers a counter, or returns the one already registered under
+// name. It panics if name is registered with a different kind or labels.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r.register(name, help, KindCounter, labels, nil)}
+}
+
+// Gauge registers a gauge, or returns the one already registered under name.
+// It panics if name is registered with a different kind or labels.
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r.register(name, help, KindGauge, labels, nil)}
+}
+
+// Histogram registers a histogram with the given upper bucket bounds, or
+// returns the one already registered under name. Nil buckets use DefBuckets.
+// It panics if name is registered with a different kind or labels.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+
+	buckets = slices.Clone(buckets)
+	sort.Float64s(buckets)
+	return &Histogram{r.register(name, help, KindHistogram, labels, buckets)}
+}
+
+func (r *Registry) register(name, help string, kind Kind, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, ok := r.families[name]; ok {
+		if f.kind != kind || !slices.Equal(f.labels, labels) {
+			panic(fmt.Sprintf("metrics: %s already registered as a %s with labels %v", name, f.kind, f.labels))
+		}
+		return f
+	}
+
+	f := &family{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  slices.Clone(labels),
+		buckets: buckets,
+		series:  map[string]*series{},
+	}
+	r.families[name] = f
+	return f
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct{ f *family }
+
+// Inc adds one to the series with the given label values.
+func (c *Counter) Inc(labels ...string) { c.Add(1, labels...) }
+
+// Add adds v, which must not be negative, to the series with the given label values.
+func (c *Counter) Add(v float64, labels ...string) {
+	if v < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+
+	c.f.update(labels, func(s *series) { s.value += v })
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct{ f *family }
+
+// Set sets the series with the given label values to v.
+func (g *Gauge) Set(v float64, labels ...string) {
+	g.f.update(labels, func(s *series) { s.value = v })
+}
+
+// Add adds v to the series with the given label values.
+func (g *Gauge) Add(v float64, labels ...string) {
+	g.f.update(labels, func(s *series) { s.value += v })
+}
+
+// Inc adds one to the series with the given label values.
+func (g *Gauge) Inc(labels ...string) { g.Add(1, labels...) }
+
+// Dec subtracts one from the series with the given label values.
+func (g *Gauge) Dec(labels ...string) { g.Add(-1, labels...) }
+
+// Histogram counts observations in buckets.
+type Histogram struct{ f *family }
+
+// Observe records v in the series with the given label values.
+func (h *Histogram) Observe(v float64, labels ...string) {
+	h.f.update(labels, func(s *series) {
+		s.count++
+		s.sum += v
+		for i, bound := range h.f.buckets {
+			if v <= bound {
+				s.buckets[i]++
+			}
+		}
+	})
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    Kind
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labels  []string
+	value   float64
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+func (f *family) update(labels []string, fn func(*series)) {
+	if len(labels) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(labels)))
+	}
+
+	key := strings.Join(labels, "\xff")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: slices.Clone(labels)}
+		if f.kind == KindHistogram {
+			s.buckets = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+
+	fn(s)
+}
+
+// Bucket is a cumulative histogram bucket.
+type Bucket struct {
+	// UpperBound is the inclusive upper bound of the bucket
+	UpperBound float64
+
+	// Count is the number of observations less than or equal to UpperBound
+	Count uint64
+}
+
+// Sample is the state of one labeled series.
+type Sample struct {
+	// Labels maps label names to values
+	Labels map[string]string
+
+	// Value is the value of a counter or gauge
+	Value float64
+
+	// Count is the number of histogram observations
+	Count uint64
+
+	// Sum is the sum of histogram observations
+	Sum float64
+
+	// Buckets are the histogram buckets, ending with the +Inf bucket
+	Buckets []Bucket
+}
+
+// Family is the state of one metric.
+type Family struct {
+	Name    string
+	Help    string
+	Kind    Kind
+	Samples []Sample
+}
+
+// Snapshot maps metric names to their state at one point in time.
+type Snapshot map[string]Family
+
+// Snapshot returns a copy of every metric's current state, with samples
+// sorted by label values.
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.RLock()
+	families := slices.Collect(maps.Values(r.families))
+	r.mu.RUnlock()
+
+	out := make(Snapshot, len(families))
+	for _, f := range families {
+		out[f.name] = f.snapshot()
+	}
+
+	return out
+}
+
+func (f *family) snapshot() Family {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := Family{Name: f.name, Help: f.help, Kind: f.kind}
+	for _, key := range slices.Sorted(maps.Keys(f.series)) {
+		s := f.series[key]
+		sample := Sample{Labels: make(map[string]string, len(f.labels)), Value: s.value, Count: s.count, Sum: s.sum}
+		for i, name := range f.labels {
+			sample.Labels[name] = s.labels[i]
+		}
+
+		if f.kind == KindHistogram {
+			for i, bound := range f.buckets {
+				sample.Buckets = append(sample.Buckets, Bucket{UpperBound: bound, Count: s.buckets[i]})
+			}
+			sample.Buckets = append(sample.Buckets, Bucket{UpperBound: math.Inf(1), Count: s.count})
+		}
+
+		out.Samples = append(out.Samples, sample)
+	}
+
+	return out
+}
+
+// Sample returns the sample of metric name whose labels include every pair in labels.
+func (s Snapshot) Sample(name string, labels map[string]string) (Sample, bool) {
+	for _, sample := range s[name].Samples {
+		match := true
+		for k, v := range labels {
+			if sample.Labels[k] != v {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return sample, true
+		}
+	}
+
+	return Sample{}, false
+}
+
+// Value returns the value of the counter or gauge sample matched as by Sample,
+// or zero if there is none.
+func (s Snapshot) Value(name string, labels map[string]string) float64 {
+	sample, _ := s.Sample(name, labels)
+	return sample.Value
+}
@@ -0,0 +1,149 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/stretchr/testify/require"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/sdktest"
+)
+
+func TestRegistryText(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.Counter("requests_total", "Requests served.", "code")
+	requests.Inc("200")
+	requests.Add(2, "500")
+	require.Same(t, requests.f, reg.Counter("requests_total", "", "code").f)
+	require.Panics(t, func() { reg.Gauge("requests_total", "") })
+	require.Panics(t, func() { requests.Inc() })
+
+	reg.Gauge("queue_depth", "Queued \"tasks\"\nright now.").Set(3)
+	latency := reg.Histogram("latency_seconds", "", []float64{1, 0.1})
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(5)
+	reg.Counter("paths_total", "", "path").Inc("a\"b\\c")
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	require.Equal(t, strings.Join([]string{
+		`# TYPE latency_seconds histogram`,
+		`latency_seconds_bucket{le="0.1"} 1`,
+		`latency_seconds_bucket{le="1"} 2`,
+		`latency_seconds_bucket{le="+Inf"} 3`,
+		`latency_seconds_sum 5.55`,
+		`latency_seconds_count 3`,
+		`# TYPE paths_total counter`,
+		`paths_total{path="a\"b\\c"} 1`,
+		`# HELP queue_depth Queued "tasks"\nright now.`,
+		`# TYPE queue_depth gauge`,
+		`queue_depth 3`,
+		`# HELP requests_total Requests served.`,
+		`# TYPE requests_total counter`,
+		`requests_total{code="200"} 1`,
+		`requests_total{code="500"} 2`,
+	}, "\n")+"\n", rec.Body.String())
+
+	snap := reg.Snapshot()
+	require.Equal(t, 2.0, snap.Value("requests_total", map[string]string{"code": "500"}))
+	sample, ok := snap.Sample("latency_seconds", nil)
+	require.True(t, ok)
+	require.EqualValues(t, 3, sample.Count)
+}
+
+type pollAction struct{}
+
+func (a *pollAction) Metadata() sdk.ActionMetadata {
+	return sdk.ActionMetadata{ID: "sync", DisplayName: "Sync", Type: core.ActionTypeAction}
+}
+
+func (a *pollAction) Properties() *smartform.FormSchema { return &smartform.FormSchema{ID: "sync"} }
+
+func (a *pollAction) Auth() *core.AuthMetadata { return nil }
+
+func (a *pollAction) Perform(ctx sdkcontext.PerformContext) (core.JSON, error) {
+	switch ctx.Input()["mode"] {
+	case "retry":
+		return nil, ctx.Retry(time.Second, "busy")
+	case "timeout":
+		return nil, fmt.Errorf("sync: %w", context.DeadlineExceeded)
+	case "coded":
+		return nil, fmt.Errorf("poll: %w", &sdk.ActionError{Code: "rate_limited", Message: "slow down", Retryable: true})
+	}
+
+	return map[string]any{"ok": true}, nil
+}
+
+type feedTrigger struct{}
+
+func (t *feedTrigger) Metadata() sdk.TriggerMetadata {
+	return sdk.TriggerMetadata{ID: "feed", DisplayName: "Feed", Type: core.TriggerTypePolling}
+}
+
+func (t *feedTrigger) Props() *smartform.FormSchema { return &smartform.FormSchema{ID: "feed"} }
+
+func (t *feedTrigger) Auth() *core.AuthMetadata { return nil }
+
+func (t *feedTrigger) Start(ctx sdkcontext.LifecycleContext) error { return errors.New("no feed") }
+
+func (t *feedTrigger) Stop(ctx sdkcontext.LifecycleContext) error { return nil }
+
+func (t *feedTrigger) Execute(ctx sdkcontext.ExecuteContext) (core.JSON, error) {
+	return []map[string]any{{"id": 1}, {"id": 2}, {"id": 3}}, nil
+}
+
+func TestWrappers(t *testing.T) {
+	reg := NewRegistry()
+	action := WrapAction(&pollAction{}, WithRegistry(reg), WithIntegration("crm"))
+	for _, mode := range []string{"ok", "ok", "retry", "timeout", "coded"} {
+		_, _ = action.Perform(sdktest.NewPerformContext(sdktest.WithInput(core.JSONObject{"mode": mode})))
+	}
+
+	trigger := WrapTrigger(&feedTrigger{}, WithRegistry(reg), WithIntegration("crm"))
+	require.Error(t, trigger.Start(sdktest.NewLifecycleContext("feed_1", nil)))
+	_, err := trigger.Execute(sdktest.NewExecuteContext("feed_1", nil))
+	require.NoError(t, err)
+
+	NewExecution(reg).ObserveRateLimitWait("crm", 250*time.Millisecond)
+
+	snap := reg.Snapshot()
+	labels := map[string]string{"integration": "crm", "action": "sync"}
+	require.Equal(t, 5.0, snap.Value("wakflo_action_invocations_total", labels))
+	require.Equal(t, 1.0, snap.Value("wakflo_action_retries_total", labels))
+	require.Equal(t, 1.0, snap.Value("wakflo_action_failures_total", map[string]string{"code": CodeTimeout}))
+	require.Equal(t, 1.0, snap.Value("wakflo_action_failures_total", map[string]string{"code": "rate_limited"}))
+
+	duration, _ := snap.Sample("wakflo_action_duration_seconds", labels)
+	require.EqualValues(t, 5, duration.Count)
+
+	require.Equal(t, 1.0, snap.Value("wakflo_trigger_failures_total", map[string]string{"operation": "start", "code": CodeError}))
+	yield, _ := snap.Sample("wakflo_trigger_poll_yield_items", map[string]string{"trigger": "feed"})
+	require.Equal(t, 3.0, yield.Sum)
+
+	wait, _ := snap.Sample("wakflo_rate_limit_wait_seconds", nil)
+	require.Equal(t, 0.25, wait.Sum)
+}
@@ -0,0 +1,115 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+// WriteText writes every metric to w in the Prometheus text exposition format,
+// sorted by name.
+func (r *Registry) WriteText(w io.Writer) error {
+	snapshot := r.Snapshot()
+	bw := bufio.NewWriter(w)
+
+	for _, name := range slices.Sorted(maps.Keys(snapshot)) {
+		f := snapshot[name]
+		if f.Help != "" {
+			bw.WriteString("# HELP " + name + " " + helpEscaper.Replace(f.Help) + "\n")
+		}
+		bw.WriteString("# TYPE " + name + " " + f.Kind.String() + "\n")
+
+		for _, sample := range f.Samples {
+			if f.Kind != KindHistogram {
+				writeSample(bw, name, sample.Labels, "", "", sample.Value)
+				continue
+			}
+
+			for _, bucket := range sample.Buckets {
+				writeSample(bw, name+"_bucket", sample.Labels, "le", formatFloat(bucket.UpperBound), float64(bucket.Count))
+			}
+			writeSample(bw, name+"_sum", sample.Labels, "", "", sample.Sum)
+			writeSample(bw, name+"_count", sample.Labels, "", "", float64(sample.Count))
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeSample(w *bufio.Writer, name string, labels map[string]string, extraName, extraValue string, value float64) {
+	w.WriteString(name)
+
+	names := slices.Sorted(maps.Keys(labels))
+	if extraName != "" {
+		names = append(names, extraName)
+	}
+
+	if len(names) > 0 {
+		w.WriteByte('{')
+		for i, k := range names {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+
+			v := labels[k]
+			if k == extraName {
+				v = extraValue
+			}
+			w.WriteString(k + `="` + labelEscaper.Replace(v) + `"`)
+		}
+		w.WriteByte('}')
+	}
+
+	w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// ServeHTTP serves the registry in the Prometheus text exposition format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	if err := r.WriteText(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Handler returns an http.Handler serving the Default registry.
+func Handler() http.Handler { return defaultRegistry }
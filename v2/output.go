@@ -0,0 +1,127 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk
+
+import (
+	"errors"
+	"fmt"
+
+	sdkcore "github.com/wakflo/go-sdk/core"
+	"github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/form"
+)
+
+// ErrInvalidOutput is returned when an output does not match its declared schema.
+var ErrInvalidOutput = errors.New("output does not match its schema")
+
+// EffectiveOutputSchema returns the declared OutputSchema, or one inferred
+// from SampleOutput. It is nil when the action declares neither.
+func (m ActionMetadata) EffectiveOutputSchema() *sdkcore.AutoFormSchema {
+	return effectiveOutputSchema(m.OutputSchema, m.SampleOutput)
+}
+
+// EffectiveOutputSchema returns the declared OutputSchema, or one inferred
+// from SampleOutput. It is nil when the trigger declares neither.
+func (m TriggerMetadata) EffectiveOutputSchema() *sdkcore.AutoFormSchema {
+	return effectiveOutputSchema(m.OutputSchema, m.SampleOutput)
+}
+
+func effectiveOutputSchema(declared *sdkcore.AutoFormSchema, sample core.JSON) *sdkcore.AutoFormSchema {
+	if declared != nil {
+		return declared
+	}
+	if sample == nil {
+		return nil
+	}
+
+	return form.InferSchema(sample)
+}
+
+// ValidateOutput checks output against schema, returning an error that wraps
+// ErrInvalidOutput and the form.ValidationErrors. A nil schema accepts any output.
+func ValidateOutput(schema *sdkcore.AutoFormSchema, output core.JSON) error {
+	if schema == nil {
+		return nil
+	}
+
+	if err := form.Validate(schema, output); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+	}
+
+	return nil
+}
+
+// WithOutputValidation returns action with the output of every successful
+// Perform checked against its declared OutputSchema. Inferred schemas are not
+// enforced, since a sample cannot tell which variations are allowed. In
+// production, without a declared schema, or when action is already validated,
+// action is returned unchanged.
+func WithOutputValidation(action Action, env core.Environment) Action {
+	if _, ok := action.(*outputValidatingAction); ok || env.IsProduction() || action.Metadata().OutputSchema == nil {
+		return action
+	}
+
+	return &outputValidatingAction{Action: action}
+}
+
+type outputValidatingAction struct {
+	Action
+}
+
+// Unwrap returns the validated action.
+func (a *outputValidatingAction) Unwrap() Action { return a.Action }
+
+func (a *outputValidatingAction) Perform(ctx context.PerformContext) (core.JSON, error) {
+	output, err := a.Action.Perform(ctx)
+	if err != nil {
+		return output, err
+	}
+
+	if err := ValidateOutput(a.Metadata().OutputSchema, output); err != nil {
+		return output, fmt.Errorf("action %s: %w", a.Metadata().ID, err)
+	}
+
+	return output, nil
+}
+
+// WithTriggerOutputValidation is WithOutputValidation for a trigger's Execute.
+func WithTriggerOutputValidation(trigger Trigger, env core.Environment) Trigger {
+	if _, ok := trigger.(*outputValidatingTrigger); ok || env.IsProduction() || trigger.Metadata().OutputSchema == nil {
+		return trigger
+	}
+
+	return &outputValidatingTrigger{Trigger: trigger}
+}
+
+type outputValidatingTrigger struct {
+	Trigger
+}
+
+// Unwrap returns the validated trigger.
+func (t *outputValidatingTrigger) Unwrap() Trigger { return t.Trigger }
+
+func (t *outputValidatingTrigger) Execute(ctx context.ExecuteContext) (core.JSON, error) {
+	output, err := t.Trigger.Execute(ctx)
+	if err != nil {
+		return output, err
+	}
+
+	if err := ValidateOutput(t.Metadata().OutputSchema, output); err != nil {
+		return output, fmt.Errorf("trigger %s: %w", t.Metadata().ID, err)
+	}
+
+	return output, nil
+}
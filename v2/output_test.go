@@ -0,0 +1,35 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkcore "github.com/wakflo/go-sdk/core"
+)
+
+func TestEffectiveOutputSchema(t *testing.T) {
+	require.Nil(t, ActionMetadata{}.EffectiveOutputSchema())
+
+	inferred := ActionMetadata{SampleOutput: map[string]any{"id": 1}}.EffectiveOutputSchema()
+	require.Equal(t, sdkcore.Integer, inferred.Properties["id"].Type)
+
+	declared := &sdkcore.AutoFormSchema{Type: sdkcore.Array}
+	require.Same(t, declared, TriggerMetadata{OutputSchema: declared, SampleOutput: 1}.EffectiveOutputSchema())
+
+	require.NoError(t, ValidateOutput(nil, "anything"))
+	require.ErrorIs(t, ValidateOutput(declared, map[string]any{}), ErrInvalidOutput)
+}
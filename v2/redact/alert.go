@@ -0,0 +1,57 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"context"
+	"time"
+
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+)
+
+// Alerter wraps next so that the error, data and breadcrumbs of every alert are redacted
+// before next sees them.
+func (r *Redactor) Alerter(next sdkerrors.Alerter) sdkerrors.Alerter {
+	return &alerter{r: r, next: next}
+}
+
+type alerter struct {
+	r    *Redactor
+	next sdkerrors.Alerter
+}
+
+func (a *alerter) Alert(ctx context.Context, alert sdkerrors.Alert) error {
+	return a.next.Alert(ctx, a.r.Alert(alert))
+}
+
+func (a *alerter) Flush(timeout time.Duration) bool { return a.next.Flush(timeout) }
+
+// Alert returns a copy of alert with its error, data and breadcrumbs redacted.
+func (r *Redactor) Alert(alert sdkerrors.Alert) sdkerrors.Alert {
+	alert.Err = r.Error(alert.Err)
+	alert.Data = r.Map(alert.Data)
+
+	if alert.Breadcrumbs != nil {
+		breadcrumbs := make([]sdkerrors.Breadcrumb, len(alert.Breadcrumbs))
+		for i, b := range alert.Breadcrumbs {
+			b.Message = r.String(b.Message)
+			b.Fields = r.Map(b.Fields)
+			breadcrumbs[i] = b
+		}
+		alert.Breadcrumbs = breadcrumbs
+	}
+
+	return alert
+}
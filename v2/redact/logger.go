@@ -0,0 +1,146 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// Entry returns a copy of e with its message and fields redacted.
+func (r *Redactor) Entry(e core.LogEntry) core.LogEntry {
+	e.Message = r.String(e.Message)
+	e.Fields = r.Map(e.Fields)
+	return e
+}
+
+// Logger wraps l so that messages, key-value arguments, fields, prefixes and
+// errors are redacted before they reach it. Entries returned by GetLogs are
+// redacted again, so secrets learned after an entry was written are masked too.
+func (r *Redactor) Logger(l core.Logger) core.Logger {
+	if rl, ok := l.(*logger); ok && rl.r == r {
+		return l
+	}
+
+	return &logger{r: r, next: l}
+}
+
+// Sink wraps s so that every entry is redacted before it is written.
+func (r *Redactor) Sink(s core.LogSink) core.LogSink {
+	return core.LogSinkFunc(func(ctx context.Context, entry core.LogEntry) error {
+		return s.Write(ctx, r.Entry(entry))
+	})
+}
+
+type logger struct {
+	r    *Redactor
+	next core.Logger
+}
+
+var _ core.Logger = (*logger)(nil)
+
+func (l *logger) format(message string, a []any) string {
+	if len(a) > 0 {
+		message = fmt.Sprintf(message, a...)
+	}
+
+	return l.r.String(message)
+}
+
+func (l *logger) keyValues(keysAndValues []any) []any {
+	out := make([]any, len(keysAndValues))
+	for i, v := range keysAndValues {
+		if i%2 == 1 {
+			if key, ok := keysAndValues[i-1].(string); ok && IsSensitiveKey(key) {
+				if s, ok := v.(string); ok && s != "" {
+					out[i] = Mask
+					continue
+				}
+			}
+		}
+
+		out[i] = l.r.Value(v)
+	}
+
+	return out
+}
+
+func (l *logger) AddLog(level core.LogLevel, message string, a ...any) {
+	l.next.AddLog(level, l.format(message, a))
+}
+
+func (l *logger) SetPrefix(prefix string) {
+	l.next.SetPrefix(l.r.String(prefix))
+}
+
+func (l *logger) GetLogs() []core.LogEntry {
+	logs := l.next.GetLogs()
+	out := make([]core.LogEntry, len(logs))
+	for i, entry := range logs {
+		out[i] = l.r.Entry(entry)
+	}
+
+	return out
+}
+
+func (l *logger) ClearLogs() {
+	l.next.ClearLogs()
+}
+
+func (l *logger) Info(message string, keysAndValues ...interface{}) {
+	l.next.Info(l.r.String(message), l.keyValues(keysAndValues)...)
+}
+
+func (l *logger) Infof(message string, a ...any) {
+	l.next.Infof("%s", l.format(message, a))
+}
+
+func (l *logger) Warn(message string, keysAndValues ...interface{}) {
+	l.next.Warn(l.r.String(message), l.keyValues(keysAndValues)...)
+}
+
+func (l *logger) Warnf(message string, a ...any) {
+	l.next.Warnf("%s", l.format(message, a))
+}
+
+func (l *logger) Error(message string, keysAndValues ...interface{}) {
+	l.next.Error(l.r.String(message), l.keyValues(keysAndValues)...)
+}
+
+func (l *logger) Errorf(err error, message string, a ...any) {
+	l.next.Errorf(l.r.Error(err), "%s", l.format(message, a))
+}
+
+func (l *logger) Debug(message string, keysAndValues ...interface{}) {
+	l.next.Debug(l.r.String(message), l.keyValues(keysAndValues)...)
+}
+
+func (l *logger) Debugf(message string, a ...any) {
+	l.next.Debugf("%s", l.format(message, a))
+}
+
+func (l *logger) WithField(key string, value interface{}) core.Logger {
+	return l.WithFields(map[string]interface{}{key: value})
+}
+
+func (l *logger) WithFields(fields map[string]interface{}) core.Logger {
+	return &logger{r: l.r, next: l.next.WithFields(l.r.Map(fields))}
+}
+
+func (l *logger) Clone() core.Logger {
+	return &logger{r: l.r, next: l.next.Clone()}
+}
@@ -0,0 +1,107 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SecretGroup names the capture group a pattern masks. Patterns without it
+// mask the whole match.
+const SecretGroup = "secret"
+
+// Common token formats.
+var (
+	// BearerPattern matches the credential of a Bearer authorization value. The
+	// credential must contain a digit so that prose such as "bearer authentication" is kept.
+	BearerPattern = regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z\-._~+/]*[0-9][A-Za-z0-9\-._~+/]*=*)`)
+
+	// BasicPattern matches the credential of a Basic Authorization header
+	BasicPattern = regexp.MustCompile(`(?i)\bauthorization["']?\s*[:=]\s*["']?basic\s+(?P<secret>[A-Za-z0-9+/]+=*)`)
+
+	// JWTPattern matches JSON Web Tokens
+	JWTPattern = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+
+	// GitHubPattern matches GitHub personal, OAuth, app and fine-grained tokens
+	GitHubPattern = regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)
+
+	// SlackPattern matches Slack bot, user and app tokens
+	SlackPattern = regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)
+
+	// AWSAccessKeyPattern matches AWS access key IDs
+	AWSAccessKeyPattern = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+
+	// StripePattern matches Stripe secret and restricted keys
+	StripePattern = regexp.MustCompile(`\b[rs]k_(?:live|test)_[0-9A-Za-z]{16,}\b`)
+
+	// GoogleAPIKeyPattern matches Google API keys
+	GoogleAPIKeyPattern = regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)
+
+	// PrivateKeyPattern matches PEM encoded private keys
+	PrivateKeyPattern = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+
+	// URLPasswordPattern matches the password in the user info of a URL
+	URLPasswordPattern = regexp.MustCompile(`://[^/\s:@]+:(?P<secret>[^/\s@]+)@`)
+)
+
+// DefaultPatterns are the patterns every Redactor masks unless created with WithoutDefaultPatterns.
+var DefaultPatterns = []*regexp.Regexp{
+	PrivateKeyPattern,
+	JWTPattern,
+	BearerPattern,
+	BasicPattern,
+	GitHubPattern,
+	SlackPattern,
+	AWSAccessKeyPattern,
+	StripePattern,
+	GoogleAPIKeyPattern,
+	URLPasswordPattern,
+}
+
+func isDefaultPattern(p *regexp.Regexp) bool {
+	return slices.Contains(DefaultPatterns, p)
+}
+
+// replacePattern masks each match of p in s, or only its SecretGroup when p has one.
+func replacePattern(p *regexp.Regexp, s string) string {
+	group := p.SubexpIndex(SecretGroup)
+
+	matches := p.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if group > 0 {
+			start, end = m[2*group], m[2*group+1]
+		}
+
+		if start < 0 || start < last || strings.Contains(s[start:end], Mask) {
+			continue
+		}
+
+		b.WriteString(s[last:start])
+		b.WriteString(Mask)
+		last = end
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
@@ -0,0 +1,361 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redact masks the secrets of a run in logs, errors and step output.
+//
+// A Redactor learns exact secret values from the run's AuthContext and from
+// input fields whose schema marks them secret, and also matches common token
+// formats with patterns, so credentials are masked even when they reach a log
+// line or output by an unexpected route.
+package redact
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+)
+
+// Mask replaces every redacted value.
+const Mask = "[REDACTED]"
+
+// MinSecretLength is the shortest value treated as a secret. Shorter values
+// would mask unrelated text.
+const MinSecretLength = 4
+
+// sensitiveKeys are map keys whose string values are always masked, compared
+// after lowercasing and removing '_' and '-'.
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"passwd":        true,
+	"secret":        true,
+	"clientsecret":  true,
+	"accesstoken":   true,
+	"refreshtoken":  true,
+	"idtoken":       true,
+	"apikey":        true,
+	"authorization": true,
+	"privatekey":    true,
+}
+
+// IsSensitiveKey reports whether values under key are masked regardless of content.
+func IsSensitiveKey(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	return sensitiveKeys[key]
+}
+
+// Option configures a Redactor.
+type Option func(*Redactor)
+
+// WithSecrets masks the given values.
+func WithSecrets(secrets ...string) Option {
+	return func(r *Redactor) { r.addSecrets(secrets) }
+}
+
+// WithAuth masks every credential held by auth.
+func WithAuth(auth *sdkcontext.AuthContext) Option {
+	return WithSecrets(AuthSecrets(auth)...)
+}
+
+// WithPatterns masks matches of the given patterns in addition to DefaultPatterns.
+func WithPatterns(patterns ...*regexp.Regexp) Option {
+	return func(r *Redactor) { r.patterns = append(r.patterns, patterns...) }
+}
+
+// WithoutDefaultPatterns disables DefaultPatterns.
+func WithoutDefaultPatterns() Option {
+	return func(r *Redactor) { r.patterns = slices.DeleteFunc(r.patterns, isDefaultPattern) }
+}
+
+// Redactor masks known secret values and token patterns. It is safe for
+// concurrent use, and secrets may be added while it is in use.
+type Redactor struct {
+	mu       sync.RWMutex
+	secrets  []string
+	replacer *strings.Replacer
+	patterns []*regexp.Regexp
+}
+
+// New creates a Redactor that masks DefaultPatterns and the secrets given by opts.
+func New(opts ...Option) *Redactor {
+	r := &Redactor{patterns: slices.Clone(DefaultPatterns)}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// AddSecrets masks the given values from now on. Values shorter than
+// MinSecretLength are ignored.
+func (r *Redactor) AddSecrets(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.addSecrets(secrets)
+}
+
+// AddAuth masks every credential held by auth.
+func (r *Redactor) AddAuth(auth *sdkcontext.AuthContext) {
+	r.AddSecrets(AuthSecrets(auth)...)
+}
+
+func (r *Redactor) addSecrets(secrets []string) {
+	changed := false
+	for _, secret := range secrets {
+		if len(secret) < MinSecretLength || slices.Contains(r.secrets, secret) {
+			continue
+		}
+
+		r.secrets = append(r.secrets, secret)
+		changed = true
+	}
+
+	if !changed {
+		return
+	}
+
+	// Longest first, so a secret containing another is masked whole.
+	slices.SortFunc(r.secrets, func(a, b string) int { return len(b) - len(a) })
+
+	pairs := make([]string, 0, len(r.secrets)*2)
+	for _, secret := range r.secrets {
+		pairs = append(pairs, secret, Mask)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// String masks secrets and pattern matches in s.
+func (r *Redactor) String(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+
+	r.mu.RLock()
+	replacer, patterns := r.replacer, r.patterns
+	r.mu.RUnlock()
+
+	if replacer != nil {
+		s = replacer.Replace(s)
+	}
+
+	for _, pattern := range patterns {
+		s = replacePattern(pattern, s)
+	}
+
+	return s
+}
+
+// Value returns a copy of v with every string masked, and with string values
+// under sensitive keys replaced entirely. Maps, slices and strings are walked
+// directly. Other values are inspected through their JSON form and returned
+// unchanged unless that form holds something to mask, in which case the
+// redacted JSON form is returned instead.
+func (r *Redactor) Value(v any) any {
+	if r == nil {
+		return v
+	}
+
+	switch v := v.(type) {
+	case nil, bool, int, int32, int64, uint, uint32, uint64, float32, float64, json.Number:
+		return v
+	case string:
+		return r.String(v)
+	case []byte:
+		return []byte(r.String(string(v)))
+	case map[string]any:
+		return r.Map(v)
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for key, value := range v {
+			if IsSensitiveKey(key) && value != "" {
+				out[key] = Mask
+			} else {
+				out[key] = r.String(value)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = r.Value(item)
+		}
+		return out
+	case []string:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = r.String(item)
+		}
+		return out
+	case []map[string]any:
+		out := make([]map[string]any, len(v))
+		for i, item := range v {
+			out[i] = r.Map(item)
+		}
+		return out
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return v
+	}
+
+	redacted := r.Value(generic)
+	if reflect.DeepEqual(generic, redacted) {
+		return v
+	}
+
+	return redacted
+}
+
+// Map returns a copy of m with its values redacted.
+func (r *Redactor) Map(m map[string]any) map[string]any {
+	if r == nil || m == nil {
+		return m
+	}
+
+	out := make(map[string]any, len(m))
+	for key, value := range m {
+		if s, ok := value.(string); ok && s != "" && IsSensitiveKey(key) {
+			out[key] = Mask
+			continue
+		}
+
+		out[key] = r.Value(value)
+	}
+
+	return out
+}
+
+// Error returns err unchanged if neither its message nor any ActionError in
+// its chain holds a secret. Otherwise it returns an error with the masked
+// message that unwraps only to a redacted copy of that ActionError, so the raw
+// error cannot be reached with errors.As. errors.Is still matches the raw
+// chain, since comparing against sentinels exposes nothing.
+func (r *Redactor) Error(err error) error {
+	if r == nil || err == nil {
+		return err
+	}
+
+	msg := err.Error()
+	masked := r.String(msg)
+
+	var ae, cause *sdk.ActionError
+	if errors.As(err, &ae) {
+		cause = r.ActionError(ae)
+		if masked == msg && reflect.DeepEqual(ae, cause) {
+			return err
+		}
+	} else if masked == msg {
+		return err
+	}
+
+	out := &redactedError{msg: masked, raw: err, categorized: *sdkerrors.As(err)}
+	out.categorized.Message = r.String(out.categorized.Message)
+	out.categorized.Details = r.Map(out.categorized.Details)
+	out.categorized.Err = nil
+	if cause != nil {
+		out.cause = cause
+	}
+
+	return out
+}
+
+// ActionError returns a copy of e with its message and details redacted.
+func (r *Redactor) ActionError(e *sdk.ActionError) *sdk.ActionError {
+	if r == nil || e == nil {
+		return e
+	}
+
+	out := *e
+	out.Message = r.String(e.Message)
+	out.Details = r.Map(e.Details)
+	return &out
+}
+
+type redactedError struct {
+	msg   string
+	raw   error
+	cause error
+
+	// categorized is the redacted taxonomy form of raw
+	categorized sdkerrors.Error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.cause }
+
+func (e *redactedError) Is(target error) bool { return errors.Is(e.raw, target) }
+
+// Categorized keeps the category, code and retry hint of the raw error.
+func (e *redactedError) Categorized() *sdkerrors.Error {
+	c := e.categorized
+	return &c
+}
+
+// AuthSecrets returns the credential values held by an auth context.
+func AuthSecrets(auth *sdkcontext.AuthContext) []string {
+	if auth == nil {
+		return nil
+	}
+
+	secrets := []string{auth.AccessToken, auth.Password, auth.Secret, auth.Key}
+	if auth.Token != nil {
+		secrets = append(secrets, auth.Token.AccessToken, auth.Token.RefreshToken)
+	}
+
+	for _, v := range auth.Extra {
+		secrets = append(secrets, v)
+	}
+
+	return secrets
+}
+
+// secretValues collects the strings held by an input value.
+func secretValues(v any) []string {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case map[string]any:
+		var out []string
+		for _, item := range v {
+			out = append(out, secretValues(item)...)
+		}
+		return out
+	case []any:
+		var out []string
+		for _, item := range v {
+			out = append(out, secretValues(item)...)
+		}
+		return out
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// secretFieldTypes are field types whose values are always secret.
+var secretFieldTypes = map[string]bool{
+	"password": true,
+	"secret":   true,
+}
+
+// secretFlags are boolean field attributes that mark a field secret.
+var secretFlags = []string{"secret", "sensitive", "isSecret", "mask", "masked"}
+
+// SecretFields returns the dot-separated paths of the fields in schema that
+// are marked secret, either by a password or secret field type or by a true
+// secret, sensitive or mask attribute on the field or its properties.
+func SecretFields(schema *smartform.FormSchema) []string {
+	if schema == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil
+	}
+
+	return secretFieldsJSON(data)
+}
+
+// secretFieldsJSON is SecretFields on the JSON form of a schema.
+func secretFieldsJSON(data []byte) []string {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	var paths []string
+	collectSecretFields(doc["fields"], "", &paths)
+	return paths
+}
+
+func collectSecretFields(fields any, prefix string, paths *[]string) {
+	list, ok := fields.([]any)
+	if !ok {
+		return
+	}
+
+	for _, item := range list {
+		field, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		id, _ := field["id"].(string)
+		if id == "" {
+			id, _ = field["name"].(string)
+		}
+		if id == "" {
+			continue
+		}
+
+		path := id
+		if prefix != "" {
+			path = prefix + "." + id
+		}
+
+		if isSecretField(field) {
+			*paths = append(*paths, path)
+			continue
+		}
+
+		for _, key := range []string{"nested", "fields", "children"} {
+			collectSecretFields(field[key], path, paths)
+		}
+	}
+}
+
+func isSecretField(field map[string]any) bool {
+	if typ, ok := field["type"].(string); ok && secretFieldTypes[strings.ToLower(typ)] {
+		return true
+	}
+
+	props, _ := field["properties"].(map[string]any)
+	for _, flag := range secretFlags {
+		if v, ok := field[flag].(bool); ok && v {
+			return true
+		}
+		if v, ok := props[flag].(bool); ok && v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddSchema masks the values in input of the fields schema marks secret.
+func (r *Redactor) AddSchema(schema *smartform.FormSchema, input core.JSONObject) {
+	for _, path := range SecretFields(schema) {
+		if value, ok := lookup(input, path); ok {
+			r.AddSecrets(secretValues(value)...)
+		}
+	}
+}
+
+// lookup resolves a dot-separated path in nested maps.
+func lookup(input map[string]any, path string) (any, bool) {
+	var current any = input
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
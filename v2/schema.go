@@ -0,0 +1,75 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk
+
+import (
+	"reflect"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/form"
+)
+
+// SchemaFromStruct derives a form schema from the struct tags of T, so the
+// struct an action decodes its input into with InputToType also defines its
+// form. See form.FromStruct for the supported tags.
+func SchemaFromStruct[T any]() (*smartform.FormSchema, error) {
+	schema, err := form.FromStruct(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.Smartform()
+}
+
+// MustSchemaFromStruct is like SchemaFromStruct but panics if T cannot be
+// described, for use in Properties methods.
+func MustSchemaFromStruct[T any]() *smartform.FormSchema {
+	schema, err := SchemaFromStruct[T]()
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
+
+// ValidateInput checks a step input against the form schema that describes
+// it. Failures are returned as form.ValidationErrors addressed by JSON
+// pointer; see form.Validate for the enforced keywords. A nil input, as given
+// to actions without inputs, is checked as an empty object.
+func ValidateInput(schema *smartform.FormSchema, input core.JSONObject) error {
+	view, err := form.FromSmartform(schema)
+	if err != nil || view == nil {
+		return err
+	}
+
+	if input == nil {
+		input = core.JSONObject{}
+	}
+
+	return view.Validate(input)
+}
+
+// StripHiddenInput returns input without the values of fields the form schema
+// hides for it, so stale values from collapsed branches are not passed to
+// Perform. See form.StripHiddenValues.
+func StripHiddenInput(schema *smartform.FormSchema, input core.JSONObject) (core.JSONObject, error) {
+	view, err := form.FromSmartform(schema)
+	if err != nil || view == nil {
+		return input, err
+	}
+
+	return view.StripHiddenValues(input), nil
+}
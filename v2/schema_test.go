@@ -0,0 +1,65 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdk
+
+import (
+	"testing"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/stretchr/testify/require"
+	"github.com/wakflo/go-sdk/v2/form"
+)
+
+type greetInput struct {
+	Name     string `json:"name" form:"required"`
+	Greeting string `json:"greeting" form:"default=Hello"`
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	schema := MustSchemaFromStruct[greetInput]()
+	require.Equal(t, "greet_input", schema.ID)
+	require.Len(t, schema.Fields, 2)
+	require.Equal(t, "name", schema.Fields[0].ID)
+
+	_, err := SchemaFromStruct[string]()
+	require.Error(t, err)
+	require.Panics(t, func() { MustSchemaFromStruct[int]() })
+}
+
+func TestValidateInput(t *testing.T) {
+	schema := MustSchemaFromStruct[greetInput]()
+	require.NoError(t, ValidateInput(schema, map[string]any{"name": "Ada"}))
+
+	err := ValidateInput(schema, map[string]any{"greeting": 1})
+	var errs form.ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	require.Equal(t, "/greeting", errs[0].Pointer)
+	require.Equal(t, "/name", errs[1].Pointer)
+
+	require.NoError(t, ValidateInput(&smartform.FormSchema{ID: "empty"}, nil))
+	require.ErrorAs(t, ValidateInput(schema, nil), &errs)
+	require.Len(t, errs, 1)
+	require.Equal(t, "/name", errs[0].Pointer)
+}
+
+func TestStripHiddenInput(t *testing.T) {
+	schema := MustSchemaFromStruct[greetInput]()
+	schema.Fields[1].Properties = map[string]interface{}{"hidden": true}
+
+	input, err := StripHiddenInput(schema, map[string]any{"name": "Ada", "greeting": "Hi"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Ada"}, input)
+}
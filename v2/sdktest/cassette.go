@@ -0,0 +1,60 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdktest
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakflo/go-sdk/v2/cassette"
+)
+
+// CassetteDir is where UseCassette stores cassettes, relative to the package under test.
+var CassetteDir = filepath.Join("testdata", "cassettes")
+
+// UseCassette routes requests sent through http.DefaultTransport, and so
+// http.DefaultClient, through the cassette CassetteDir/<name>.json until the
+// test ends. The mode is read from $WAKFLO_CASSETTE_MODE and defaults to
+// replay, so tests run offline unless explicitly re-recorded with
+// WAKFLO_CASSETTE_MODE=record.
+//
+// Clients built by the integration itself should be wrapped with the returned
+// recorder's WrapClient, or obtained from cassette.WrapAuth. Tests using
+// UseCassette must not run in parallel.
+func UseCassette(t testing.TB, name string, opts ...cassette.Option) *cassette.Recorder {
+	t.Helper()
+
+	original := http.DefaultTransport
+	opts = append([]cassette.Option{
+		cassette.WithMode(cassette.ModeFromEnv(cassette.ModeReplay)),
+		cassette.WithTransport(original),
+	}, opts...)
+
+	rec, err := cassette.New(filepath.Join(CassetteDir, name+".json"), opts...)
+	if err != nil {
+		t.Fatalf("sdktest: open cassette %s: %s", name, err)
+	}
+
+	http.DefaultTransport = rec
+	t.Cleanup(func() {
+		http.DefaultTransport = original
+		if err := rec.Stop(); err != nil {
+			t.Errorf("sdktest: save cassette %s: %s", name, err)
+		}
+	})
+
+	return rec
+}
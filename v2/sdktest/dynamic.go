@@ -0,0 +1,81 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdktest
+
+import (
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// DynamicFieldContext is an in-memory context.DynamicFieldContext.
+type DynamicFieldContext struct {
+	baseContext
+
+	operationID string
+	fieldName   string
+}
+
+var _ sdkcontext.DynamicFieldContext = (*DynamicFieldContext)(nil)
+
+// NewDynamicFieldContext creates a DynamicFieldContext for a field of the given
+// action or trigger. The filter defaults to offset 0 and a limit of
+// core.DefaultDynamicOptionsLimit.
+func NewDynamicFieldContext(operationID, fieldName string, opts ...Option) *DynamicFieldContext {
+	c := &DynamicFieldContext{
+		baseContext: newBaseContext(opts),
+		operationID: operationID,
+		fieldName:   fieldName,
+	}
+
+	if c.opts.Filter == nil {
+		c.opts.Filter = &core.DynamicOptionsFilterParams{}
+	}
+
+	if c.opts.Filter.Offset < 0 {
+		c.opts.Filter.Offset = 0
+	}
+
+	if c.opts.Filter.Limit <= 0 {
+		c.opts.Filter.Limit = core.DefaultDynamicOptionsLimit
+	}
+
+	return c
+}
+
+func (c *DynamicFieldContext) Respond(data any, totalItems int) (*core.DynamicOptionsResponse, error) {
+	filter := c.opts.Filter
+
+	return &core.DynamicOptionsResponse{
+		Metadata: core.OffsetPaginationMeta{
+			Offset:     filter.Offset,
+			Limit:      filter.Limit,
+			TotalItems: totalItems,
+			HasMore:    (filter.Offset + filter.Limit) < totalItems,
+		},
+		Items: data,
+	}, nil
+}
+
+func (c *DynamicFieldContext) RespondJSON(data any, totalItems int) (core.JSON, error) {
+	return c.Respond(data, totalItems)
+}
+
+func (c *DynamicFieldContext) FieldName() string { return c.fieldName }
+
+func (c *DynamicFieldContext) OperationID() string { return c.operationID }
+
+func (c *DynamicFieldContext) StepID() string { return c.opts.StepID }
+
+func (c *DynamicFieldContext) Filter() *core.DynamicOptionsFilterParams { return c.opts.Filter }
@@ -0,0 +1,216 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdktest
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rs/xid"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// Event records an event emitted by a trigger.
+type Event struct {
+	Type    string    `json:"type,omitempty"`
+	Payload core.JSON `json:"payload"`
+}
+
+// ExecuteContext is an in-memory context.ExecuteContext.
+type ExecuteContext struct {
+	baseContext
+
+	triggerID string
+	lastRun   *time.Time
+	state     core.StepRunStatus
+	output    core.JSON
+	hasOutput bool
+	events    []Event
+	pauses    []string
+}
+
+var _ sdkcontext.ExecuteContext = (*ExecuteContext)(nil)
+
+// NewExecuteContext creates an ExecuteContext for the given trigger.
+func NewExecuteContext(triggerID string, lastRun *time.Time, opts ...Option) *ExecuteContext {
+	return &ExecuteContext{
+		baseContext: newBaseContext(opts),
+		triggerID:   triggerID,
+		lastRun:     lastRun,
+		state:       core.StepRunStatusRunning,
+	}
+}
+
+func (c *ExecuteContext) TriggerID() string { return c.triggerID }
+
+func (c *ExecuteContext) RunID() xid.ID { return c.opts.RunID }
+
+func (c *ExecuteContext) LastRun() *time.Time { return c.lastRun }
+
+func (c *ExecuteContext) Environment() core.Environment { return c.opts.Environment }
+
+func (c *ExecuteContext) SetInput(input core.JSONObject) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.opts.Input = input
+	return nil
+}
+
+func (c *ExecuteContext) EmitEvent(eventType string, payload core.JSON) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.events = append(c.events, Event{Type: eventType, Payload: payload})
+	return nil
+}
+
+func (c *ExecuteContext) SetOutput(output core.JSON) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.output = c.opts.Redactor.Value(output)
+	c.hasOutput = true
+	return nil
+}
+
+func (c *ExecuteContext) PauseExecution(reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pauses = append(c.pauses, reason)
+	c.state = core.StepRunStatusPaused
+	return nil
+}
+
+func (c *ExecuteContext) ExecutionState() core.StepRunStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+// Output returns the value passed to SetOutput and whether it was called.
+func (c *ExecuteContext) Output() (core.JSON, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.output, c.hasOutput
+}
+
+// Events returns every event emitted during execution.
+func (c *ExecuteContext) Events() []Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Event(nil), c.events...)
+}
+
+// Pauses returns the reasons passed to PauseExecution.
+func (c *ExecuteContext) Pauses() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.pauses...)
+}
+
+// LifecycleContext is an in-memory context.LifecycleContext.
+type LifecycleContext struct {
+	baseContext
+
+	triggerID string
+	config    map[string]interface{}
+	criteria  *core.TriggerCriteria
+	lastRun   *time.Time
+	state     map[string]interface{}
+	events    []Event
+}
+
+var _ sdkcontext.LifecycleContext = (*LifecycleContext)(nil)
+
+// NewLifecycleContext creates a LifecycleContext for the given trigger.
+func NewLifecycleContext(triggerID string, criteria *core.TriggerCriteria, opts ...Option) *LifecycleContext {
+	return &LifecycleContext{
+		baseContext: newBaseContext(opts),
+		triggerID:   triggerID,
+		config:      map[string]interface{}{},
+		criteria:    criteria,
+	}
+}
+
+func (c *LifecycleContext) TriggerID() string { return c.triggerID }
+
+func (c *LifecycleContext) Config() map[string]interface{} { return c.config }
+
+func (c *LifecycleContext) Input() map[string]interface{} { return c.opts.Input }
+
+func (c *LifecycleContext) GetLastRunTime() (*time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lastRun, nil
+}
+
+func (c *LifecycleContext) SetLastRunTime(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastRun = &t
+	return nil
+}
+
+func (c *LifecycleContext) GetState() (map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state, nil
+}
+
+func (c *LifecycleContext) SetState(state map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.state = state
+	return nil
+}
+
+func (c *LifecycleContext) TriggerCriteria() (*core.TriggerCriteria, error) {
+	if c.criteria == nil {
+		return nil, errors.New("no trigger criteria configured")
+	}
+
+	return c.criteria, nil
+}
+
+func (c *LifecycleContext) EmitEvent(payload core.JSON) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.events = append(c.events, Event{Payload: payload})
+	return nil
+}
+
+func (c *LifecycleContext) StoreMetadata(key string, value interface{}) error {
+	return c.SetMetadata(key, value)
+}
+
+// Events returns every event emitted during the lifecycle calls.
+func (c *LifecycleContext) Events() []Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Event(nil), c.events...)
+}
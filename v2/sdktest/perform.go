@@ -0,0 +1,164 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdktest
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rs/xid"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// PauseCall records a call to PauseExecution.
+type PauseCall struct {
+	Reason      string     `json:"reason"`
+	ResumeAfter *time.Time `json:"resumeAfter,omitempty"`
+}
+
+// RetryCall records a call to Retry.
+type RetryCall struct {
+	After  time.Duration `json:"after"`
+	Reason string        `json:"reason"`
+}
+
+// PerformContext is an in-memory context.PerformContext.
+type PerformContext struct {
+	baseContext
+
+	stepRunID xid.ID
+	state     core.StepRunStatus
+	output    core.JSON
+	hasOutput bool
+	pauses    []PauseCall
+	retries   []RetryCall
+	failures  []string
+}
+
+var _ sdkcontext.PerformContext = (*PerformContext)(nil)
+
+// NewPerformContext creates a PerformContext configured by opts.
+func NewPerformContext(opts ...Option) *PerformContext {
+	return &PerformContext{
+		baseContext: newBaseContext(opts),
+		stepRunID:   xid.New(),
+		state:       core.StepRunStatusRunning,
+	}
+}
+
+func (c *PerformContext) StepID() string { return c.opts.StepID }
+
+func (c *PerformContext) RunID() xid.ID { return c.opts.RunID }
+
+func (c *PerformContext) StepRunID() xid.ID { return c.stepRunID }
+
+func (c *PerformContext) PreviousStepOutput() (core.JSONObject, error) {
+	if c.opts.PreviousOutput == nil {
+		return nil, errors.New("no previous step output")
+	}
+
+	return c.opts.PreviousOutput, nil
+}
+
+func (c *PerformContext) SetOutput(output core.JSON) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.output = c.opts.Redactor.Value(output)
+	c.hasOutput = true
+	return nil
+}
+
+func (c *PerformContext) PauseExecution(reason string, resumeAfter *time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pauses = append(c.pauses, PauseCall{Reason: reason, ResumeAfter: resumeAfter})
+	c.state = core.StepRunStatusPaused
+	return nil
+}
+
+func (c *PerformContext) ExecutionState() core.StepRunStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+func (c *PerformContext) Retry(after time.Duration, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retries = append(c.retries, RetryCall{After: after, Reason: reason})
+	c.state = core.StepRunStatusRetrying
+	return nil
+}
+
+func (c *PerformContext) MarkFailed(reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures = append(c.failures, reason)
+	c.state = core.StepRunStatusFailed
+	return nil
+}
+
+func (c *PerformContext) WorkflowContextData() (map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return core.MergeMaps(c.opts.WorkflowContext), nil
+}
+
+func (c *PerformContext) UpdateWorkflowContext(data map[string]interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.opts.WorkflowContext = core.MergeMaps(c.opts.WorkflowContext, data)
+	return nil
+}
+
+// Output returns the value passed to SetOutput and whether it was called.
+func (c *PerformContext) Output() (core.JSON, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.output, c.hasOutput
+}
+
+// Pauses returns every PauseExecution call.
+func (c *PerformContext) Pauses() []PauseCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]PauseCall(nil), c.pauses...)
+}
+
+// Retries returns every Retry call.
+func (c *PerformContext) Retries() []RetryCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]RetryCall(nil), c.retries...)
+}
+
+// Failures returns the reasons passed to MarkFailed.
+func (c *PerformContext) Failures() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.failures...)
+}
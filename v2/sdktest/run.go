@@ -0,0 +1,244 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdktest
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/wakflo/go-sdk/v2"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// ActionResult is the outcome of RunAction.
+type ActionResult struct {
+	// Output is the value returned by Perform
+	Output core.JSON `json:"output"`
+
+	// Error is the error returned by Perform, if any
+	Error string `json:"error,omitempty"`
+
+	// ActionError is the redacted structured form of Error
+	ActionError *sdk.ActionError `json:"actionError,omitempty"`
+
+	// State is the execution state after Perform returned
+	State core.StepRunStatus `json:"state"`
+
+	// Logs are the entries written to the context logger
+	Logs []core.LogEntry `json:"logs"`
+
+	// Pauses lists the PauseExecution calls
+	Pauses []PauseCall `json:"pauses,omitempty"`
+
+	// Retries lists the Retry calls
+	Retries []RetryCall `json:"retries,omitempty"`
+
+	// Failures lists the reasons passed to MarkFailed
+	Failures []string `json:"failures,omitempty"`
+
+	// Metadata holds the values stored with SetMetadata
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Duration is how long Perform took
+	Duration time.Duration `json:"duration"`
+
+	err error
+}
+
+// Err returns the error returned by Perform.
+func (r *ActionResult) Err() error { return r.err }
+
+// RunAction performs action with a PerformContext configured by opts and
+// records everything the action did. Output, logs and errors are passed
+// through the context's redactor. Values of fields the schema hides are
+// removed from the input before Perform, and outside production the output is
+// checked against the action's declared OutputSchema.
+func RunAction(action sdk.Action, opts ...Option) *ActionResult {
+	ctx := NewPerformContext(opts...)
+	action = sdk.WithOutputValidation(action, ctx.opts.Environment)
+	if ctx.opts.Schema == nil {
+		ctx.opts.Schema = action.Properties()
+		ctx.opts.Redactor.AddSchema(ctx.opts.Schema, ctx.opts.Input)
+	}
+	redactor := ctx.opts.Redactor
+
+	start := time.Now()
+	input, err := sdk.StripHiddenInput(ctx.opts.Schema, ctx.opts.Input)
+	var output core.JSON
+	if err == nil {
+		ctx.opts.Input = input
+		output, err = action.Perform(ctx)
+	}
+	err = redactor.Error(err)
+
+	result := &ActionResult{
+		Output:   redactor.Value(output),
+		State:    ctx.ExecutionState(),
+		Logs:     ctx.Logger().GetLogs(),
+		Pauses:   ctx.Pauses(),
+		Retries:  ctx.Retries(),
+		Failures: ctx.Failures(),
+		Metadata: ctx.Metadata(),
+		Duration: time.Since(start),
+		err:      err,
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+		result.ActionError = redactor.ActionError(sdk.NewActionError(err))
+		if result.State == core.StepRunStatusRunning {
+			result.State = core.StepRunStatusFailed
+		}
+	} else if result.State == core.StepRunStatusRunning {
+		result.State = core.StepRunStatusCompleted
+	}
+
+	return result
+}
+
+// TriggerResult is the outcome of RunTrigger.
+type TriggerResult struct {
+	// Output is the value returned by Execute
+	Output core.JSON `json:"output"`
+
+	// Error is the first error returned by Start, Execute or Stop
+	Error string `json:"error,omitempty"`
+
+	// Phase is the lifecycle call that returned Error, or "input" if the input
+	// could not be prepared
+	Phase string `json:"phase,omitempty"`
+
+	// State is the execution state after Execute returned
+	State core.StepRunStatus `json:"state"`
+
+	// Events lists the events emitted during Start, Execute and Stop
+	Events []Event `json:"events,omitempty"`
+
+	// Logs are the entries written to the context loggers
+	Logs []core.LogEntry `json:"logs"`
+
+	// Pauses lists the reasons passed to PauseExecution
+	Pauses []string `json:"pauses,omitempty"`
+
+	// Metadata holds the values stored during Start, Execute and Stop
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	err error
+}
+
+// Err returns the first error returned by Start, Execute or Stop.
+func (r *TriggerResult) Err() error { return r.err }
+
+// RunTrigger calls Start, Execute and Stop on trigger in that order. Stop is
+// called even when Execute fails; Execute is skipped when Start fails.
+// Values of fields the schema hides are removed from the input first, and
+// outside production the output is checked against the trigger's declared
+// OutputSchema.
+func RunTrigger(trigger sdk.Trigger, lastRun *time.Time, opts ...Option) *TriggerResult {
+	meta := trigger.Metadata()
+	shared := newOptions(opts)
+	trigger = sdk.WithTriggerOutputValidation(trigger, shared.Environment)
+	logger := shared.Logger
+	if shared.Schema == nil {
+		shared.Schema = trigger.Props()
+		shared.Redactor.AddSchema(shared.Schema, shared.Input)
+	}
+
+	input, inputErr := sdk.StripHiddenInput(shared.Schema, shared.Input)
+	if inputErr == nil {
+		shared.Input = input
+	}
+	opts = []Option{func(o *Options) { *o = *shared }}
+
+	lc := NewLifecycleContext(meta.ID, meta.Criteria, opts...)
+	ec := NewExecuteContext(meta.ID, lastRun, opts...)
+
+	result := &TriggerResult{}
+	fail := func(phase string, err error) {
+		err = shared.Redactor.Error(err)
+		if result.err == nil {
+			result.err = fmt.Errorf("%s: %w", phase, err)
+			result.Error = err.Error()
+			result.Phase = phase
+		}
+	}
+
+	if inputErr != nil {
+		fail("input", inputErr)
+	} else if err := trigger.Start(lc); err != nil {
+		fail("start", err)
+	} else {
+		output, err := trigger.Execute(ec)
+		result.Output = shared.Redactor.Value(output)
+		if err != nil {
+			fail("execute", err)
+		}
+
+		if err := trigger.Stop(lc); err != nil {
+			fail("stop", err)
+		}
+	}
+
+	result.State = ec.ExecutionState()
+	if result.err != nil {
+		result.State = core.StepRunStatusFailed
+	} else if result.State == core.StepRunStatusRunning {
+		result.State = core.StepRunStatusCompleted
+	}
+
+	result.Events = append(lc.Events(), ec.Events()...)
+	result.Logs = logger.GetLogs()
+	result.Pauses = ec.Pauses()
+	result.Metadata = core.MergeMaps(lc.Metadata(), ec.Metadata())
+
+	return result
+}
+
+// OptionsResult is the outcome of RunOptions.
+type OptionsResult struct {
+	// Response is the page of options returned by the function
+	Response *core.DynamicOptionsResponse `json:"response"`
+
+	// Error is the error returned by the function, if any
+	Error string `json:"error,omitempty"`
+
+	// Logs are the entries written to the context logger
+	Logs []core.LogEntry `json:"logs"`
+
+	err error
+}
+
+// Err returns the error returned by the options function.
+func (r *OptionsResult) Err() error { return r.err }
+
+// RunOptions invokes the dynamic options function of field on the given
+// action or trigger. Use WithFilter to select the page and search term.
+func RunOptions(fn sdk.DynamicOptionsFn, operationID, field string, opts ...Option) *OptionsResult {
+	ctx := NewDynamicFieldContext(operationID, field, opts...)
+
+	rsp, err := fn(ctx)
+	err = ctx.opts.Redactor.Error(err)
+	result := &OptionsResult{
+		Response: rsp,
+		Logs:     ctx.Logger().GetLogs(),
+		err:      err,
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
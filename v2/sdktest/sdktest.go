@@ -0,0 +1,245 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sdktest provides in-memory implementations of the SDK execution
+// contexts so actions and triggers can be exercised in unit tests and local
+// runs without the workflow engine. Every side effect an action requests
+// (output, pause, retry, failure, metadata) is recorded for inspection.
+package sdktest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/rs/xid"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/files"
+	"github.com/wakflo/go-sdk/v2/redact"
+)
+
+// Options configures the contexts created by this package.
+type Options struct {
+	Context           context.Context
+	Input             core.JSONObject
+	Auth              *sdkcontext.AuthContext
+	Files             sdkcontext.FileResource
+	Logger            core.Logger
+	Schema            *smartform.FormSchema
+	Environment       core.Environment
+	WorkflowID        xid.ID
+	WorkflowVersionID xid.ID
+	ProjectID         xid.ID
+	RunID             xid.ID
+	StepID            string
+	PreviousOutput    core.JSONObject
+	WorkflowContext   map[string]interface{}
+	Filter            *core.DynamicOptionsFilterParams
+	Redactor          *redact.Redactor
+}
+
+// Option mutates Options.
+type Option func(*Options)
+
+// WithContext sets the Go context returned by Context().
+func WithContext(ctx context.Context) Option {
+	return func(o *Options) { o.Context = ctx }
+}
+
+// WithInput sets the resolved step input.
+func WithInput(input core.JSONObject) Option {
+	return func(o *Options) { o.Input = input }
+}
+
+// WithAuth sets the authentication context.
+func WithAuth(auth *sdkcontext.AuthContext) Option {
+	return func(o *Options) { o.Auth = auth }
+}
+
+// WithFiles sets the file resource returned by Files(). Defaults to a files.MemoryStore.
+func WithFiles(files sdkcontext.FileResource) Option {
+	return func(o *Options) { o.Files = files }
+}
+
+// WithRedactor sets the redactor applied to logs, outputs and errors. By
+// default a redactor learns the secrets of the configured auth and of the
+// input fields the schema marks secret.
+func WithRedactor(r *redact.Redactor) Option {
+	return func(o *Options) { o.Redactor = r }
+}
+
+// WithLogger sets the logger returned by Logger().
+func WithLogger(logger core.Logger) Option {
+	return func(o *Options) { o.Logger = logger }
+}
+
+// WithSchema sets the input schema returned by Schema().
+func WithSchema(schema *smartform.FormSchema) Option {
+	return func(o *Options) { o.Schema = schema }
+}
+
+// WithEnvironment sets the execution environment. Defaults to core.EnvironmentTest.
+func WithEnvironment(env core.Environment) Option {
+	return func(o *Options) { o.Environment = env }
+}
+
+// WithStepID sets the step identifier.
+func WithStepID(stepID string) Option {
+	return func(o *Options) { o.StepID = stepID }
+}
+
+// WithPreviousOutput sets the output returned by PreviousStepOutput().
+func WithPreviousOutput(output core.JSONObject) Option {
+	return func(o *Options) { o.PreviousOutput = output }
+}
+
+// WithWorkflowContext seeds the workflow context data.
+func WithWorkflowContext(data map[string]interface{}) Option {
+	return func(o *Options) { o.WorkflowContext = data }
+}
+
+// WithFilter sets the dynamic options filter.
+func WithFilter(filter *core.DynamicOptionsFilterParams) Option {
+	return func(o *Options) { o.Filter = filter }
+}
+
+func newOptions(opts []Option) *Options {
+	o := &Options{
+		Context:           context.Background(),
+		Input:             core.JSONObject{},
+		Environment:       core.EnvironmentTest,
+		WorkflowID:        xid.New(),
+		WorkflowVersionID: xid.New(),
+		ProjectID:         xid.New(),
+		RunID:             xid.New(),
+		StepID:            "step_1",
+		WorkflowContext:   map[string]interface{}{},
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.Logger == nil {
+		o.Logger = core.NewStructuredLogger(core.WithLogScope(core.LogScope{
+			ProjectID: o.ProjectID.String(),
+			FlowID:    o.WorkflowID.String(),
+			RunID:     o.RunID.String(),
+			StepID:    o.StepID,
+		}))
+	}
+
+	if o.Redactor == nil {
+		o.Redactor = redact.New()
+	}
+	o.Redactor.AddAuth(o.Auth)
+	o.Redactor.AddSchema(o.Schema, o.Input)
+	o.Logger = o.Redactor.Logger(o.Logger)
+
+	if o.Files == nil {
+		o.Files = files.NewMemoryStore()
+	}
+
+	return o
+}
+
+// ErrNoAuth is returned by AuthContext when no credentials were configured.
+var ErrNoAuth = errors.New("no auth context configured")
+
+// baseContext implements context.BaseContext.
+type baseContext struct {
+	opts     *Options
+	mu       sync.Mutex
+	metadata map[string]interface{}
+	canceled bool
+}
+
+func newBaseContext(opts []Option) baseContext {
+	return baseContext{opts: newOptions(opts), metadata: map[string]interface{}{}}
+}
+
+func (c *baseContext) Context() context.Context { return c.opts.Context }
+
+func (c *baseContext) WorkflowID() xid.ID { return c.opts.WorkflowID }
+
+func (c *baseContext) WorkflowVersionID() xid.ID { return c.opts.WorkflowVersionID }
+
+func (c *baseContext) ProjectID() xid.ID { return c.opts.ProjectID }
+
+func (c *baseContext) Logger() core.Logger { return c.opts.Logger }
+
+func (c *baseContext) Input() core.JSONObject { return c.opts.Input }
+
+func (c *baseContext) Auth() *sdkcontext.AuthContext { return c.opts.Auth }
+
+func (c *baseContext) AuthContext() (*sdkcontext.AuthContext, error) {
+	if c.opts.Auth == nil {
+		return nil, ErrNoAuth
+	}
+
+	return c.opts.Auth, nil
+}
+
+func (c *baseContext) Files() sdkcontext.FileResource { return c.opts.Files }
+
+func (c *baseContext) Schema() *smartform.FormSchema { return c.opts.Schema }
+
+// Validate checks the input against the schema. See sdk.ValidateInput.
+func (c *baseContext) Validate() error { return sdk.ValidateInput(c.opts.Schema, c.opts.Input) }
+
+func (c *baseContext) SetMetadata(key string, value interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.metadata[key] = value
+	return nil
+}
+
+func (c *baseContext) GetMetadata(key string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.metadata[key]
+	if !ok {
+		return nil, errors.New("metadata not found: " + key)
+	}
+
+	return value, nil
+}
+
+// Metadata returns a copy of all metadata stored during execution.
+func (c *baseContext) Metadata() map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return core.MergeMaps(c.metadata)
+}
+
+func (c *baseContext) Cancel() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.canceled = true
+	return nil
+}
+
+func (c *baseContext) IsCanceled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.canceled || c.opts.Context.Err() != nil
+}
@@ -0,0 +1,287 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdktest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/stretchr/testify/require"
+	sdkcore "github.com/wakflo/go-sdk/core"
+	sdk "github.com/wakflo/go-sdk/v2"
+	"github.com/wakflo/go-sdk/v2/cassette"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+type greetAction struct{}
+
+func (a *greetAction) Metadata() sdk.ActionMetadata {
+	return sdk.ActionMetadata{ID: "greet", DisplayName: "Greet", Description: "Greets.", Type: core.ActionTypeAction}
+}
+
+func (a *greetAction) Properties() *smartform.FormSchema { return &smartform.FormSchema{ID: "greet"} }
+
+func (a *greetAction) Auth() *core.AuthMetadata { return nil }
+
+func (a *greetAction) Perform(ctx sdkcontext.PerformContext) (core.JSON, error) {
+	auth, err := ctx.AuthContext()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Logger().Infof("greeting %s", ctx.Input()["name"])
+	if err := ctx.SetMetadata("token", auth.AccessToken); err != nil {
+		return nil, err
+	}
+
+	if ctx.Input()["name"] == "later" {
+		return nil, ctx.Retry(time.Minute, "rate limited")
+	}
+
+	return map[string]any{"message": "Hello, " + ctx.Input()["name"].(string)}, nil
+}
+
+type leakyAction struct{ greetAction }
+
+func (a *leakyAction) Perform(ctx sdkcontext.PerformContext) (core.JSON, error) {
+	return nil, fmt.Errorf("perform: %w", &sdk.ActionError{
+		Code:    "upstream_rejected",
+		Message: "rejected token " + ctx.Auth().AccessToken,
+		Details: map[string]interface{}{"request": map[string]any{"token": ctx.Auth().AccessToken}},
+	})
+}
+
+type tickTrigger struct {
+	started, stopped bool
+	fail             bool
+}
+
+func (t *tickTrigger) Metadata() sdk.TriggerMetadata {
+	return sdk.TriggerMetadata{ID: "tick", DisplayName: "Tick", Description: "Ticks.", Type: core.TriggerTypePolling}
+}
+
+func (t *tickTrigger) Props() *smartform.FormSchema { return &smartform.FormSchema{ID: "tick"} }
+
+func (t *tickTrigger) Auth() *core.AuthMetadata { return nil }
+
+func (t *tickTrigger) Start(ctx sdkcontext.LifecycleContext) error {
+	t.started = true
+	return ctx.SetLastRunTime(time.Unix(0, 0))
+}
+
+func (t *tickTrigger) Stop(ctx sdkcontext.LifecycleContext) error {
+	t.stopped = true
+	return nil
+}
+
+func (t *tickTrigger) Execute(ctx sdkcontext.ExecuteContext) (core.JSON, error) {
+	if t.fail {
+		return nil, errors.New("boom")
+	}
+
+	if err := ctx.EmitEvent("tick", map[string]any{"n": 1}); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"lastRun": ctx.LastRun()}, nil
+}
+
+func TestRunAction(t *testing.T) {
+	auth := &sdkcontext.AuthContext{AccessToken: "secret"}
+
+	res := RunAction(&greetAction{}, WithInput(core.JSONObject{"name": "Ada"}), WithAuth(auth))
+	require.NoError(t, res.Err())
+	require.Equal(t, core.StepRunStatusCompleted, res.State)
+	require.Equal(t, map[string]any{"message": "Hello, Ada"}, res.Output)
+	require.Equal(t, "secret", res.Metadata["token"])
+	require.Len(t, res.Logs, 1)
+	require.Equal(t, "greeting Ada", res.Logs[0].Message)
+
+	res = RunAction(&greetAction{}, WithInput(core.JSONObject{"name": "later"}), WithAuth(auth))
+	require.NoError(t, res.Err())
+	require.Equal(t, core.StepRunStatusRetrying, res.State)
+	require.Equal(t, []RetryCall{{After: time.Minute, Reason: "rate limited"}}, res.Retries)
+
+	res = RunAction(&greetAction{}, WithInput(core.JSONObject{"name": "secret"}), WithAuth(auth))
+	require.Equal(t, map[string]any{"message": "Hello, [REDACTED]"}, res.Output)
+	require.Equal(t, "greeting [REDACTED]", res.Logs[0].Message)
+
+	res = RunAction(&greetAction{})
+	require.ErrorIs(t, res.Err(), ErrNoAuth)
+	require.Equal(t, core.StepRunStatusFailed, res.State)
+}
+
+func TestRunActionRedactsActionError(t *testing.T) {
+	res := RunAction(&leakyAction{}, WithAuth(&sdkcontext.AuthContext{AccessToken: "sk-live-123456"}))
+	require.Error(t, res.Err())
+	require.Equal(t, "perform: rejected token [REDACTED]", res.Error)
+	require.Equal(t, "upstream_rejected", res.ActionError.Code)
+	require.Equal(t, map[string]any{"token": "[REDACTED]"}, res.ActionError.Details["request"])
+
+	var ae *sdk.ActionError
+	require.True(t, errors.As(res.Err(), &ae))
+	require.Equal(t, "rejected token [REDACTED]", ae.Message)
+	require.Equal(t, map[string]any{"token": "[REDACTED]"}, ae.Details["request"])
+}
+
+func TestRunTrigger(t *testing.T) {
+	lastRun := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	trigger := &tickTrigger{}
+	res := RunTrigger(trigger, &lastRun)
+	require.NoError(t, res.Err())
+	require.True(t, trigger.started)
+	require.True(t, trigger.stopped)
+	require.Equal(t, map[string]any{"lastRun": &lastRun}, res.Output)
+	require.Equal(t, []Event{{Type: "tick", Payload: map[string]any{"n": 1}}}, res.Events)
+
+	trigger = &tickTrigger{fail: true}
+	res = RunTrigger(trigger, nil)
+	require.EqualError(t, res.Err(), "execute: boom")
+	require.Equal(t, "execute", res.Phase)
+	require.Equal(t, core.StepRunStatusFailed, res.State)
+	require.True(t, trigger.stopped)
+}
+
+// echoAction returns its input, with the second field of its schema hidden.
+type echoAction struct{ greetAction }
+
+func (a *echoAction) Properties() *smartform.FormSchema {
+	return &smartform.FormSchema{ID: "echo", Fields: []*smartform.Field{
+		{ID: "name", Type: "text"},
+		{ID: "legacy", Type: "text", Properties: map[string]interface{}{"hidden": true}},
+	}}
+}
+
+func (a *echoAction) Perform(ctx sdkcontext.PerformContext) (core.JSON, error) {
+	return ctx.Input(), nil
+}
+
+type echoTrigger struct {
+	tickTrigger
+	started core.JSONObject
+}
+
+func (t *echoTrigger) Props() *smartform.FormSchema { return (&echoAction{}).Properties() }
+
+func (t *echoTrigger) Start(ctx sdkcontext.LifecycleContext) error {
+	t.started = ctx.Input()
+	return nil
+}
+
+func (t *echoTrigger) Execute(ctx sdkcontext.ExecuteContext) (core.JSON, error) {
+	return ctx.Input(), nil
+}
+
+func TestRunStripsHiddenInput(t *testing.T) {
+	input := WithInput(core.JSONObject{"name": "Ada", "legacy": "stale"})
+
+	res := RunAction(&echoAction{}, input)
+	require.NoError(t, res.Err())
+	require.Equal(t, core.JSONObject{"name": "Ada"}, res.Output)
+
+	trigger := &echoTrigger{}
+	tres := RunTrigger(trigger, nil, input)
+	require.NoError(t, tres.Err())
+	require.Equal(t, core.JSONObject{"name": "Ada"}, tres.Output)
+	require.Equal(t, core.JSONObject{"name": "Ada"}, trigger.started)
+}
+
+func TestContextValidate(t *testing.T) {
+	schema := &smartform.FormSchema{ID: "greet", Fields: []*smartform.Field{{ID: "name", Type: "text", Required: true}}}
+
+	require.NoError(t, NewPerformContext(WithSchema(schema), WithInput(core.JSONObject{"name": "Ada"})).Validate())
+	require.Error(t, NewPerformContext(WithSchema(schema)).Validate())
+	require.Error(t, NewExecuteContext("tick", nil, WithSchema(schema), WithInput(nil)).Validate())
+	require.NoError(t, NewExecuteContext("tick", nil, WithInput(nil)).Validate())
+}
+
+func TestRunOptions(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	fn := func(ctx sdkcontext.DynamicFieldContext) (*core.DynamicOptionsResponse, error) {
+		f := ctx.Filter()
+		end := min(f.Offset+f.Limit, len(items))
+		return ctx.Respond(items[f.Offset:end], len(items))
+	}
+
+	res := RunOptions(fn, "greet", "name", WithFilter(&core.DynamicOptionsFilterParams{Offset: 1, Limit: 2}))
+	require.NoError(t, res.Err())
+	require.Equal(t, []string{"b", "c"}, res.Response.Items)
+	require.True(t, res.Response.Metadata.HasMore)
+
+	res = RunOptions(fn, "greet", "name", WithFilter(&core.DynamicOptionsFilterParams{Offset: 3}))
+	require.Equal(t, []string{"d", "e"}, res.Response.Items)
+	require.Equal(t, core.DefaultDynamicOptionsLimit, res.Response.Metadata.Limit)
+	require.False(t, res.Response.Metadata.HasMore)
+}
+
+func TestUseCassette(t *testing.T) {
+	dir := t.TempDir()
+	c := &cassette.Cassette{Interactions: []*cassette.Interaction{{
+		Request:  cassette.Request{Method: http.MethodGet, URL: "https://api.example.com/me"},
+		Response: cassette.Response{StatusCode: http.StatusOK, Body: cassette.Body(`{"id":1}`)},
+	}}}
+	require.NoError(t, c.Save(filepath.Join(dir, "me.json")))
+
+	original := CassetteDir
+	CassetteDir = dir
+	t.Cleanup(func() { CassetteDir = original })
+
+	t.Run("replay", func(t *testing.T) {
+		t.Setenv(cassette.EnvMode, "")
+		UseCassette(t, "me")
+
+		rsp, err := http.Get("https://api.example.com/me")
+		require.NoError(t, err)
+		body, _ := io.ReadAll(rsp.Body)
+		require.JSONEq(t, `{"id":1}`, string(body))
+	})
+
+	_, isRecorder := http.DefaultTransport.(*cassette.Recorder)
+	require.False(t, isRecorder)
+}
+
+type typedGreetAction struct {
+	greetAction
+}
+
+func (a *typedGreetAction) Metadata() sdk.ActionMetadata {
+	meta := a.greetAction.Metadata()
+	meta.OutputSchema = &sdkcore.AutoFormSchema{
+		Type:       sdkcore.Object,
+		Properties: map[string]*sdkcore.AutoFormSchema{"message": {Type: sdkcore.Integer}},
+		Required:   []string{"message"},
+	}
+
+	return meta
+}
+
+func TestRunActionValidatesOutput(t *testing.T) {
+	opts := []Option{WithInput(core.JSONObject{"name": "Ada"}), WithAuth(&sdkcontext.AuthContext{})}
+
+	res := RunAction(&typedGreetAction{}, opts...)
+	require.ErrorIs(t, res.Err(), sdk.ErrInvalidOutput)
+	require.Contains(t, res.Error, "/message: must be integer")
+
+	res = RunAction(&typedGreetAction{}, append(opts, WithEnvironment(core.EnvironmentProd))...)
+	require.NoError(t, res.Err())
+}
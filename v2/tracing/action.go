@@ -0,0 +1,82 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WrapAction returns action with every Perform call traced in an
+// "action.perform" span.
+func WrapAction(action sdk.Action, opts ...Option) sdk.Action {
+	return &tracedAction{Action: action, cfg: newConfig(opts)}
+}
+
+type tracedAction struct {
+	sdk.Action
+	cfg config
+}
+
+// Unwrap returns the instrumented action.
+func (a *tracedAction) Unwrap() sdk.Action { return a.Action }
+
+func (a *tracedAction) Perform(ctx sdkcontext.PerformContext) (output core.JSON, err error) {
+	spanCtx, span := a.cfg.start(ctx.Context(), "action.perform", performAttributes(ctx, AttrActionID.String(a.Metadata().ID))...)
+	defer func() { finish(span, err, recover()) }()
+
+	return a.Action.Perform(newPerformContext(ctx, spanCtx, span))
+}
+
+func performAttributes(ctx sdkcontext.PerformContext, attrs ...attribute.KeyValue) []attribute.KeyValue {
+	attrs = append(attrs, baseAttributes(ctx)...)
+	attrs = append(attrs,
+		AttrRunID.String(ctx.RunID().String()),
+		AttrStepID.String(ctx.StepID()),
+		AttrStepRunID.String(ctx.StepRunID().String()),
+	)
+
+	return attrs
+}
+
+// performContext carries the span of a traced call and records retries and
+// failures on it.
+type performContext struct {
+	sdkcontext.PerformContext
+	ctx  context.Context
+	span trace.Span
+}
+
+func newPerformContext(ctx sdkcontext.PerformContext, spanCtx context.Context, span trace.Span) *performContext {
+	return &performContext{PerformContext: ctx, ctx: spanCtx, span: span}
+}
+
+func (c *performContext) Context() context.Context { return c.ctx }
+
+func (c *performContext) Retry(after time.Duration, reason string) error {
+	recordRetry(c.span, after, reason)
+	return c.PerformContext.Retry(after, reason)
+}
+
+func (c *performContext) MarkFailed(reason string) error {
+	recordFailed(c.span, reason)
+	return c.PerformContext.MarkFailed(reason)
+}
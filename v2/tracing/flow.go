@@ -0,0 +1,157 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	sdk "github.com/wakflo/go-sdk/v2"
+	"github.com/wakflo/go-sdk/v2/flow"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Flow span attribute keys.
+const (
+	AttrLoopType       = attribute.Key("wakflo.loop.type")
+	AttrLoopIndex      = attribute.Key("wakflo.loop.index")
+	AttrBranchPath     = attribute.Key("wakflo.branch.path")
+	AttrBooleanOp      = attribute.Key("wakflo.boolean.operator")
+	AttrBooleanResult  = attribute.Key("wakflo.boolean.result")
+	AttrConditionCount = attribute.Key("wakflo.condition.count")
+)
+
+// WrapLoop returns loop with every Execute call traced in a "loop.execute"
+// span. Each iteration the controller advances to is recorded as an event.
+func WrapLoop(loop flow.Loop, opts ...Option) flow.Loop {
+	return &tracedLoop{Loop: loop, cfg: newConfig(opts)}
+}
+
+type tracedLoop struct {
+	flow.Loop
+	cfg config
+}
+
+// Unwrap returns the instrumented loop.
+func (l *tracedLoop) Unwrap() flow.Loop { return l.Loop }
+
+func (l *tracedLoop) Execute(ctx flow.LoopExecutionContext, controller flow.LoopController) (err error) {
+	perform := ctx.Context()
+	attrs := performAttributes(perform,
+		AttrFlowID.String(l.Metadata().ID),
+		AttrLoopType.String(string(ctx.LoopType())),
+	)
+
+	spanCtx, span := l.cfg.start(perform.Context(), "loop.execute", attrs...)
+	defer func() { finish(span, err, recover()) }()
+
+	return l.Loop.Execute(
+		&loopContext{LoopExecutionContext: ctx, perform: newPerformContext(perform, spanCtx, span)},
+		&loopController{LoopController: controller, span: span},
+	)
+}
+
+type loopContext struct {
+	flow.LoopExecutionContext
+	perform sdk.PerformContext
+}
+
+func (c *loopContext) Context() sdk.PerformContext { return c.perform }
+
+type loopController struct {
+	flow.LoopController
+	span trace.Span
+}
+
+func (c *loopController) Next(ctx flow.LoopExecutionContext) (*flow.LoopIteration, error) {
+	iteration, err := c.LoopController.Next(ctx)
+	if err == nil && iteration != nil {
+		c.span.AddEvent(EventIteration, trace.WithAttributes(AttrLoopIndex.Int(iteration.Index)))
+	}
+
+	return iteration, err
+}
+
+// WrapBranch returns branch with every Evaluate call traced in a
+// "branch.evaluate" span that records the chosen path.
+func WrapBranch(branch flow.Branch, opts ...Option) flow.Branch {
+	return &tracedBranch{Branch: branch, cfg: newConfig(opts)}
+}
+
+type tracedBranch struct {
+	flow.Branch
+	cfg config
+}
+
+// Unwrap returns the instrumented branch.
+func (b *tracedBranch) Unwrap() flow.Branch { return b.Branch }
+
+func (b *tracedBranch) Evaluate(ctx flow.BranchEvaluationContext) (result *flow.BranchPathResult, err error) {
+	perform := ctx.Context()
+	spanCtx, span := b.cfg.start(perform.Context(), "branch.evaluate", performAttributes(perform, AttrFlowID.String(b.Metadata().ID))...)
+	defer func() { finish(span, err, recover()) }()
+
+	result, err = b.Branch.Evaluate(&branchContext{BranchEvaluationContext: ctx, perform: newPerformContext(perform, spanCtx, span)})
+	if result != nil {
+		span.SetAttributes(AttrBranchPath.String(result.PathName))
+	}
+
+	return result, err
+}
+
+type branchContext struct {
+	flow.BranchEvaluationContext
+	perform sdk.PerformContext
+}
+
+func (c *branchContext) Context() sdk.PerformContext { return c.perform }
+
+// WrapBoolean returns boolean with every Evaluate call traced in a
+// "boolean.evaluate" span that records the result.
+func WrapBoolean(boolean flow.Boolean, opts ...Option) flow.Boolean {
+	return &tracedBoolean{Boolean: boolean, cfg: newConfig(opts)}
+}
+
+type tracedBoolean struct {
+	flow.Boolean
+	cfg config
+}
+
+// Unwrap returns the instrumented boolean.
+func (b *tracedBoolean) Unwrap() flow.Boolean { return b.Boolean }
+
+func (b *tracedBoolean) Evaluate(ctx flow.BooleanEvaluationContext) (result *flow.BooleanResult, err error) {
+	perform := ctx.Context()
+	attrs := performAttributes(perform,
+		AttrFlowID.String(b.Metadata().ID),
+		AttrBooleanOp.String(string(ctx.Operator())),
+		AttrConditionCount.Int(len(ctx.Conditions())),
+	)
+
+	spanCtx, span := b.cfg.start(perform.Context(), "boolean.evaluate", attrs...)
+	defer func() { finish(span, err, recover()) }()
+
+	result, err = b.Boolean.Evaluate(&booleanContext{BooleanEvaluationContext: ctx, perform: newPerformContext(perform, spanCtx, span)})
+	if result != nil {
+		span.SetAttributes(AttrBooleanResult.Bool(result.Result))
+	}
+
+	return result, err
+}
+
+type booleanContext struct {
+	flow.BooleanEvaluationContext
+	perform sdk.PerformContext
+}
+
+func (c *booleanContext) Context() sdk.PerformContext { return c.perform }
@@ -0,0 +1,185 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing instruments actions, triggers and flow components with
+// OpenTelemetry spans.
+//
+// Wrapped components open a span for every Perform, Execute, Start, Stop or
+// Evaluate call, tag it with the run, step and integration it belongs to, and
+// record errors and retries on it. The span is carried by the Go context the
+// component receives, so helpers such as HTTPClient propagate it to outbound
+// calls:
+//
+//	provider := tracing.NewProvider(exporter)
+//	defer provider.Shutdown(ctx)
+//
+//	action = tracing.WrapAction(action, tracing.WithTracerProvider(provider), tracing.WithIntegration("slack"))
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies the tracer used by this package.
+const InstrumentationName = "github.com/wakflo/go-sdk/v2/tracing"
+
+// Span attribute keys.
+const (
+	AttrProjectID         = attribute.Key("wakflo.project.id")
+	AttrWorkflowID        = attribute.Key("wakflo.workflow.id")
+	AttrWorkflowVersionID = attribute.Key("wakflo.workflow.version_id")
+	AttrRunID             = attribute.Key("wakflo.run.id")
+	AttrStepID            = attribute.Key("wakflo.step.id")
+	AttrStepRunID         = attribute.Key("wakflo.step.run_id")
+	AttrIntegrationID     = attribute.Key("wakflo.integration.id")
+	AttrActionID          = attribute.Key("wakflo.action.id")
+	AttrTriggerID         = attribute.Key("wakflo.trigger.id")
+	AttrFlowID            = attribute.Key("wakflo.flow.id")
+	AttrRetryAfter        = attribute.Key("wakflo.retry.after")
+	AttrRetryReason       = attribute.Key("wakflo.retry.reason")
+	AttrFailureReason     = attribute.Key("wakflo.failure.reason")
+)
+
+// Span event names.
+const (
+	EventRetry     = "retry"
+	EventFailed    = "failed"
+	EventIteration = "iteration"
+)
+
+// Exporter receives finished spans. Any OpenTelemetry span exporter can be used.
+type Exporter = sdktrace.SpanExporter
+
+// NewProvider returns a tracer provider that exports spans to exporter in
+// batches. Shut it down to flush pending spans.
+func NewProvider(exporter Exporter, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithBatcher(exporter)}, opts...)...)
+}
+
+// NewTestProvider returns a tracer provider that exports every span as soon as
+// it ends to the returned in-memory exporter.
+func NewTestProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+// Option configures instrumentation.
+type Option func(*config)
+
+type config struct {
+	provider    trace.TracerProvider
+	integration string
+	attrs       []attribute.KeyValue
+}
+
+// WithTracerProvider sets the provider spans are created with. Defaults to the
+// global OpenTelemetry provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) { c.provider = provider }
+}
+
+// WithIntegration tags spans with the ID of the integration the component belongs to.
+func WithIntegration(integrationID string) Option {
+	return func(c *config) { c.integration = integrationID }
+}
+
+// WithAttributes adds attributes to every span.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) { c.attrs = append(c.attrs, attrs...) }
+}
+
+func newConfig(opts []Option) config {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// start opens a span named name as a child of the span in parent.
+func (c config) start(parent context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	provider := c.provider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	if c.integration != "" {
+		attrs = append(attrs, AttrIntegrationID.String(c.integration))
+	}
+
+	return provider.Tracer(InstrumentationName).Start(parent, name, trace.WithAttributes(append(attrs, c.attrs...)...))
+}
+
+// finish records the outcome of a call on span and ends it. A recovered panic
+// is recorded and then re-raised.
+func finish(span trace.Span, err error, recovered any) {
+	if recovered != nil {
+		err = fmt.Errorf("panic: %v", recovered)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+
+	span.End()
+
+	if recovered != nil {
+		panic(recovered)
+	}
+}
+
+func recordRetry(span trace.Span, after time.Duration, reason string) {
+	span.AddEvent(EventRetry, trace.WithAttributes(
+		AttrRetryAfter.String(after.String()),
+		AttrRetryReason.String(reason),
+	))
+}
+
+func recordFailed(span trace.Span, reason string) {
+	span.AddEvent(EventFailed, trace.WithAttributes(AttrFailureReason.String(reason)))
+}
+
+// baseAttributes describes the project and workflow of ctx.
+func baseAttributes(ctx sdkcontext.BaseContext) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if id := ctx.ProjectID(); !id.IsNil() {
+		attrs = append(attrs, AttrProjectID.String(id.String()))
+	}
+	if id := ctx.WorkflowID(); !id.IsNil() {
+		attrs = append(attrs, AttrWorkflowID.String(id.String()))
+	}
+	if id := ctx.WorkflowVersionID(); !id.IsNil() {
+		attrs = append(attrs, AttrWorkflowVersionID.String(id.String()))
+	}
+
+	return attrs
+}
@@ -0,0 +1,159 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/juicycleff/smartform/v1"
+	"github.com/stretchr/testify/require"
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+	"github.com/wakflo/go-sdk/v2/sdktest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fetchAction struct {
+	url string
+}
+
+func (a *fetchAction) Metadata() sdk.ActionMetadata {
+	return sdk.ActionMetadata{ID: "fetch", DisplayName: "Fetch", Type: core.ActionTypeAction}
+}
+
+func (a *fetchAction) Properties() *smartform.FormSchema { return &smartform.FormSchema{ID: "fetch"} }
+
+func (a *fetchAction) Auth() *core.AuthMetadata { return nil }
+
+func (a *fetchAction) Perform(ctx sdkcontext.PerformContext) (core.JSON, error) {
+	req, err := http.NewRequestWithContext(ctx.Context(), http.MethodGet, a.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := HTTPClient(nil).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		_ = ctx.Retry(time.Minute, "rate limited")
+		return nil, errors.New("rate limited")
+	}
+
+	return map[string]any{"status": resp.StatusCode}, nil
+}
+
+type pingTrigger struct{}
+
+func (t *pingTrigger) Metadata() sdk.TriggerMetadata {
+	return sdk.TriggerMetadata{ID: "ping", DisplayName: "Ping", Type: core.TriggerTypePolling}
+}
+
+func (t *pingTrigger) Props() *smartform.FormSchema { return &smartform.FormSchema{ID: "ping"} }
+
+func (t *pingTrigger) Auth() *core.AuthMetadata { return nil }
+
+func (t *pingTrigger) Start(ctx sdkcontext.LifecycleContext) error { return nil }
+
+func (t *pingTrigger) Stop(ctx sdkcontext.LifecycleContext) error { return nil }
+
+func (t *pingTrigger) Execute(ctx sdkcontext.ExecuteContext) (core.JSON, error) {
+	if !trace.SpanContextFromContext(ctx.Context()).IsValid() {
+		return nil, errors.New("no span in context")
+	}
+
+	return map[string]any{"ok": true}, nil
+}
+
+func attrs(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	out := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		out[kv.Key] = kv.Value
+	}
+
+	return out
+}
+
+func TestWrapAction(t *testing.T) {
+	provider, exporter := NewTestProvider()
+
+	var traceparents []string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	action := WrapAction(&fetchAction{url: srv.URL}, WithTracerProvider(provider), WithIntegration("web"))
+	ctx := sdktest.NewPerformContext(sdktest.WithStepID("fetch_1"))
+
+	_, err := action.Perform(ctx)
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	require.Equal(t, "action.perform", span.Name)
+	require.Equal(t, codes.Ok, span.Status.Code)
+
+	got := attrs(span)
+	require.Equal(t, "fetch", got[AttrActionID].AsString())
+	require.Equal(t, "web", got[AttrIntegrationID].AsString())
+	require.Equal(t, "fetch_1", got[AttrStepID].AsString())
+	require.Equal(t, ctx.RunID().String(), got[AttrRunID].AsString())
+	require.Equal(t, ctx.ProjectID().String(), got[AttrProjectID].AsString())
+
+	require.Len(t, traceparents, 1)
+	require.Contains(t, traceparents[0], span.SpanContext.TraceID().String())
+	require.Contains(t, traceparents[0], span.SpanContext.SpanID().String())
+
+	exporter.Reset()
+	status = http.StatusTooManyRequests
+	_, err = action.Perform(sdktest.NewPerformContext())
+	require.EqualError(t, err, "rate limited")
+
+	span = exporter.GetSpans()[0]
+	require.Equal(t, codes.Error, span.Status.Code)
+	require.Equal(t, EventRetry, span.Events[0].Name)
+	require.Equal(t, "exception", span.Events[1].Name)
+}
+
+func TestWrapTrigger(t *testing.T) {
+	provider, exporter := NewTestProvider()
+	trigger := WrapTrigger(&pingTrigger{}, WithTracerProvider(provider))
+
+	require.NoError(t, trigger.Start(sdktest.NewLifecycleContext("ping_1", nil)))
+	_, err := trigger.Execute(sdktest.NewExecuteContext("ping_1", nil))
+	require.NoError(t, err)
+	require.NoError(t, trigger.Stop(sdktest.NewLifecycleContext("ping_1", nil)))
+
+	var names []string
+	for _, span := range exporter.GetSpans() {
+		names = append(names, span.Name)
+		require.Equal(t, "ping", attrs(span)[AttrTriggerID].AsString())
+	}
+	require.Equal(t, []string{"trigger.start", "trigger.execute", "trigger.stop"}, names)
+}
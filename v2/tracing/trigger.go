@@ -0,0 +1,77 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+
+	sdk "github.com/wakflo/go-sdk/v2"
+	sdkcontext "github.com/wakflo/go-sdk/v2/context"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// WrapTrigger returns trigger with its Start, Stop and Execute calls traced in
+// "trigger.start", "trigger.stop" and "trigger.execute" spans.
+func WrapTrigger(trigger sdk.Trigger, opts ...Option) sdk.Trigger {
+	return &tracedTrigger{Trigger: trigger, cfg: newConfig(opts)}
+}
+
+type tracedTrigger struct {
+	sdk.Trigger
+	cfg config
+}
+
+// Unwrap returns the instrumented trigger.
+func (t *tracedTrigger) Unwrap() sdk.Trigger { return t.Trigger }
+
+func (t *tracedTrigger) Start(ctx sdkcontext.LifecycleContext) (err error) {
+	spanCtx, span := t.cfg.start(ctx.Context(), "trigger.start", AttrTriggerID.String(t.Metadata().ID))
+	defer func() { finish(span, err, recover()) }()
+
+	return t.Trigger.Start(&lifecycleContext{LifecycleContext: ctx, ctx: spanCtx})
+}
+
+func (t *tracedTrigger) Stop(ctx sdkcontext.LifecycleContext) (err error) {
+	spanCtx, span := t.cfg.start(ctx.Context(), "trigger.stop", AttrTriggerID.String(t.Metadata().ID))
+	defer func() { finish(span, err, recover()) }()
+
+	return t.Trigger.Stop(&lifecycleContext{LifecycleContext: ctx, ctx: spanCtx})
+}
+
+func (t *tracedTrigger) Execute(ctx sdkcontext.ExecuteContext) (output core.JSON, err error) {
+	attrs := append(baseAttributes(ctx),
+		AttrTriggerID.String(t.Metadata().ID),
+		AttrRunID.String(ctx.RunID().String()),
+	)
+
+	spanCtx, span := t.cfg.start(ctx.Context(), "trigger.execute", attrs...)
+	defer func() { finish(span, err, recover()) }()
+
+	return t.Trigger.Execute(&executeContext{ExecuteContext: ctx, ctx: spanCtx})
+}
+
+type lifecycleContext struct {
+	sdkcontext.LifecycleContext
+	ctx context.Context
+}
+
+func (c *lifecycleContext) Context() context.Context { return c.ctx }
+
+type executeContext struct {
+	sdkcontext.ExecuteContext
+	ctx context.Context
+}
+
+func (c *executeContext) Context() context.Context { return c.ctx }
@@ -16,6 +16,7 @@ package sdk
 
 import (
 	"github.com/juicycleff/smartform/v1"
+	sdkcore "github.com/wakflo/go-sdk/core"
 	"github.com/wakflo/go-sdk/v2/context"
 	"github.com/wakflo/go-sdk/v2/core"
 )
@@ -46,6 +47,9 @@ type TriggerMetadata struct {
 	// SampleOutput contains an example of the trigger's output
 	SampleOutput core.JSON `json:"sampleOutput,omitempty"`
 
+	// OutputSchema describes the trigger's output; when nil it is inferred from SampleOutput
+	OutputSchema *sdkcore.AutoFormSchema `json:"outputSchema,omitempty"`
+
 	// Criteria returns additional trigger criteria configuration
 	Criteria *core.TriggerCriteria `json:"criteria"`
 }
@@ -79,6 +83,9 @@ type TriggerDefinition struct {
 	// SampleOutput contains an example of the trigger's output
 	SampleOutput core.JSON `json:"sampleOutput,omitempty"`
 
+	// OutputSchema describes the trigger's output, declared or inferred from SampleOutput
+	OutputSchema *sdkcore.AutoFormSchema `json:"outputSchema,omitempty"`
+
 	// Properties defines the schema for additional configuration required for the trigger in the form of a smartform.
 	Properties *smartform.FormSchema `json:"properties"`
 
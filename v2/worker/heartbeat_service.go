@@ -0,0 +1,279 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+var (
+	// ErrHeartbeatRunning is returned by Start when a worker's heartbeat loop is already running.
+	ErrHeartbeatRunning = errors.New("worker: heartbeat already running")
+
+	// ErrHeartbeatNotRunning is returned by Stop when a worker has no heartbeat loop.
+	ErrHeartbeatNotRunning = errors.New("worker: heartbeat not running")
+)
+
+// Heartbeat defaults applied by Start.
+const (
+	DefaultHeartbeatInterval = 10 * time.Second
+	DefaultHeartbeatBackoff  = time.Second
+)
+
+// HeartbeatOption configures a HeartbeatService.
+type HeartbeatOption func(*HeartbeatService)
+
+// WithHeartbeatStatus sets the function that reports a worker's status for
+// each heartbeat. By default workers report WorkerStatusOnline.
+func WithHeartbeatStatus(fn func(ctx context.Context, workerID xid.ID) HeartbeatStatus) HeartbeatOption {
+	return func(s *HeartbeatService) { s.status = fn }
+}
+
+// WithSystemSampler sets the sampler used when IncludeDetailedMetrics is on.
+// Defaults to NewSystemSampler("/").
+func WithSystemSampler(sampler SystemSampler) HeartbeatOption {
+	return func(s *HeartbeatService) { s.sampler = sampler }
+}
+
+// WithHeartbeatLogger sets the logger for heartbeat operations.
+func WithHeartbeatLogger(logger core.Logger) HeartbeatOption {
+	return func(s *HeartbeatService) { s.logger = logger }
+}
+
+// HeartbeatService is the reference Heartbeat implementation. Each started
+// worker runs a loop that sends heartbeats through a HeartbeatTransport with
+// retries, while liveness queries are answered by a HeartbeatStore.
+type HeartbeatService struct {
+	transport HeartbeatTransport
+	store     HeartbeatStore
+	status    func(ctx context.Context, workerID xid.ID) HeartbeatStatus
+	sampler   SystemSampler
+	logger    core.Logger
+
+	mu    sync.Mutex
+	loops map[xid.ID]*heartbeatLoop
+}
+
+var _ Heartbeat = (*HeartbeatService)(nil)
+
+type heartbeatLoop struct {
+	options HeartbeatOptions
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu      sync.Mutex
+	lastErr string
+	latency time.Duration
+}
+
+// NewHeartbeatService creates a HeartbeatService that sends through transport
+// and reads liveness from store. A MemoryHeartbeatStore can serve as both.
+func NewHeartbeatService(transport HeartbeatTransport, store HeartbeatStore, opts ...HeartbeatOption) *HeartbeatService {
+	s := &HeartbeatService{
+		transport: transport,
+		store:     store,
+		loops:     map[xid.ID]*heartbeatLoop{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.status == nil {
+		s.status = func(context.Context, xid.ID) HeartbeatStatus {
+			return HeartbeatStatus{Status: WorkerStatusOnline}
+		}
+	}
+	if s.sampler == nil {
+		s.sampler = NewSystemSampler("/")
+	}
+	if s.logger == nil {
+		s.logger = core.NewStructuredLogger()
+	}
+
+	return s
+}
+
+// Start sends a heartbeat for workerID immediately and then every
+// options.Interval until Stop is called or ctx is done. A zero Interval
+// defaults to DefaultHeartbeatInterval, a zero Timeout to three intervals and
+// a zero RetryBackoff to DefaultHeartbeatBackoff.
+func (s *HeartbeatService) Start(ctx context.Context, workerID xid.ID, options HeartbeatOptions) error {
+	if options.Interval <= 0 {
+		options.Interval = DefaultHeartbeatInterval
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 3 * options.Interval
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = DefaultHeartbeatBackoff
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.loops[workerID]; ok {
+		return ErrHeartbeatRunning
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	loop := &heartbeatLoop{options: options, cancel: cancel, done: make(chan struct{})}
+	s.loops[workerID] = loop
+
+	go s.run(loopCtx, workerID, loop)
+	return nil
+}
+
+func (s *HeartbeatService) run(ctx context.Context, workerID xid.ID, loop *heartbeatLoop) {
+	defer close(loop.done)
+
+	ticker := time.NewTicker(loop.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.beat(ctx, workerID, loop, s.status(ctx, workerID))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// beat completes status with loop's measurements and sends it.
+func (s *HeartbeatService) beat(ctx context.Context, workerID xid.ID, loop *heartbeatLoop, status HeartbeatStatus) {
+	if loop.options.IncludeDetailedMetrics {
+		usage, err := s.sampler.Sample(ctx)
+		if err != nil {
+			s.logger.Debug("heartbeat: system sampling failed", "worker", workerID.String(), "error", err.Error())
+		} else {
+			status.CPUUsage, status.MemoryUsage, status.DiskUsage = usage.CPU, usage.Memory, usage.Disk
+		}
+	}
+
+	loop.mu.Lock()
+	if status.NetworkLatency == 0 {
+		status.NetworkLatency = loop.latency.Milliseconds()
+	}
+	if status.LastError == "" {
+		status.LastError = loop.lastErr
+	}
+	loop.mu.Unlock()
+
+	start := time.Now()
+	err := s.send(ctx, workerID, status, loop.options)
+
+	loop.mu.Lock()
+	defer loop.mu.Unlock()
+
+	if err != nil {
+		if ctx.Err() == nil {
+			loop.lastErr = err.Error()
+			s.logger.Warn("heartbeat: send failed", "worker", workerID.String(), "error", err.Error())
+		}
+		return
+	}
+
+	loop.latency = time.Since(start)
+	loop.lastErr = ""
+}
+
+// send delivers status, retrying failed attempts as options allow.
+func (s *HeartbeatService) send(ctx context.Context, workerID xid.ID, status HeartbeatStatus, options HeartbeatOptions) error {
+	record := HeartbeatRecord{WorkerID: workerID, Status: status, SentAt: time.Now(), Timeout: options.Timeout}
+
+	var err error
+	for attempt := 0; attempt <= max(options.RetryAttempts, 0); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(options.RetryBackoff):
+			}
+		}
+
+		if err = s.transport.Send(ctx, record); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("worker: send heartbeat: %w", err)
+}
+
+// Stop ends workerID's heartbeat loop and sends a final heartbeat reporting
+// WorkerStatusOffline, so the worker is not reported dead.
+func (s *HeartbeatService) Stop(ctx context.Context, workerID xid.ID) error {
+	s.mu.Lock()
+	loop, ok := s.loops[workerID]
+	delete(s.loops, workerID)
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrHeartbeatNotRunning
+	}
+
+	loop.cancel()
+	select {
+	case <-loop.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	status := s.status(ctx, workerID)
+	status.Status = WorkerStatusOffline
+	return s.send(ctx, workerID, status, loop.options)
+}
+
+// SendHeartbeat sends status for workerID immediately, with the retry settings
+// of its loop if one is running.
+func (s *HeartbeatService) SendHeartbeat(ctx context.Context, workerID xid.ID, status HeartbeatStatus) error {
+	s.mu.Lock()
+	loop, ok := s.loops[workerID]
+	s.mu.Unlock()
+
+	if ok {
+		return s.send(ctx, workerID, status, loop.options)
+	}
+
+	return s.send(ctx, workerID, status, HeartbeatOptions{Timeout: DefaultHeartbeatTimeout})
+}
+
+func (s *HeartbeatService) GetLastHeartbeat(ctx context.Context, workerID xid.ID) (*HeartbeatStatus, time.Time, error) {
+	record, receivedAt, err := s.store.Last(ctx, workerID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return &record.Status, receivedAt, nil
+}
+
+func (s *HeartbeatService) IsAlive(ctx context.Context, workerID xid.ID) (bool, error) {
+	return s.store.IsAlive(ctx, workerID)
+}
+
+func (s *HeartbeatService) GetDeadWorkers(ctx context.Context) ([]xid.ID, error) {
+	return s.store.DeadWorkers(ctx)
+}
+
+func (s *HeartbeatService) Logger() core.Logger {
+	return s.logger
+}
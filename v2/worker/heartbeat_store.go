@@ -0,0 +1,192 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// ErrWorkerNotFound is returned when no record exists for a worker.
+var ErrWorkerNotFound = errors.New("worker: worker not found")
+
+// HeartbeatRecord is a heartbeat as sent by a worker.
+type HeartbeatRecord struct {
+	// WorkerID is the worker that sent the heartbeat
+	WorkerID xid.ID `json:"workerId"`
+
+	// Status is the reported worker status
+	Status HeartbeatStatus `json:"status"`
+
+	// SentAt is when the worker sent the heartbeat
+	SentAt time.Time `json:"sentAt"`
+
+	// Timeout is how long the worker is considered alive after this heartbeat
+	Timeout time.Duration `json:"timeout"`
+}
+
+// HeartbeatTransport delivers heartbeats from a worker to a HeartbeatStore.
+type HeartbeatTransport interface {
+	// Send delivers a single heartbeat
+	Send(ctx context.Context, record HeartbeatRecord) error
+}
+
+// HeartbeatTransportFunc adapts a function to a HeartbeatTransport.
+type HeartbeatTransportFunc func(ctx context.Context, record HeartbeatRecord) error
+
+// Send calls f.
+func (f HeartbeatTransportFunc) Send(ctx context.Context, record HeartbeatRecord) error {
+	return f(ctx, record)
+}
+
+// HeartbeatStore keeps the last heartbeat of each worker and computes liveness.
+type HeartbeatStore interface {
+	// Record stores a heartbeat, replacing any earlier one from the same worker
+	Record(ctx context.Context, record HeartbeatRecord) error
+
+	// Last returns the last heartbeat received from a worker and when it was received
+	Last(ctx context.Context, workerID xid.ID) (*HeartbeatRecord, time.Time, error)
+
+	// IsAlive reports whether a worker's last heartbeat is within its timeout
+	IsAlive(ctx context.Context, workerID xid.ID) (bool, error)
+
+	// DeadWorkers returns the workers whose last heartbeat has timed out
+	DeadWorkers(ctx context.Context) ([]xid.ID, error)
+
+	// Remove forgets a worker
+	Remove(ctx context.Context, workerID xid.ID) error
+}
+
+// DefaultHeartbeatTimeout is used for heartbeats that carry no timeout.
+const DefaultHeartbeatTimeout = 30 * time.Second
+
+// MemoryStoreOption configures a MemoryHeartbeatStore.
+type MemoryStoreOption func(*MemoryHeartbeatStore)
+
+// WithStoreClock sets the clock used to timestamp and age heartbeats.
+func WithStoreClock(now func() time.Time) MemoryStoreOption {
+	return func(s *MemoryHeartbeatStore) { s.now = now }
+}
+
+// WithDefaultTimeout sets the timeout applied to heartbeats that carry none.
+func WithDefaultTimeout(d time.Duration) MemoryStoreOption {
+	return func(s *MemoryHeartbeatStore) { s.timeout = d }
+}
+
+// MemoryHeartbeatStore is an in-memory HeartbeatStore. It is also a
+// HeartbeatTransport that records what it is sent, so a cluster of workers can
+// be simulated by pointing their heartbeat loops at one store.
+type MemoryHeartbeatStore struct {
+	mu      sync.RWMutex
+	entries map[xid.ID]heartbeatEntry
+	now     func() time.Time
+	timeout time.Duration
+}
+
+type heartbeatEntry struct {
+	record     HeartbeatRecord
+	receivedAt time.Time
+}
+
+// NewMemoryHeartbeatStore creates an empty MemoryHeartbeatStore.
+func NewMemoryHeartbeatStore(opts ...MemoryStoreOption) *MemoryHeartbeatStore {
+	s := &MemoryHeartbeatStore{
+		entries: map[xid.ID]heartbeatEntry{},
+		now:     time.Now,
+		timeout: DefaultHeartbeatTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Send records record.
+func (s *MemoryHeartbeatStore) Send(ctx context.Context, record HeartbeatRecord) error {
+	return s.Record(ctx, record)
+}
+
+func (s *MemoryHeartbeatStore) Record(ctx context.Context, record HeartbeatRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[record.WorkerID] = heartbeatEntry{record: record, receivedAt: s.now()}
+	return nil
+}
+
+func (s *MemoryHeartbeatStore) Last(ctx context.Context, workerID xid.ID) (*HeartbeatRecord, time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[workerID]
+	if !ok {
+		return nil, time.Time{}, ErrWorkerNotFound
+	}
+
+	record := entry.record
+	return &record, entry.receivedAt, nil
+}
+
+func (s *MemoryHeartbeatStore) IsAlive(ctx context.Context, workerID xid.ID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[workerID]
+	if !ok {
+		return false, ErrWorkerNotFound
+	}
+
+	return entry.record.Status.Status != WorkerStatusOffline && !s.expired(entry), nil
+}
+
+// DeadWorkers returns the workers whose last heartbeat has timed out, sorted
+// by ID. Workers that reported going offline are not dead.
+func (s *MemoryHeartbeatStore) DeadWorkers(ctx context.Context) ([]xid.ID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var dead []xid.ID
+	for id, entry := range s.entries {
+		if entry.record.Status.Status != WorkerStatusOffline && s.expired(entry) {
+			dead = append(dead, id)
+		}
+	}
+
+	slices.SortFunc(dead, func(a, b xid.ID) int { return a.Compare(b) })
+	return dead, nil
+}
+
+func (s *MemoryHeartbeatStore) Remove(ctx context.Context, workerID xid.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, workerID)
+	return nil
+}
+
+func (s *MemoryHeartbeatStore) expired(entry heartbeatEntry) bool {
+	timeout := entry.record.Timeout
+	if timeout <= 0 {
+		timeout = s.timeout
+	}
+
+	return s.now().Sub(entry.receivedAt) > timeout
+}
@@ -0,0 +1,148 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+type fixedSampler struct{}
+
+func (fixedSampler) Sample(context.Context) (SystemUsage, error) {
+	return SystemUsage{CPU: 12.5, Memory: 40, Disk: 70}, nil
+}
+
+func TestHeartbeatCluster(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	store := NewMemoryHeartbeatStore(WithStoreClock(clock.Now))
+	hb := NewHeartbeatService(store, store, WithSystemSampler(fixedSampler{}))
+	ctx := context.Background()
+	options := HeartbeatOptions{Interval: 5 * time.Millisecond, Timeout: time.Minute, IncludeDetailedMetrics: true}
+
+	healthy, graceful, crashed := xid.New(), xid.New(), xid.New()
+	crashCtx, crash := context.WithCancel(ctx)
+	require.NoError(t, hb.Start(ctx, healthy, options))
+	require.NoError(t, hb.Start(ctx, graceful, options))
+	require.NoError(t, hb.Start(crashCtx, crashed, options))
+	require.ErrorIs(t, hb.Start(ctx, healthy, options), ErrHeartbeatRunning)
+
+	for _, id := range []xid.ID{healthy, graceful, crashed} {
+		require.Eventually(t, func() bool {
+			alive, _ := hb.IsAlive(ctx, id)
+			return alive
+		}, time.Second, time.Millisecond)
+	}
+
+	status, _, err := hb.GetLastHeartbeat(ctx, healthy)
+	require.NoError(t, err)
+	require.Equal(t, WorkerStatusOnline, status.Status)
+	require.Equal(t, 40.0, status.MemoryUsage)
+
+	require.NoError(t, hb.Stop(ctx, graceful))
+	require.ErrorIs(t, hb.Stop(ctx, graceful), ErrHeartbeatNotRunning)
+	crash()
+
+	// Advance twice so a beat the crashed worker had in flight is aged out too.
+	for range 2 {
+		clock.Advance(2 * time.Minute)
+		require.Eventually(t, func() bool {
+			alive, _ := hb.IsAlive(ctx, healthy)
+			return alive
+		}, time.Second, time.Millisecond)
+	}
+
+	alive, err := hb.IsAlive(ctx, graceful)
+	require.NoError(t, err)
+	require.False(t, alive)
+
+	dead, err := hb.GetDeadWorkers(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []xid.ID{crashed}, dead)
+
+	_, err = hb.IsAlive(ctx, xid.New())
+	require.ErrorIs(t, err, ErrWorkerNotFound)
+	require.NoError(t, hb.Stop(ctx, healthy))
+}
+
+func TestHeartbeatRetries(t *testing.T) {
+	store := NewMemoryHeartbeatStore()
+	failures := 2
+	transport := HeartbeatTransportFunc(func(ctx context.Context, record HeartbeatRecord) error {
+		if failures > 0 {
+			failures--
+			return errors.New("connection refused")
+		}
+		return store.Send(ctx, record)
+	})
+
+	hb := NewHeartbeatService(transport, store)
+	ctx := context.Background()
+	id := xid.New()
+
+	require.NoError(t, hb.Start(ctx, id, HeartbeatOptions{Interval: time.Hour, RetryAttempts: 2, RetryBackoff: time.Millisecond}))
+	require.Eventually(t, func() bool {
+		_, _, err := hb.GetLastHeartbeat(ctx, id)
+		return err == nil
+	}, time.Second, time.Millisecond)
+	require.NoError(t, hb.Stop(ctx, id))
+
+	failures = 1
+	require.ErrorContains(t, hb.SendHeartbeat(ctx, id, HeartbeatStatus{Status: WorkerStatusBusy}), "connection refused")
+	require.NoError(t, hb.SendHeartbeat(ctx, id, HeartbeatStatus{Status: WorkerStatusBusy}))
+}
+
+func TestSystemSampler(t *testing.T) {
+	sampler := NewSystemSampler(t.TempDir())
+	usage, err := sampler.Sample(context.Background())
+	if runtime.GOOS != "linux" {
+		require.ErrorIs(t, err, ErrSamplingUnsupported)
+		return
+	}
+
+	require.NoError(t, err)
+	require.Greater(t, usage.Memory, 0.0)
+	require.Greater(t, usage.Disk, 0.0)
+
+	usage, err = sampler.Sample(context.Background())
+	require.NoError(t, err)
+	require.LessOrEqual(t, usage.CPU, 100.0)
+}
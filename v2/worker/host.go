@@ -0,0 +1,103 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// processStart approximates when the worker process started.
+var processStart = time.Now()
+
+// HostMetadata describes the host and process the worker runs in: hostname,
+// first non-loopback IP address, operating system, the main module version and
+// process start time, with the Go version, PID and CPU count in AdditionalInfo.
+func HostMetadata() WorkerMetadata {
+	hostname, _ := os.Hostname()
+
+	md := WorkerMetadata{
+		Hostname:        hostname,
+		IPAddress:       hostIP(),
+		StartTime:       processStart,
+		OperatingSystem: runtime.GOOS + "/" + runtime.GOARCH,
+		AdditionalInfo: map[string]interface{}{
+			"goVersion": runtime.Version(),
+			"pid":       os.Getpid(),
+			"numCPU":    runtime.NumCPU(),
+		},
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		md.Version = info.Main.Version
+	}
+
+	return md
+}
+
+// fillMetadata sets the empty fields of md from host.
+func fillMetadata(md *WorkerMetadata, host WorkerMetadata) {
+	if md.Hostname == "" {
+		md.Hostname = host.Hostname
+	}
+	if md.IPAddress == "" {
+		md.IPAddress = host.IPAddress
+	}
+	if md.Version == "" {
+		md.Version = host.Version
+	}
+	if md.StartTime.IsZero() {
+		md.StartTime = host.StartTime
+	}
+	if md.OperatingSystem == "" {
+		md.OperatingSystem = host.OperatingSystem
+	}
+
+	if md.AdditionalInfo == nil {
+		md.AdditionalInfo = map[string]interface{}{}
+	}
+	for k, v := range host.AdditionalInfo {
+		if _, ok := md.AdditionalInfo[k]; !ok {
+			md.AdditionalInfo[k] = v
+		}
+	}
+}
+
+func hostIP() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+
+	var fallback string
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+		if fallback == "" {
+			fallback = ipNet.IP.String()
+		}
+	}
+
+	return fallback
+}
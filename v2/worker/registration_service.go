@@ -0,0 +1,375 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+var (
+	// ErrWorkerExists is returned by Register when the worker is already registered.
+	ErrWorkerExists = errors.New("worker: worker already registered")
+
+	// ErrInvalidTransition is returned when a worker cannot move to the requested status.
+	ErrInvalidTransition = errors.New("worker: invalid status transition")
+
+	// ErrNotAccepting is returned by AcquireTask when the worker's status does not accept tasks.
+	ErrNotAccepting = errors.New("worker: worker is not accepting tasks")
+
+	// ErrAtCapacity is returned by AcquireTask when the worker runs MaxConcurrentTasks tasks.
+	ErrAtCapacity = errors.New("worker: worker is at capacity")
+
+	// ErrDrainTimeout is returned by Drain when tasks were still running at the deadline.
+	ErrDrainTimeout = errors.New("worker: drain deadline exceeded")
+
+	// ErrDrainCanceled is returned by Drain when the worker leaves the draining status while waiting.
+	ErrDrainCanceled = errors.New("worker: drain canceled")
+)
+
+// statusTransitions lists the statuses each status may move to. Moving to the
+// current status is always allowed.
+var statusTransitions = map[WorkerStatus][]WorkerStatus{
+	WorkerStatusStarting:    {WorkerStatusOnline, WorkerStatusIdle, WorkerStatusError, WorkerStatusStopping, WorkerStatusOffline},
+	WorkerStatusOnline:      {WorkerStatusIdle, WorkerStatusBusy, WorkerStatusDraining, WorkerStatusMaintenance, WorkerStatusError, WorkerStatusStopping, WorkerStatusOffline},
+	WorkerStatusIdle:        {WorkerStatusOnline, WorkerStatusBusy, WorkerStatusDraining, WorkerStatusMaintenance, WorkerStatusError, WorkerStatusStopping, WorkerStatusOffline},
+	WorkerStatusBusy:        {WorkerStatusOnline, WorkerStatusIdle, WorkerStatusDraining, WorkerStatusError, WorkerStatusStopping, WorkerStatusOffline},
+	WorkerStatusDraining:    {WorkerStatusOnline, WorkerStatusIdle, WorkerStatusError, WorkerStatusStopping, WorkerStatusOffline},
+	WorkerStatusMaintenance: {WorkerStatusOnline, WorkerStatusIdle, WorkerStatusStopping, WorkerStatusOffline},
+	WorkerStatusError:       {WorkerStatusStarting, WorkerStatusOnline, WorkerStatusIdle, WorkerStatusMaintenance, WorkerStatusStopping, WorkerStatusOffline},
+	WorkerStatusStopping:    {WorkerStatusOffline},
+	WorkerStatusOffline:     {WorkerStatusStarting},
+}
+
+// CanTransition reports whether a worker may move from one status to another.
+func CanTransition(from, to WorkerStatus) bool {
+	return from == to || slices.Contains(statusTransitions[from], to)
+}
+
+// accepting reports whether a worker in status takes new tasks.
+func accepting(status WorkerStatus) bool {
+	switch status {
+	case WorkerStatusOnline, WorkerStatusIdle, WorkerStatusBusy:
+		return true
+	}
+
+	return false
+}
+
+// RegistrationOption configures a RegistrationService.
+type RegistrationOption func(*RegistrationService)
+
+// WithRegistrationLogger sets the logger for registration operations.
+func WithRegistrationLogger(logger core.Logger) RegistrationOption {
+	return func(s *RegistrationService) { s.logger = logger }
+}
+
+// WithHostMetadata replaces HostMetadata as the source of metadata filled in
+// on registration.
+func WithHostMetadata(fn func() WorkerMetadata) RegistrationOption {
+	return func(s *RegistrationService) { s.host = fn }
+}
+
+// RegistrationService is an in-memory Registration that enforces status
+// transitions and task capacity, and drains workers gracefully.
+type RegistrationService struct {
+	logger core.Logger
+	host   func() WorkerMetadata
+
+	mu      sync.Mutex
+	workers map[xid.ID]*registeredWorker
+}
+
+var _ Registration = (*RegistrationService)(nil)
+
+type registeredWorker struct {
+	info RegistrationInfo
+
+	// changed is closed and replaced whenever CurrentTasks decreases or Status changes
+	changed chan struct{}
+}
+
+func (w *registeredWorker) notify() {
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// NewRegistrationService creates an empty RegistrationService.
+func NewRegistrationService(opts ...RegistrationOption) *RegistrationService {
+	s := &RegistrationService{workers: map[xid.ID]*registeredWorker{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.logger == nil {
+		s.logger = core.NewStructuredLogger()
+	}
+	if s.host == nil {
+		s.host = HostMetadata
+	}
+
+	return s
+}
+
+// Register registers a worker. A zero Status defaults to WorkerStatusStarting,
+// and empty metadata fields are filled in from the host.
+func (s *RegistrationService) Register(ctx context.Context, info RegistrationInfo) error {
+	if info.ID.IsNil() {
+		return errors.New("worker: registration requires an ID")
+	}
+	if info.MaxConcurrentTasks < 0 || info.CurrentTasks < 0 {
+		return errors.New("worker: task counts cannot be negative")
+	}
+	if info.MaxConcurrentTasks > 0 && info.CurrentTasks > info.MaxConcurrentTasks {
+		return fmt.Errorf("%w: %d tasks with a limit of %d", ErrAtCapacity, info.CurrentTasks, info.MaxConcurrentTasks)
+	}
+	if info.Status == "" {
+		info.Status = WorkerStatusStarting
+	}
+	if _, ok := statusTransitions[info.Status]; !ok {
+		return fmt.Errorf("worker: unknown status %q", info.Status)
+	}
+
+	info = cloneInfo(info)
+	fillMetadata(&info.Metadata, s.host())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.workers[info.ID]; ok {
+		return ErrWorkerExists
+	}
+
+	s.workers[info.ID] = &registeredWorker{info: info, changed: make(chan struct{})}
+	s.logger.Info("worker registered", "worker", info.ID.String(), "status", info.Status.String())
+	return nil
+}
+
+func (s *RegistrationService) Unregister(ctx context.Context, workerID xid.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[workerID]
+	if !ok {
+		return ErrWorkerNotFound
+	}
+
+	close(w.changed)
+	delete(s.workers, workerID)
+	s.logger.Info("worker unregistered", "worker", workerID.String())
+	return nil
+}
+
+// UpdateStatus moves a worker to status, or returns ErrInvalidTransition if
+// CanTransition does not allow it.
+func (s *RegistrationService) UpdateStatus(ctx context.Context, workerID xid.ID, status WorkerStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[workerID]
+	if !ok {
+		return ErrWorkerNotFound
+	}
+
+	return s.setStatus(w, status)
+}
+
+func (s *RegistrationService) setStatus(w *registeredWorker, status WorkerStatus) error {
+	if !CanTransition(w.info.Status, status) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, w.info.Status, status)
+	}
+
+	if w.info.Status != status {
+		s.logger.Info("worker status changed", "worker", w.info.ID.String(), "from", w.info.Status.String(), "to", status.String())
+		w.info.Status = status
+		w.notify()
+	}
+
+	return nil
+}
+
+func (s *RegistrationService) UpdateCapabilities(ctx context.Context, workerID xid.ID, capabilities []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[workerID]
+	if !ok {
+		return ErrWorkerNotFound
+	}
+
+	w.info.Capabilities = slices.Clone(capabilities)
+	return nil
+}
+
+// UpdateMetadata replaces a worker's metadata, filling empty fields from the host.
+func (s *RegistrationService) UpdateMetadata(ctx context.Context, workerID xid.ID, metadata WorkerMetadata) error {
+	metadata = cloneMetadata(metadata)
+	fillMetadata(&metadata, s.host())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[workerID]
+	if !ok {
+		return ErrWorkerNotFound
+	}
+
+	w.info.Metadata = metadata
+	return nil
+}
+
+// GetWorkerInfo returns a copy of a worker's registration.
+func (s *RegistrationService) GetWorkerInfo(ctx context.Context, workerID xid.ID) (*RegistrationInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[workerID]
+	if !ok {
+		return nil, ErrWorkerNotFound
+	}
+
+	info := cloneInfo(w.info)
+	return &info, nil
+}
+
+// AcquireTask records that a worker started a task. It fails with
+// ErrNotAccepting unless the worker is online, idle or busy, and with
+// ErrAtCapacity if it already runs MaxConcurrentTasks tasks; a zero
+// MaxConcurrentTasks means no limit. An online or idle worker becomes busy.
+func (s *RegistrationService) AcquireTask(ctx context.Context, workerID xid.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[workerID]
+	if !ok {
+		return ErrWorkerNotFound
+	}
+
+	if !accepting(w.info.Status) {
+		return fmt.Errorf("%w: status is %s", ErrNotAccepting, w.info.Status)
+	}
+	if w.info.MaxConcurrentTasks > 0 && w.info.CurrentTasks >= w.info.MaxConcurrentTasks {
+		return ErrAtCapacity
+	}
+
+	w.info.CurrentTasks++
+	return s.setStatus(w, WorkerStatusBusy)
+}
+
+// ReleaseTask records that a worker finished a task. A busy worker with no
+// tasks left becomes idle.
+func (s *RegistrationService) ReleaseTask(ctx context.Context, workerID xid.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.workers[workerID]
+	if !ok {
+		return ErrWorkerNotFound
+	}
+	if w.info.CurrentTasks == 0 {
+		return errors.New("worker: no task to release")
+	}
+
+	w.info.CurrentTasks--
+	w.notify()
+
+	if w.info.CurrentTasks == 0 && w.info.Status == WorkerStatusBusy {
+		return s.setStatus(w, WorkerStatusIdle)
+	}
+
+	return nil
+}
+
+// Drain stops a worker accepting tasks, waits up to timeout for its running
+// tasks to finish and then moves it offline. If tasks are still running at
+// the deadline the worker goes offline anyway and ErrDrainTimeout is
+// returned. A non-positive timeout waits until ctx is done. Moving the worker
+// out of the draining status while Drain waits cancels it with ErrDrainCanceled.
+func (s *RegistrationService) Drain(ctx context.Context, workerID xid.ID, timeout time.Duration) error {
+	s.mu.Lock()
+	w, ok := s.workers[workerID]
+	if !ok {
+		s.mu.Unlock()
+		return ErrWorkerNotFound
+	}
+	if err := s.setStatus(w, WorkerStatusDraining); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.mu.Unlock()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for {
+		s.mu.Lock()
+		current, ok := s.workers[workerID]
+		if !ok {
+			s.mu.Unlock()
+			return ErrWorkerNotFound
+		}
+		if current.info.Status != WorkerStatusDraining {
+			s.mu.Unlock()
+			return fmt.Errorf("%w: status changed to %s", ErrDrainCanceled, current.info.Status)
+		}
+
+		if current.info.CurrentTasks == 0 || ctx.Err() != nil {
+			remaining := current.info.CurrentTasks
+			err := s.setStatus(current, WorkerStatusOffline)
+			s.mu.Unlock()
+
+			if err == nil && remaining > 0 {
+				err = fmt.Errorf("%w: %d tasks still running", ErrDrainTimeout, remaining)
+			}
+			return err
+		}
+
+		changed := current.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+		}
+	}
+}
+
+func (s *RegistrationService) Logger() core.Logger {
+	return s.logger
+}
+
+func cloneInfo(info RegistrationInfo) RegistrationInfo {
+	info.Capabilities = slices.Clone(info.Capabilities)
+	info.Specializations = slices.Clone(info.Specializations)
+	info.Metadata = cloneMetadata(info.Metadata)
+	return info
+}
+
+func cloneMetadata(md WorkerMetadata) WorkerMetadata {
+	md.Tags = slices.Clone(md.Tags)
+	md.AdditionalInfo = maps.Clone(md.AdditionalInfo)
+	return md
+}
@@ -0,0 +1,107 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrationLifecycle(t *testing.T) {
+	reg := NewRegistrationService(WithHostMetadata(func() WorkerMetadata {
+		return WorkerMetadata{Hostname: "node-1", Version: "v1.2.3", OperatingSystem: "linux/amd64"}
+	}))
+	ctx := context.Background()
+	id := xid.New()
+
+	require.NoError(t, reg.Register(ctx, RegistrationInfo{ID: id, MaxConcurrentTasks: 2, Metadata: WorkerMetadata{Version: "v2.0.0"}}))
+	require.ErrorIs(t, reg.Register(ctx, RegistrationInfo{ID: id}), ErrWorkerExists)
+
+	info, err := reg.GetWorkerInfo(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, WorkerStatusStarting, info.Status)
+	require.Equal(t, "node-1", info.Metadata.Hostname)
+	require.Equal(t, "v2.0.0", info.Metadata.Version)
+
+	require.ErrorIs(t, reg.AcquireTask(ctx, id), ErrNotAccepting)
+	require.ErrorIs(t, reg.UpdateStatus(ctx, id, WorkerStatusDraining), ErrInvalidTransition)
+	require.NoError(t, reg.UpdateStatus(ctx, id, WorkerStatusOnline))
+
+	require.NoError(t, reg.AcquireTask(ctx, id))
+	require.NoError(t, reg.AcquireTask(ctx, id))
+	require.ErrorIs(t, reg.AcquireTask(ctx, id), ErrAtCapacity)
+
+	info, _ = reg.GetWorkerInfo(ctx, id)
+	require.Equal(t, WorkerStatusBusy, info.Status)
+	require.Equal(t, 2, info.CurrentTasks)
+
+	require.NoError(t, reg.ReleaseTask(ctx, id))
+	require.NoError(t, reg.ReleaseTask(ctx, id))
+	info, _ = reg.GetWorkerInfo(ctx, id)
+	require.Equal(t, WorkerStatusIdle, info.Status)
+
+	require.NoError(t, reg.UpdateCapabilities(ctx, id, []string{"http"}))
+	info, _ = reg.GetWorkerInfo(ctx, id)
+	info.Capabilities[0] = "mutated"
+	info, _ = reg.GetWorkerInfo(ctx, id)
+	require.Equal(t, []string{"http"}, info.Capabilities)
+
+	require.NoError(t, reg.Unregister(ctx, id))
+	_, err = reg.GetWorkerInfo(ctx, id)
+	require.ErrorIs(t, err, ErrWorkerNotFound)
+}
+
+func TestRegistrationDrain(t *testing.T) {
+	reg := NewRegistrationService()
+	ctx := context.Background()
+	id := xid.New()
+
+	require.NoError(t, reg.Register(ctx, RegistrationInfo{ID: id, Status: WorkerStatusOnline}))
+	require.NotEmpty(t, func() string { info, _ := reg.GetWorkerInfo(ctx, id); return info.Metadata.Hostname }())
+	require.NoError(t, reg.AcquireTask(ctx, id))
+	require.NoError(t, reg.AcquireTask(ctx, id))
+
+	drained := make(chan error, 1)
+	go func() { drained <- reg.Drain(ctx, id, time.Minute) }()
+
+	require.Eventually(t, func() bool {
+		return reg.AcquireTask(ctx, id) != nil
+	}, time.Second, time.Millisecond)
+	require.ErrorIs(t, reg.AcquireTask(ctx, id), ErrNotAccepting)
+
+	require.NoError(t, reg.ReleaseTask(ctx, id))
+	select {
+	case err := <-drained:
+		t.Fatalf("drain finished with a task running: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	require.NoError(t, reg.ReleaseTask(ctx, id))
+	require.NoError(t, <-drained)
+	info, _ := reg.GetWorkerInfo(ctx, id)
+	require.Equal(t, WorkerStatusOffline, info.Status)
+
+	// A drain that hits its deadline still takes the worker offline.
+	other := xid.New()
+	require.NoError(t, reg.Register(ctx, RegistrationInfo{ID: other, Status: WorkerStatusOnline}))
+	require.NoError(t, reg.AcquireTask(ctx, other))
+	require.ErrorIs(t, reg.Drain(ctx, other, 10*time.Millisecond), ErrDrainTimeout)
+	info, _ = reg.GetWorkerInfo(ctx, other)
+	require.Equal(t, WorkerStatusOffline, info.Status)
+}
@@ -0,0 +1,320 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rs/xid"
+	"github.com/wakflo/go-sdk/v2/core"
+)
+
+// WildcardValue is a capability value that matches every value of its type.
+const WildcardValue = "*"
+
+// CapacityDiscount is the share of a worker's score lost as it approaches
+// MaxConcurrentTasks: a worker running half its limit keeps 75% of its score.
+const CapacityDiscount = 0.5
+
+// SpecializationOption configures a SpecializationService.
+type SpecializationOption func(*SpecializationService)
+
+// WithLiveness restricts FindMatchingWorkers to workers alive reports true
+// for. Workers it reports as ErrWorkerNotFound are treated as dead.
+func WithLiveness(alive func(ctx context.Context, workerID xid.ID) (bool, error)) SpecializationOption {
+	return func(s *SpecializationService) { s.alive = alive }
+}
+
+// WithHeartbeat restricts FindMatchingWorkers to workers h reports alive.
+func WithHeartbeat(h Heartbeat) SpecializationOption {
+	return WithLiveness(h.IsAlive)
+}
+
+// WithWorkerLoad sets the function reporting how many tasks a worker runs and
+// its limit, which FindMatchingWorkers uses to discount busy workers and skip
+// full ones. A zero limit means no limit. Without a load function the service
+// does not know how busy a worker is, so no worker is discounted or skipped,
+// whatever the MaxConcurrent of its capabilities.
+func WithWorkerLoad(load func(ctx context.Context, workerID xid.ID) (current, limit int, err error)) SpecializationOption {
+	return func(s *SpecializationService) { s.load = load }
+}
+
+// WithRegistration reads worker load from the CurrentTasks and
+// MaxConcurrentTasks of r's registrations.
+func WithRegistration(r Registration) SpecializationOption {
+	return WithWorkerLoad(func(ctx context.Context, workerID xid.ID) (int, int, error) {
+		info, err := r.GetWorkerInfo(ctx, workerID)
+		if err != nil {
+			return 0, 0, err
+		}
+		return info.CurrentTasks, info.MaxConcurrentTasks, nil
+	})
+}
+
+// WithSpecializationLogger sets the logger for specialization operations.
+func WithSpecializationLogger(logger core.Logger) SpecializationOption {
+	return func(s *SpecializationService) { s.logger = logger }
+}
+
+// SpecializationService is an in-memory Specialization with a deterministic
+// scorer.
+//
+// A worker's score is the priority-weighted mean of its confidence in each
+// requirement, so it ranges from 0 to 1. Every requirement weighs its
+// Priority, or 1 if that is lower. A requirement is met by the capability of
+// the same type whose value matches it case-insensitively or is WildcardValue,
+// taking the highest confidence if several do. Workers missing a required
+// specialization are never returned by FindMatchingWorkers.
+type SpecializationService struct {
+	alive  func(ctx context.Context, workerID xid.ID) (bool, error)
+	load   func(ctx context.Context, workerID xid.ID) (current, limit int, err error)
+	logger core.Logger
+
+	mu           sync.RWMutex
+	capabilities map[xid.ID][]SpecializationCapability
+}
+
+var _ Specialization = (*SpecializationService)(nil)
+
+// NewSpecializationService creates an empty SpecializationService.
+func NewSpecializationService(opts ...SpecializationOption) *SpecializationService {
+	s := &SpecializationService{capabilities: map[xid.ID][]SpecializationCapability{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.logger == nil {
+		s.logger = core.NewStructuredLogger()
+	}
+
+	return s
+}
+
+func (s *SpecializationService) RegisterCapabilities(ctx context.Context, workerID xid.ID, capabilities []SpecializationCapability) error {
+	if err := validateCapabilities(capabilities); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.capabilities[workerID]; ok {
+		return ErrWorkerExists
+	}
+
+	s.capabilities[workerID] = slices.Clone(capabilities)
+	return nil
+}
+
+func (s *SpecializationService) UpdateCapabilities(ctx context.Context, workerID xid.ID, capabilities []SpecializationCapability) error {
+	if err := validateCapabilities(capabilities); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.capabilities[workerID]; !ok {
+		return ErrWorkerNotFound
+	}
+
+	s.capabilities[workerID] = slices.Clone(capabilities)
+	return nil
+}
+
+func (s *SpecializationService) GetCapabilities(ctx context.Context, workerID xid.ID) ([]SpecializationCapability, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	capabilities, ok := s.capabilities[workerID]
+	if !ok {
+		return nil, ErrWorkerNotFound
+	}
+
+	return slices.Clone(capabilities), nil
+}
+
+// RemoveWorker forgets a worker's capabilities.
+func (s *SpecializationService) RemoveWorker(ctx context.Context, workerID xid.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.capabilities, workerID)
+	return nil
+}
+
+// FindMatchingWorkers returns the alive workers that have every required
+// specialization and are below their task limit, best match first. A worker's
+// limit is the lower of the one reported by the load function and the
+// MaxConcurrent of the capabilities meeting the requirements. Scores are
+// discounted by CapacityDiscount times the share of the limit in use; ties are
+// broken by worker ID.
+func (s *SpecializationService) FindMatchingWorkers(ctx context.Context, requirements []SpecializationRequirement) ([]SpecializationMatch, error) {
+	s.mu.RLock()
+	workers := make(map[xid.ID][]SpecializationCapability, len(s.capabilities))
+	for id, capabilities := range s.capabilities {
+		workers[id] = capabilities
+	}
+	s.mu.RUnlock()
+
+	var matches []SpecializationMatch
+	for id, capabilities := range workers {
+		match, err := s.CalculateMatchScore(capabilities, requirements)
+		if err != nil {
+			return nil, err
+		}
+		if len(match.MissingRequirements) > 0 {
+			continue
+		}
+
+		if s.alive != nil {
+			alive, err := s.alive(ctx, id)
+			if err != nil && !errors.Is(err, ErrWorkerNotFound) {
+				return nil, fmt.Errorf("worker: liveness of %s: %w", id, err)
+			}
+			if !alive {
+				continue
+			}
+		}
+
+		if s.load != nil {
+			current, limit, err := s.load(ctx, id)
+			if errors.Is(err, ErrWorkerNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("worker: load of %s: %w", id, err)
+			}
+
+			if capLimit := capacityLimit(capabilities, requirements); capLimit > 0 && (limit <= 0 || capLimit < limit) {
+				limit = capLimit
+			}
+
+			if limit > 0 {
+				if current >= limit {
+					continue
+				}
+				match.MatchScore *= 1 - CapacityDiscount*float64(current)/float64(limit)
+			}
+		}
+
+		match.WorkerID = id
+		matches = append(matches, *match)
+	}
+
+	slices.SortFunc(matches, func(a, b SpecializationMatch) int {
+		if c := cmp.Compare(b.MatchScore, a.MatchScore); c != 0 {
+			return c
+		}
+		return a.WorkerID.Compare(b.WorkerID)
+	})
+
+	return matches, nil
+}
+
+// CalculateMatchScore scores capabilities against requirements. MatchDetails
+// maps each requirement, as "type:value", to the confidence it was met with,
+// and MissingRequirements lists the required ones that were not met. With no
+// requirements every worker scores 1.
+func (s *SpecializationService) CalculateMatchScore(capabilities []SpecializationCapability, requirements []SpecializationRequirement) (*SpecializationMatch, error) {
+	match := &SpecializationMatch{MatchScore: 1, MatchDetails: map[string]float64{}}
+	if len(requirements) == 0 {
+		return match, nil
+	}
+
+	var total, weights float64
+	for _, req := range requirements {
+		if req.Value == "" {
+			return nil, fmt.Errorf("worker: %s requirement has no value", req.Type)
+		}
+
+		best, ok := bestCapability(capabilities, req)
+		confidence := best.Confidence
+		if !ok && req.IsRequired {
+			match.MissingRequirements = append(match.MissingRequirements, req)
+		}
+
+		weight := float64(max(req.Priority, 1))
+		total += weight * confidence
+		weights += weight
+		match.MatchDetails[string(req.Type)+":"+req.Value] = confidence
+	}
+
+	match.MatchScore = total / weights
+	if len(match.MissingRequirements) > 0 {
+		match.MatchScore = 0
+	}
+
+	return match, nil
+}
+
+func (s *SpecializationService) Logger() core.Logger {
+	return s.logger
+}
+
+// bestCapability returns the capability meeting req with the highest confidence.
+func bestCapability(capabilities []SpecializationCapability, req SpecializationRequirement) (SpecializationCapability, bool) {
+	var best SpecializationCapability
+	found := false
+	for _, c := range capabilities {
+		if c.Type != req.Type || (c.Value != WildcardValue && !strings.EqualFold(c.Value, req.Value)) {
+			continue
+		}
+
+		if !found || c.Confidence > best.Confidence {
+			best, found = c, true
+		}
+	}
+
+	return best, found
+}
+
+// capacityLimit returns the lowest positive MaxConcurrent of the capabilities
+// meeting requirements, or 0 if none sets one.
+func capacityLimit(capabilities []SpecializationCapability, requirements []SpecializationRequirement) int {
+	limit := 0
+	for _, req := range requirements {
+		if c, ok := bestCapability(capabilities, req); ok && c.MaxConcurrent > 0 && (limit == 0 || c.MaxConcurrent < limit) {
+			limit = c.MaxConcurrent
+		}
+	}
+
+	return limit
+}
+
+func validateCapabilities(capabilities []SpecializationCapability) error {
+	for _, c := range capabilities {
+		if !slices.Contains(c.Type.Values(), string(c.Type)) {
+			return fmt.Errorf("worker: unknown specialization type %q", c.Type)
+		}
+		if c.Value == "" {
+			return fmt.Errorf("worker: %s capability has no value", c.Type)
+		}
+		if c.Confidence < 0 || c.Confidence > 1 {
+			return fmt.Errorf("worker: confidence of %s:%s must be between 0 and 1", c.Type, c.Value)
+		}
+		if c.MaxConcurrent < 0 {
+			return fmt.Errorf("worker: max concurrent of %s:%s cannot be negative", c.Type, c.Value)
+		}
+	}
+
+	return nil
+}
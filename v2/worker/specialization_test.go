@@ -0,0 +1,123 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateMatchScore(t *testing.T) {
+	s := NewSpecializationService()
+	requirements := []SpecializationRequirement{
+		{Type: SpecializationTypeIntegration, Value: "slack", Priority: 3, IsRequired: true},
+		{Type: SpecializationTypeRegion, Value: "eu-west", Priority: 1},
+	}
+
+	match, err := s.CalculateMatchScore([]SpecializationCapability{
+		{Type: SpecializationTypeIntegration, Value: "Slack", Confidence: 0.8},
+		{Type: SpecializationTypeRegion, Value: WildcardValue, Confidence: 0.4},
+	}, requirements)
+	require.NoError(t, err)
+	require.InDelta(t, (3*0.8+0.4)/4, match.MatchScore, 1e-9)
+	require.Equal(t, map[string]float64{"integration:slack": 0.8, "region:eu-west": 0.4}, match.MatchDetails)
+	require.Empty(t, match.MissingRequirements)
+
+	match, err = s.CalculateMatchScore([]SpecializationCapability{
+		{Type: SpecializationTypeRegion, Value: "eu-west", Confidence: 1},
+	}, requirements)
+	require.NoError(t, err)
+	require.Zero(t, match.MatchScore)
+	require.Equal(t, requirements[:1], match.MissingRequirements)
+}
+
+func TestFindMatchingWorkers(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	store := NewMemoryHeartbeatStore(WithStoreClock(clock.Now))
+	reg := NewRegistrationService()
+	s := NewSpecializationService(WithLiveness(store.IsAlive), WithRegistration(reg))
+
+	expert, busyExpert, novice, generalist, dead, full := xid.New(), xid.New(), xid.New(), xid.New(), xid.New(), xid.New()
+	slack := func(confidence float64) []SpecializationCapability {
+		return []SpecializationCapability{{Type: SpecializationTypeIntegration, Value: "slack", Confidence: confidence}}
+	}
+
+	for id, capabilities := range map[xid.ID][]SpecializationCapability{
+		expert:     slack(0.9),
+		busyExpert: slack(0.9),
+		novice:     slack(0.5),
+		generalist: {{Type: SpecializationTypeRegion, Value: "eu-west", Confidence: 1}},
+		dead:       slack(1),
+		full:       slack(1),
+	} {
+		require.NoError(t, s.RegisterCapabilities(ctx, id, capabilities))
+		require.NoError(t, reg.Register(ctx, RegistrationInfo{ID: id, Status: WorkerStatusOnline, MaxConcurrentTasks: 2}))
+		require.NoError(t, store.Record(ctx, HeartbeatRecord{WorkerID: id, Timeout: time.Minute}))
+	}
+
+	require.NoError(t, reg.AcquireTask(ctx, busyExpert))
+	require.NoError(t, reg.AcquireTask(ctx, full))
+	require.NoError(t, reg.AcquireTask(ctx, full))
+
+	clock.Advance(2 * time.Minute)
+	for _, id := range []xid.ID{expert, busyExpert, novice, generalist, full} {
+		require.NoError(t, store.Record(ctx, HeartbeatRecord{WorkerID: id, Timeout: time.Minute}))
+	}
+
+	matches, err := s.FindMatchingWorkers(ctx, []SpecializationRequirement{
+		{Type: SpecializationTypeIntegration, Value: "slack", Priority: 2, IsRequired: true},
+	})
+	require.NoError(t, err)
+
+	var ids []xid.ID
+	for _, m := range matches {
+		ids = append(ids, m.WorkerID)
+	}
+	require.Equal(t, []xid.ID{expert, busyExpert, novice}, ids)
+	require.InDelta(t, 0.9*0.75, matches[1].MatchScore, 1e-9)
+
+	require.Error(t, s.RegisterCapabilities(ctx, xid.New(), []SpecializationCapability{{Type: "gpu", Value: "a100"}}))
+	require.ErrorIs(t, s.RegisterCapabilities(ctx, expert, nil), ErrWorkerExists)
+}
+
+func TestFindMatchingWorkersCapabilityLimit(t *testing.T) {
+	ctx := context.Background()
+	idle, nearCapacity, atCapability := xid.New(), xid.New(), xid.New()
+	running := map[xid.ID]int{nearCapacity: 3, atCapability: 2}
+	s := NewSpecializationService(WithWorkerLoad(func(_ context.Context, id xid.ID) (int, int, error) {
+		return running[id], 0, nil
+	}))
+
+	slack := func(maxConcurrent int) []SpecializationCapability {
+		return []SpecializationCapability{{Type: SpecializationTypeIntegration, Value: "slack", Confidence: 0.8, MaxConcurrent: maxConcurrent}}
+	}
+	require.NoError(t, s.RegisterCapabilities(ctx, idle, slack(4)))
+	require.NoError(t, s.RegisterCapabilities(ctx, nearCapacity, slack(4)))
+	require.NoError(t, s.RegisterCapabilities(ctx, atCapability, slack(2)))
+
+	matches, err := s.FindMatchingWorkers(ctx, []SpecializationRequirement{
+		{Type: SpecializationTypeIntegration, Value: "slack", Priority: 1, IsRequired: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	require.Equal(t, idle, matches[0].WorkerID)
+	require.Equal(t, nearCapacity, matches[1].WorkerID)
+	require.InDelta(t, 0.8*(1-CapacityDiscount*3/4), matches[1].MatchScore, 1e-9)
+}
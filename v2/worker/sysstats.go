@@ -0,0 +1,49 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worker
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSamplingUnsupported is returned by the default SystemSampler on
+// platforms where it cannot read process statistics.
+var ErrSamplingUnsupported = errors.New("worker: system sampling is not supported on this platform")
+
+// SystemUsage is a sample of the resources used by the worker process.
+type SystemUsage struct {
+	// CPU is the process CPU usage since the previous sample as a percentage of all cores (0-100)
+	CPU float64
+
+	// Memory is the process resident memory as a percentage of system memory (0-100)
+	Memory float64
+
+	// Disk is the used space of the filesystem holding the sampled path (0-100)
+	Disk float64
+}
+
+// SystemSampler samples the resources used by the worker process.
+type SystemSampler interface {
+	// Sample returns the current resource usage
+	Sample(ctx context.Context) (SystemUsage, error)
+}
+
+// NewSystemSampler returns the default SystemSampler for this platform, which
+// measures disk usage of the filesystem holding diskPath. On Linux it reads
+// /proc; elsewhere Sample returns ErrSamplingUnsupported.
+func NewSystemSampler(diskPath string) SystemSampler {
+	return newSystemSampler(diskPath)
+}
@@ -0,0 +1,164 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// clockTicks is the USER_HZ unit of /proc CPU times, which is 100 on every
+// mainstream Linux architecture.
+const clockTicks = 100
+
+type procSampler struct {
+	diskPath string
+
+	mu       sync.Mutex
+	lastCPU  time.Duration
+	lastWall time.Time
+}
+
+func newSystemSampler(diskPath string) SystemSampler {
+	if diskPath == "" {
+		diskPath = "/"
+	}
+
+	return &procSampler{diskPath: diskPath}
+}
+
+func (p *procSampler) Sample(ctx context.Context) (SystemUsage, error) {
+	var usage SystemUsage
+
+	cpu, err := processCPUTime()
+	if err != nil {
+		return usage, err
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	if !p.lastWall.IsZero() {
+		if wall := now.Sub(p.lastWall); wall > 0 {
+			usage.CPU = clampPercent(float64(cpu-p.lastCPU) / float64(wall) / float64(runtime.NumCPU()) * 100)
+		}
+	}
+	p.lastCPU, p.lastWall = cpu, now
+	p.mu.Unlock()
+
+	if usage.Memory, err = memoryPercent(); err != nil {
+		return usage, err
+	}
+
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(p.diskPath, &fs); err != nil {
+		return usage, fmt.Errorf("worker: statfs %s: %w", p.diskPath, err)
+	}
+	if fs.Blocks > 0 {
+		usage.Disk = clampPercent(float64(fs.Blocks-fs.Bavail) / float64(fs.Blocks) * 100)
+	}
+
+	return usage, nil
+}
+
+// processCPUTime returns the user and system CPU time of this process.
+func processCPUTime() (time.Duration, error) {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return 0, err
+	}
+
+	// The command name may contain spaces, so fields are counted after its
+	// closing parenthesis; utime and stime are fields 14 and 15.
+	fields := strings.Fields(string(data[bytes.LastIndexByte(data, ')')+1:]))
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("worker: unexpected /proc/self/stat format")
+	}
+
+	utime, err := strconv.ParseInt(fields[11], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	stime, err := strconv.ParseInt(fields[12], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(utime+stime) * time.Second / clockTicks, nil
+}
+
+// memoryPercent returns the resident memory of this process as a percentage
+// of total system memory.
+func memoryPercent() (float64, error) {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("worker: unexpected /proc/self/statm format")
+	}
+
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	total, err := memTotal()
+	if err != nil || total == 0 {
+		return 0, err
+	}
+
+	return clampPercent(float64(pages*uint64(os.Getpagesize())) / float64(total) * 100), nil
+}
+
+// memTotal returns MemTotal from /proc/meminfo in bytes.
+func memTotal() (uint64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			return kb * 1024, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("worker: MemTotal not found in /proc/meminfo")
+}
+
+func clampPercent(v float64) float64 {
+	return min(max(v, 0), 100)
+}
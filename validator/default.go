@@ -17,12 +17,12 @@
 package validator
 
 import (
+	"encoding/json"
 	errors2 "errors"
 	"fmt"
 	"strconv"
 	"strings"
 
-	"encore.dev/beta/errs"
 	v10Validator "github.com/go-playground/validator/v10"
 	"github.com/hashicorp/go-multierror"
 	"github.com/wakflo/go-sdk/errors"
@@ -38,74 +38,94 @@ const (
 	DurationErr   = "Invalid duration. Durations must be in the format <number><unit>, where unit is one of: 's', 'm', 'h', 'd', 'w', 'M', 'y'"
 )
 
-// type APIErrors gen.APIErrors
-
-// func (a *APIErrors) String() string {
-//	var sb strings.Builder
-//
-//	sb.WriteString("Validation failed with the following errors:\n")
-//
-//	for i, err := range a.Errors {
-//		sb.WriteString(fmt.Sprintf("%d: %s\n", i, err.Description))
-//	}
-//
-//	return sb.String()
-// }
-
 // Validator will validate the fields for a request object to ensure that
 // the request is well-formed. For example, it searches for required fields
 // or verifies that fields are of a semantic type (like email)
 type Validator interface {
-	// Validate accepts a generic struct for validating. It returns a request
-	// error that is meant to be shown to the end user as a readable string.
+	// Validate accepts a generic struct for validating. It returns
+	// ValidationErrors describing every field that failed, each with a
+	// message that is safe to show to the end user.
 	Validate(s interface{}) error
+}
 
-	ValidateAPI(s interface{}) error
+// FieldLevel gives a validation function access to the field being validated.
+type FieldLevel = v10Validator.FieldLevel
+
+// Func reports whether a field satisfies a custom tag.
+type Func func(fl FieldLevel) bool
+
+// Option configures a DefaultValidator.
+type Option func(*DefaultValidator) error
+
+// WithValidation registers a custom tag. See DefaultValidator.RegisterValidation.
+func WithValidation(tag string, fn Func, message string) Option {
+	return func(v *DefaultValidator) error { return v.RegisterValidation(tag, fn, message) }
 }
 
 // DefaultValidator uses the go-playground v10 validator for verifying that
-// request objects are well-formed
+// request objects are well-formed, with a user-facing message per tag
 type DefaultValidator struct {
-	v10 *v10Validator.Validate
+	v10      *v10Validator.Validate
+	messages map[string]string
 }
 
 // NewDefaultValidator returns a Validator constructed from the go-playground v10
-// validator
-func NewDefaultValidator() Validator {
-	return &DefaultValidator{newValidator()}
-}
+// validator, with the built-in tags (spiderName, password, uuid, cron, actionid,
+// semver, json and duration) and any custom tags given as options. It panics if
+// an option fails, since tags are registered at startup; use
+// NewDefaultValidatorE to handle the error instead.
+func NewDefaultValidator(opts ...Option) Validator {
+	v, err := NewDefaultValidatorE(opts...)
+	if err != nil {
+		panic(err)
+	}
 
-func (v *DefaultValidator) ValidateAPI(s interface{}) error {
-	err := v.v10.Struct(s)
+	return v
+}
 
-	if err == nil {
-		return nil
+// NewDefaultValidatorE is NewDefaultValidator returning an option's error
+// instead of panicking.
+func NewDefaultValidatorE(opts ...Option) (Validator, error) {
+	v := &DefaultValidator{
+		v10: newValidator(),
+		messages: map[string]string{
+			"email":      EmailErr,
+			"spiderName": SpiderNameErr,
+			"uuid":       UUIDErr,
+			"actionid":   ActionIDErr,
+			"cron":       CronErr,
+			"duration":   DurationErr,
+		},
 	}
 
-	// translate all validator errors
-	errList, ok := err.(v10Validator.ValidationErrors)
-
-	if !ok {
-		return errors.NewErrInternal(fmt.Errorf("could not cast err to validator.ValidationErrors, type %T", err))
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
 	}
 
-	if len(errList) == 0 {
-		return nil
+	return v, nil
+}
+
+// RegisterValidation adds a custom tag, or replaces a built-in one. message
+// describes the failure to end users; empty means a generic message naming
+// the condition.
+func (v *DefaultValidator) RegisterValidation(tag string, fn Func, message string) error {
+	if err := v.v10.RegisterValidation(tag, v10Validator.Func(fn)); err != nil {
+		return fmt.Errorf("validator: register %q: %w", tag, err)
 	}
 
-	// convert all validator errors to error strings
-	apiError := errs.B().Code(errs.InvalidArgument).Msg("Validation Error")
-	for _, field := range errList {
-		errObj := NewValidationErrObject(field)
-		fieldStr := strings.ToLower(errObj.Field)
-		apiError = apiError.Meta(fieldStr, field.Error())
+	if message == "" {
+		delete(v.messages, tag)
+	} else {
+		v.messages[tag] = message
 	}
 
-	return apiError.Err()
+	return nil
 }
 
 // Validate uses the go-playground v10 validator and checks struct fields against
-// a `form:"<validator>"` tag.
+// a `validate:"<validator>"` tag. Failures are returned as ValidationErrors.
 func (v *DefaultValidator) Validate(s interface{}) error {
 	err := v.v10.Struct(s)
 
@@ -120,37 +140,53 @@ func (v *DefaultValidator) Validate(s interface{}) error {
 		return errors.NewErrInternal(fmt.Errorf("could not cast err to validator.ValidationErrors, type %T", err))
 	}
 
-	// convert all validator errors to error strings
-	errorStrs := make([]string, len(errs))
-
+	out := make(ValidationErrors, len(errs))
 	for i, field := range errs {
-		errObj := NewValidationErrObject(field)
-
-		errorStrs[i] = getErrorStr(errObj)
+		out[i] = NewValidationErrObject(field)
+		out[i].Message = v.message(out[i])
 	}
 
-	return NewErrFailedRequestValidation(errorStrs...)
+	return out
 }
 
-func getErrorStr(errObj *ValidationErrObject) string {
-	switch strings.ToLower(errObj.Condition) {
-	case "password":
+func (v *DefaultValidator) message(errObj *ValidationErrObject) string {
+	// never echo a rejected password
+	if errObj.Condition == "password" {
 		return PasswordErr
-	case "email":
-		return errObj.SafeExternalError(EmailErr)
-	case "spiderName":
-		return errObj.SafeExternalError(SpiderNameErr)
-	case "uuid":
-		return errObj.SafeExternalError(UUIDErr)
-	case "actionid":
-		return errObj.SafeExternalError(ActionIDErr)
-	case "cron":
-		return errObj.SafeExternalError(CronErr)
-	case "duration":
-		return errObj.SafeExternalError(DurationErr)
-	default:
-		return errObj.SafeExternalError("")
 	}
+
+	return errObj.SafeExternalError(v.messages[errObj.Condition])
+}
+
+// ValidationErrors is returned by DefaultValidator.Validate, with one entry
+// per failed field.
+type ValidationErrors []*ValidationErrObject
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, obj := range e {
+		msgs[i] = obj.Message
+		if msgs[i] == "" {
+			msgs[i] = obj.SafeExternalError("")
+		}
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Is matches errors.ErrValidation.
+func (e ValidationErrors) Is(target error) bool {
+	return target == errors.ErrValidation
+}
+
+// Fields returns the message of each failed field keyed by namespace.
+func (e ValidationErrors) Fields() map[string]string {
+	out := make(map[string]string, len(e))
+	for _, obj := range e {
+		out[obj.Namespace] = obj.Message
+	}
+
+	return out
 }
 
 func NewErrFailedRequestValidation(valErrors ...string) error {
@@ -190,6 +226,21 @@ type ValidationErrObject struct {
 
 	// ActualValue is the actual value of the field that failed validation.
 	ActualValue interface{}
+
+	// Message is a description of the failure that is safe to show to end users
+	Message string
+}
+
+// MarshalJSON encodes the error with its SafeValue in place of ActualValue.
+func (obj *ValidationErrObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Field     string `json:"field"`
+		Namespace string `json:"namespace"`
+		Condition string `json:"condition"`
+		Param     string `json:"param,omitempty"`
+		Value     string `json:"value"`
+		Message   string `json:"message"`
+	}{obj.Field, obj.Namespace, obj.Condition, obj.Param, obj.SafeValue(), obj.Message})
 }
 
 // NewValidationErrObject simply returns a ValidationErrObject from a go-playground v10
@@ -224,13 +275,15 @@ func (obj *ValidationErrObject) SafeExternalError(suffix string) string {
 	sb.WriteString(fmt.Sprintf("validation failed on field '%s': %s", obj.Namespace, suffix))
 
 	if obj.Param != "" {
-		sb.WriteString(fmt.Sprintf(" [ %s ]: got %s", obj.Param, obj.getActualValueString()))
+		sb.WriteString(fmt.Sprintf(" [ %s ]: got %s", obj.Param, obj.SafeValue()))
 	}
 
 	return sb.String()
 }
 
-func (obj *ValidationErrObject) getActualValueString() string {
+// SafeValue returns ActualValue as a string if it is of a type that is safe to
+// show externally (see SafeExternalError), and "invalid type" otherwise.
+func (obj *ValidationErrObject) SafeValue() string {
 	// we translate to "json-readable" form for nil values, since clients may not be Golang
 	if obj.ActualValue == nil {
 		return "null"
@@ -0,0 +1,71 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkerrors "github.com/wakflo/go-sdk/errors"
+)
+
+type stepResource struct {
+	Name   string `validate:"spiderName"`
+	Action string `validate:"actionid"`
+	Size   int    `validate:"oneof=1 2"`
+}
+
+func TestDefaultValidatorFieldErrors(t *testing.T) {
+	err := NewDefaultValidator().Validate(&stepResource{Name: "&&!!", Action: "slack", Size: 3})
+	require.ErrorIs(t, err, sdkerrors.ErrValidation)
+
+	var valErrs ValidationErrors
+	require.True(t, errors.As(err, &valErrs))
+	require.Len(t, valErrs, 3)
+
+	require.Equal(t, "stepResource.Name", valErrs[0].Namespace)
+	require.Equal(t, "spiderName", valErrs[0].Condition)
+	require.Contains(t, valErrs[0].Message, SpiderNameErr)
+	require.Contains(t, valErrs[1].Message, ActionIDErr)
+	require.Equal(t, "1 2", valErrs[2].Param)
+	require.Equal(t, "3", valErrs[2].SafeValue())
+
+	raw, err := json.Marshal(valErrs[2])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"field":"Size","namespace":"stepResource.Size","condition":"oneof","param":"1 2","value":"3",
+		"message":"validation failed on field 'stepResource.Size': on condition 'oneof' [ 1 2 ]: got 3"}`, string(raw))
+
+	require.NoError(t, NewDefaultValidator().Validate(&stepResource{Name: "ok", Action: "slack:send", Size: 1}))
+}
+
+func TestDefaultValidatorCustomTag(t *testing.T) {
+	type resource struct {
+		Channel string `validate:"channel"`
+	}
+
+	v := NewDefaultValidator(WithValidation("channel", func(fl FieldLevel) bool {
+		return strings.HasPrefix(fl.Field().String(), "#")
+	}, "Channels must start with #"))
+
+	require.NoError(t, v.Validate(&resource{Channel: "#general"}))
+	require.ErrorContains(t, v.Validate(&resource{Channel: "general"}), "Channels must start with #")
+
+	_, err := NewDefaultValidatorE(WithValidation("", nil, ""))
+	require.ErrorContains(t, err, "validator: register")
+	require.Panics(t, func() { NewDefaultValidator(WithValidation("", nil, "")) })
+}
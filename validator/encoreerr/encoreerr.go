@@ -0,0 +1,52 @@
+// Copyright 2022-present Wakflo
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package encoreerr adapts validator errors to encore.dev API errors. It is
+// kept separate so integrations that only use the validator do not link the
+// encore runtime.
+package encoreerr
+
+import (
+	"errors"
+	"strings"
+
+	"encore.dev/beta/errs"
+	"github.com/wakflo/go-sdk/validator"
+)
+
+// FromValidation converts validator.ValidationErrors into an InvalidArgument
+// API error with one metadata entry per field. Other errors are returned
+// unchanged.
+func FromValidation(err error) error {
+	var valErrs validator.ValidationErrors
+	if !errors.As(err, &valErrs) {
+		return err
+	}
+
+	if len(valErrs) == 0 {
+		return nil
+	}
+
+	apiError := errs.B().Code(errs.InvalidArgument).Msg("Validation Error")
+	for _, obj := range valErrs {
+		apiError = apiError.Meta(strings.ToLower(obj.Field), obj.Message)
+	}
+
+	return apiError.Err()
+}
+
+// ValidateAPI validates s with v and returns any failure as an API error.
+func ValidateAPI(v validator.Validator, s interface{}) error {
+	return FromValidation(v.Validate(s))
+}
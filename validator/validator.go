@@ -27,6 +27,9 @@ import (
 
 var NameRegex = regexp.MustCompile("^[a-zA-Z0-9\\.\\-_]+$") //nolint:gosimple
 
+// ActionIDRegex matches action IDs of the form <integrationId>:<verb>.
+var ActionIDRegex = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+:[a-zA-Z0-9.\-_]+$`)
+
 var CronRegex = regexp.MustCompile(`(@(annually|yearly|monthly|weekly|daily|hourly|reboot))|(@every (\d+(ns|us|µs|ms|s|m|h))+)|((((\d+,)+\d+|(\d+(\/|-)\d+)|\d+|\*) ?){5,7})`) //nolint:gosimple
 
 func newValidator() *validator.Validate {
@@ -48,14 +51,9 @@ func newValidator() *validator.Validate {
 		return CronRegex.MatchString(fl.Field().String())
 	})
 
-	// _ = validate.RegisterValidation("actionId", func(fl validator.FieldLevel) bool {
-	//	action, err := types.ParseActionID(fl.Field().String())
-	//	if err != nil {
-	//		return false
-	//	}
-	//
-	//	return action.Service != "" && action.Verb != ""
-	// })
+	_ = validate.RegisterValidation("actionid", func(fl validator.FieldLevel) bool {
+		return ActionIDRegex.MatchString(fl.Field().String())
+	})
 
 	_ = validate.RegisterValidation("semver", func(fl validator.FieldLevel) bool {
 		_, err := semver.NewVersion(fl.Field().String())